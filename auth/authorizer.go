@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var (
+	ErrUnauthenticated = errors.New("api key is not valid")
+	ErrForbidden       = errors.New("role is not allowed to perform this operation")
+	ErrNoUser          = errors.New("api key is not bound to a user")
+	ErrNotOwner        = errors.New("api key is not bound to this user")
+)
+
+// Caller is who presents the API key of a request.
+type Caller struct {
+	Role Role
+	// UserID is the user the key is bound to, 0 for callers without a key and for
+	// keys that are not bound to a user.
+	UserID int
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx that carries caller.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the caller Authorize stored in ctx.
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
+
+// UserID returns the user the caller of ctx acts as, or ErrNoUser.
+func UserID(ctx context.Context) (int, error) {
+	caller, ok := CallerFrom(ctx)
+	if !ok || caller.UserID == 0 {
+		return 0, ErrNoUser
+	}
+	return caller.UserID, nil
+}
+
+// CheckUser returns nil when the caller of ctx acts as userID or is an admin, who may
+// act on behalf of every user, and ErrNotOwner otherwise.
+func CheckUser(ctx context.Context, userID int) error {
+	caller, _ := CallerFrom(ctx)
+	if caller.Role == RoleAdmin || (caller.UserID != 0 && caller.UserID == userID) {
+		return nil
+	}
+	return ErrNotOwner
+}
+
+type Authorizer struct {
+	policy *Policy
+}
+
+func NewAuthorizer(policy *Policy) *Authorizer {
+	return &Authorizer{policy: policy}
+}
+
+// APIKey returns the key sent in the X-API-Key header or as an "Authorization: Bearer" token.
+func APIKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+
+	if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimPrefix(authorization, "Bearer ")
+	}
+	return ""
+}
+
+func (a *Authorizer) RoleOf(r *http.Request) (Role, error) {
+	return a.RoleOfKey(APIKey(r))
+}
+
+// RoleOfKey is RoleOf for a key that came some other way than in an HTTP request,
+// such as in gRPC metadata. An empty key gets the default role.
+func (a *Authorizer) RoleOfKey(key string) (Role, error) {
+	if key == "" {
+		return a.policy.DefaultRole, nil
+	}
+
+	role, ok := a.policy.APIKeys[key]
+	if !ok {
+		return RoleNone, ErrUnauthenticated
+	}
+	return role, nil
+}
+
+// Check returns nil when the caller of r may perform op, and ErrUnauthenticated or
+// ErrForbidden otherwise.
+func (a *Authorizer) Check(r *http.Request, op Operation) error {
+	return a.CheckKey(APIKey(r), op)
+}
+
+// CheckKey is Check for the caller presenting key.
+func (a *Authorizer) CheckKey(key string, op Operation) error {
+	_, err := a.authorizeKey(key, op)
+	return err
+}
+
+func (a *Authorizer) authorizeKey(key string, op Operation) (Caller, error) {
+	role, err := a.RoleOfKey(key)
+	if err != nil {
+		return Caller{}, err
+	}
+
+	if !role.Allows(a.policy.RequiredRole(op)) {
+		if role == RoleNone {
+			return Caller{}, ErrUnauthenticated
+		}
+		return Caller{}, ErrForbidden
+	}
+
+	caller := Caller{Role: role}
+	if key != "" {
+		caller.UserID = a.policy.Users[key]
+	}
+	return caller, nil
+}
+
+// Authorize wraps next so that it only runs for callers whose role satisfies op, next
+// finds the caller with CallerFrom.
+func (a *Authorizer) Authorize(op Operation, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		caller, err := a.authorizeKey(APIKey(r), op)
+		if err != nil {
+			status := http.StatusForbidden
+			if errors.Is(err, ErrUnauthenticated) {
+				status = http.StatusUnauthorized
+			}
+			problem.Write(w, r, status, err.Error())
+			return
+		}
+
+		next(w, r.WithContext(WithCaller(r.Context(), caller)), ps)
+	}
+}
@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/handler"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestRouter(ms service.IMovieService) http.Handler {
+	policy := auth.DefaultPolicy()
+	policy.APIKeys["editor-key"] = auth.RoleEditor
+	policy.APIKeys["admin-key"] = auth.RoleAdmin
+	a := auth.NewAuthorizer(policy)
+	mh := handler.NewMovieHandler(ms, logging.NewNop())
+
+	router := httprouter.New()
+	router.GET("/movies", a.Authorize(auth.OpGetMovies, mh.GetMovies))
+	router.DELETE("/movies", a.Authorize(auth.OpDeleteAllMovie, mh.DeleteAllMovies))
+	router.DELETE("/movies/:id", a.Authorize(auth.OpDeleteMovie, mh.DeleteMovie))
+	return router
+}
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	t.Run("Reader can get movies without a key", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return([]model.Movie{}, nil).Times(1)
+
+		req, _ := http.NewRequest(http.MethodGet, "/movies", http.NoBody)
+		rec := httptest.NewRecorder()
+		newTestRouter(mockService).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Unknown key - Unauthorized", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Times(0)
+
+		req, _ := http.NewRequest(http.MethodGet, "/movies", http.NoBody)
+		req.Header.Set(auth.APIKeyHeader, "wrong-key")
+		rec := httptest.NewRecorder()
+		newTestRouter(mockService).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("Editor cannot delete - Forbidden", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().DeleteMovie(gomock.Any(), gomock.Any()).Times(0)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/movies/1", http.NoBody)
+		req.Header.Set(auth.APIKeyHeader, "editor-key")
+		rec := httptest.NewRecorder()
+		newTestRouter(mockService).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Admin can delete all movies with a bearer token", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().DeleteAllMovie(gomock.Any()).Return(nil).Times(1)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/movies", http.NoBody)
+		req.Header.Set("Authorization", "Bearer admin-key")
+		rec := httptest.NewRecorder()
+		newTestRouter(mockService).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
+
+func TestAuthorizer_Caller(t *testing.T) {
+	policy := auth.DefaultPolicy()
+	policy.APIKeys["user-key"] = auth.RoleUser
+	policy.Users["user-key"] = 42
+	a := auth.NewAuthorizer(policy)
+
+	var caller auth.Caller
+	rate := a.Authorize(auth.OpRateMovie, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		caller, _ = auth.CallerFrom(r.Context())
+	})
+
+	t.Run("Rating without a key - Forbidden", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/ratings", http.NoBody)
+		rec := httptest.NewRecorder()
+		rate(rec, req, nil)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Caller carries the user of the key", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/ratings", http.NoBody)
+		req.Header.Set(auth.APIKeyHeader, "user-key")
+		rec := httptest.NewRecorder()
+		rate(rec, req, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, auth.Caller{Role: auth.RoleUser, UserID: 42}, caller)
+	})
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// Operation names mirror the methods of service.IMovieService,
+// service.IPersonService, service.IRatingService, service.IWatchlistService,
+// service.IRecommendationService and service.IWebhookService.
+type Operation string
+
+const (
+	OpGetMovies      Operation = "GetMovies"
+	OpGetMovie       Operation = "GetMovie"
+	OpGetMovieStats  Operation = "GetMovieStats"
+	OpGetMovieEvents Operation = "GetMovieEvents"
+	OpCreateMovie    Operation = "CreateMovie"
+	OpUpdateMovie    Operation = "UpdateMovie"
+	OpDeleteMovie    Operation = "DeleteMovie"
+	OpDeleteAllMovie Operation = "DeleteAllMovie"
+	OpMergeMovies    Operation = "MergeMovies"
+
+	OpGetPeople       Operation = "GetPeople"
+	OpGetPerson       Operation = "GetPerson"
+	OpCreatePerson    Operation = "CreatePerson"
+	OpUpdatePerson    Operation = "UpdatePerson"
+	OpDeletePerson    Operation = "DeletePerson"
+	OpGetMovieCredits Operation = "GetMovieCredits"
+	OpGetPersonMovies Operation = "GetPersonMovies"
+	OpAddCredit       Operation = "AddCredit"
+	OpDeleteCredit    Operation = "DeleteCredit"
+
+	OpGetRatings   Operation = "GetRatings"
+	OpRateMovie    Operation = "RateMovie"
+	OpDeleteRating Operation = "DeleteRating"
+	OpGetReviews   Operation = "GetReviews"
+	OpAddReview    Operation = "AddReview"
+	OpDeleteReview Operation = "DeleteReview"
+
+	OpGetWatchlist        Operation = "GetWatchlist"
+	OpAddToWatchlist      Operation = "AddToWatchlist"
+	OpRemoveFromWatchlist Operation = "RemoveFromWatchlist"
+	OpReorderWatchlist    Operation = "ReorderWatchlist"
+	OpGetWatched          Operation = "GetWatched"
+	OpAddWatched          Operation = "AddWatched"
+	OpDeleteWatched       Operation = "DeleteWatched"
+
+	OpGetSimilarMovies   Operation = "GetSimilarMovies"
+	OpGetRecommendations Operation = "GetRecommendations"
+
+	OpGetWebhooks       Operation = "GetWebhooks"
+	OpGetWebhook        Operation = "GetWebhook"
+	OpCreateWebhook     Operation = "CreateWebhook"
+	OpUpdateWebhook     Operation = "UpdateWebhook"
+	OpDeleteWebhook     Operation = "DeleteWebhook"
+	OpGetDeliveries     Operation = "GetDeliveries"
+	OpRedeliverDelivery Operation = "RedeliverDelivery"
+)
+
+type Policy struct {
+	// DefaultRole is granted to callers that do not present an API key.
+	DefaultRole Role               `json:"default_role"`
+	APIKeys     map[string]Role    `json:"api_keys"`
+	Operations  map[Operation]Role `json:"operations"`
+	// Users binds API keys to the id of the user they rate, review and keep
+	// watchlists as.
+	Users map[string]int `json:"users"`
+}
+
+func DefaultPolicy() *Policy {
+	return &Policy{
+		DefaultRole: RoleReader,
+		APIKeys:     map[string]Role{},
+		Users:       map[string]int{},
+		Operations: map[Operation]Role{
+			OpGetMovies:      RoleReader,
+			OpGetMovie:       RoleReader,
+			OpGetMovieStats:  RoleReader,
+			OpGetMovieEvents: RoleReader,
+			OpCreateMovie:    RoleEditor,
+			OpUpdateMovie:    RoleEditor,
+			OpDeleteMovie:    RoleAdmin,
+			OpDeleteAllMovie: RoleAdmin,
+			OpMergeMovies:    RoleAdmin,
+
+			OpGetPeople:       RoleReader,
+			OpGetPerson:       RoleReader,
+			OpCreatePerson:    RoleEditor,
+			OpUpdatePerson:    RoleEditor,
+			OpDeletePerson:    RoleAdmin,
+			OpGetMovieCredits: RoleReader,
+			OpGetPersonMovies: RoleReader,
+			OpAddCredit:       RoleEditor,
+			OpDeleteCredit:    RoleEditor,
+
+			// Users rate and review as themselves, editors moderate.
+			OpGetRatings:   RoleReader,
+			OpRateMovie:    RoleUser,
+			OpDeleteRating: RoleEditor,
+			OpGetReviews:   RoleReader,
+			OpAddReview:    RoleUser,
+			OpDeleteReview: RoleEditor,
+
+			// Users keep their own watchlists, see auth.CheckUser.
+			OpGetWatchlist:        RoleUser,
+			OpAddToWatchlist:      RoleUser,
+			OpRemoveFromWatchlist: RoleUser,
+			OpReorderWatchlist:    RoleUser,
+			OpGetWatched:          RoleUser,
+			OpAddWatched:          RoleUser,
+			OpDeleteWatched:       RoleUser,
+
+			OpGetSimilarMovies:   RoleReader,
+			OpGetRecommendations: RoleReader,
+
+			// Webhooks see every change of the catalog, only admins manage them.
+			OpGetWebhooks:       RoleAdmin,
+			OpGetWebhook:        RoleAdmin,
+			OpCreateWebhook:     RoleAdmin,
+			OpUpdateWebhook:     RoleAdmin,
+			OpDeleteWebhook:     RoleAdmin,
+			OpGetDeliveries:     RoleAdmin,
+			OpRedeliverDelivery: RoleAdmin,
+		},
+	}
+}
+
+// LoadPolicy reads a JSON policy file. Operations the file does not mention keep
+// the requirements of DefaultPolicy.
+func LoadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// DefaultRole is a pointer so that a file without default_role keeps the default
+	// rather than falling back to RoleNone.
+	var fromFile struct {
+		Policy
+		DefaultRole *Role `json:"default_role"`
+	}
+	if err := json.NewDecoder(f).Decode(&fromFile); err != nil {
+		return nil, err
+	}
+
+	policy := DefaultPolicy()
+	if fromFile.DefaultRole != nil {
+		policy.DefaultRole = *fromFile.DefaultRole
+	}
+	for key, role := range fromFile.APIKeys {
+		policy.APIKeys[key] = role
+	}
+	for key, userID := range fromFile.Users {
+		policy.Users[key] = userID
+	}
+	for op, role := range fromFile.Operations {
+		policy.Operations[op] = role
+	}
+
+	return policy, nil
+}
+
+// RequiredRole returns the role needed for op. Unknown operations require admin.
+func (p *Policy) RequiredRole(op Operation) Role {
+	if role, ok := p.Operations[op]; ok {
+		return role
+	}
+	return RoleAdmin
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("Error - unknown role", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		os.WriteFile(path, []byte(`{"default_role": "superuser"}`), 0o600)
+
+		_, err := LoadPolicy(path)
+
+		assert.ErrorIs(t, err, ErrUnknownRole)
+	})
+	t.Run("Success - file overrides defaults", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		os.WriteFile(path, []byte(`{
+			"default_role": "none",
+			"api_keys": {"k1": "editor"},
+			"operations": {"DeleteMovie": "editor"}
+		}`), 0o600)
+
+		policy, err := LoadPolicy(path)
+
+		assert.Nil(t, err)
+		assert.Equal(t, RoleNone, policy.DefaultRole)
+		assert.Equal(t, RoleEditor, policy.APIKeys["k1"])
+		assert.Equal(t, RoleEditor, policy.RequiredRole(OpDeleteMovie))
+		assert.Equal(t, RoleAdmin, policy.RequiredRole(OpDeleteAllMovie))
+		assert.Equal(t, RoleReader, policy.RequiredRole(OpGetMovies))
+	})
+	t.Run("Success - default role is kept without default_role", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		os.WriteFile(path, []byte(`{"api_keys": {"k1": "editor"}}`), 0o600)
+
+		policy, err := LoadPolicy(path)
+
+		assert.Nil(t, err)
+		assert.Equal(t, RoleReader, policy.DefaultRole)
+		assert.Equal(t, RoleEditor, policy.APIKeys["k1"])
+	})
+	t.Run("Success - example policy", func(t *testing.T) {
+		_, err := LoadPolicy("../config/policy.example.json")
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownRole = errors.New("unknown role")
+)
+
+// Role is ordered: every role is granted everything the roles below it are.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleReader
+	// RoleUser is meant for API keys bound to a user in Policy.Users, callers without
+	// a key never act as a user.
+	RoleUser
+	RoleEditor
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:   "none",
+	RoleReader: "reader",
+	RoleUser:   "user",
+	RoleEditor: "editor",
+	RoleAdmin:  "admin",
+}
+
+func ParseRole(s string) (Role, error) {
+	for role, name := range roleNames {
+		if strings.EqualFold(s, name) {
+			return role, nil
+		}
+	}
+	return RoleNone, ErrUnknownRole
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Role) UnmarshalText(text []byte) error {
+	role, err := ParseRole(string(text))
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
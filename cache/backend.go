@@ -0,0 +1,15 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// IBackend stores serialized values. The in-process LRU is the default; a shared store
+// such as Redis can be plugged in by implementing the same methods.
+type IBackend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	Clear(ctx context.Context) error
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLRUBackend(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Evicts the least recently used entry", func(t *testing.T) {
+		lru := NewLRUBackend(2)
+		lru.Set(ctx, "a", []byte("1"), time.Minute)
+		lru.Set(ctx, "b", []byte("2"), time.Minute)
+		lru.Get(ctx, "a")
+		lru.Set(ctx, "c", []byte("3"), time.Minute)
+
+		_, ok, _ := lru.Get(ctx, "b")
+		assert.False(t, ok)
+
+		value, ok, _ := lru.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+	})
+	t.Run("Entries expire after their ttl", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		lru := NewLRUBackend(2)
+		lru.now = func() time.Time { return now }
+		lru.Set(ctx, "a", []byte("1"), time.Second)
+
+		_, ok, _ := lru.Get(ctx, "a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Second)
+		_, ok, _ = lru.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, lru.order.Len())
+	})
+	t.Run("Delete and Clear", func(t *testing.T) {
+		lru := NewLRUBackend(3)
+		lru.Set(ctx, "a", []byte("1"), time.Minute)
+		lru.Set(ctx, "b", []byte("2"), time.Minute)
+		lru.Set(ctx, "c", []byte("3"), time.Minute)
+
+		lru.Delete(ctx, "a", "missing")
+		_, ok, _ := lru.Get(ctx, "a")
+		assert.False(t, ok)
+
+		lru.Clear(ctx)
+		_, ok, _ = lru.Get(ctx, "b")
+		assert.False(t, ok)
+		assert.Equal(t, 0, len(lru.items))
+	})
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"golang.org/x/sync/singleflight"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const moviesKey = "movies"
+
+func movieKey(id int) string {
+	return "movie:" + strconv.Itoa(id)
+}
+
+type cachedMovieService struct {
+	next    service.IMovieService
+	backend IBackend
+	ttl     time.Duration
+	group   singleflight.Group
+	logger  *slog.Logger
+
+	// generations counts the invalidations of each key and epoch the clears of the
+	// whole cache. A load only caches its value when neither moved while it ran, so
+	// that a read racing with a write never caches what it read before the write.
+	mu          sync.Mutex
+	generations map[string]uint64
+	epoch       uint64
+}
+
+type generation struct {
+	epoch uint64
+	key   uint64
+}
+
+// NewMovieService decorates next with a read-through cache. Reads are served from
+// backend for up to ttl, and writes invalidate the entries they affect.
+func NewMovieService(next service.IMovieService, backend IBackend, ttl time.Duration, logger *slog.Logger) *cachedMovieService {
+	return &cachedMovieService{
+		next:        next,
+		backend:     backend,
+		ttl:         ttl,
+		logger:      logger,
+		generations: make(map[string]uint64),
+	}
+}
+
+// GetMovies only caches the unfiltered list. Filtered lists are read from next, as
+// a write could not tell which of them it affects.
+func (c *cachedMovieService) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+	if !filter.IsZero() {
+		return c.next.GetMovies(ctx, filter)
+	}
+
+	var movies []model.Movie
+	err := c.readThrough(ctx, moviesKey, &movies, func(ctx context.Context) (interface{}, error) {
+		return c.next.GetMovies(ctx, filter)
+	})
+	return movies, err
+}
+
+// StreamMovies bypasses the cache: holding the whole catalog would defeat streaming.
+func (c *cachedMovieService) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error {
+	return c.next.StreamMovies(ctx, filter, fn)
+}
+
+func (c *cachedMovieService) GetMovie(ctx context.Context, id int) (model.Movie, error) {
+	var movie model.Movie
+	err := c.readThrough(ctx, movieKey(id), &movie, func(ctx context.Context) (interface{}, error) {
+		return c.next.GetMovie(ctx, id)
+	})
+	return movie, err
+}
+
+// GetMovieStats is not cached, the repository aggregates the statistics.
+func (c *cachedMovieService) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	return c.next.GetMovieStats(ctx, filter, topN)
+}
+
+func (c *cachedMovieService) CreateMovie(ctx context.Context, movie model.Movie) ([]model.Duplicate, error) {
+	duplicates, err := c.next.CreateMovie(ctx, movie)
+	c.invalidate(ctx, moviesKey)
+	return duplicates, err
+}
+
+func (c *cachedMovieService) DeleteMovie(ctx context.Context, id int) error {
+	err := c.next.DeleteMovie(ctx, id)
+	c.invalidate(ctx, moviesKey, movieKey(id))
+	return err
+}
+
+func (c *cachedMovieService) DeleteAllMovie(ctx context.Context) error {
+	err := c.next.DeleteAllMovie(ctx)
+
+	c.mu.Lock()
+	c.epoch++
+	c.mu.Unlock()
+	if clearErr := c.backend.Clear(ctx); clearErr != nil {
+		c.logger.ErrorContext(ctx, "clearing movie cache", "error", clearErr)
+	}
+	return err
+}
+
+func (c *cachedMovieService) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
+	err := c.next.UpdateMovie(ctx, id, movie)
+	c.invalidate(ctx, moviesKey, movieKey(id))
+	return err
+}
+
+func (c *cachedMovieService) MergeMovies(ctx context.Context, targetID int, sourceID int) (model.Movie, error) {
+	movie, err := c.next.MergeMovies(ctx, targetID, sourceID)
+	c.invalidate(ctx, moviesKey, movieKey(targetID), movieKey(sourceID))
+	return movie, err
+}
+
+// readThrough decodes the cached value of key into dst. On a miss, concurrent callers
+// for the same key share a single call to load, whose result is cached unless it fails
+// or key is invalidated meanwhile, even while it is being cached. load runs without the cancellation of ctx, so that
+// a caller giving up does not fail the others; it only stops waiting.
+func (c *cachedMovieService) readThrough(ctx context.Context, key string, dst interface{}, load func(ctx context.Context) (interface{}, error)) error {
+	cached, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "reading movie cache", "key", key, "error", err)
+	}
+	if ok {
+		return json.Unmarshal(cached, dst)
+	}
+
+	loadCtx := context.WithoutCancel(ctx)
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		before := c.generation(key)
+
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.generation(key) != before {
+			return encoded, nil
+		}
+		if err := c.backend.Set(loadCtx, key, encoded, c.ttl); err != nil {
+			c.logger.ErrorContext(loadCtx, "writing movie cache", "key", key, "error", err)
+		}
+		// An invalidation between the check above and Set may have deleted key before
+		// Set wrote it, so the value is dropped when the generation moved meanwhile. An
+		// invalidation after this second check deletes key after Set by itself.
+		if c.generation(key) != before {
+			if err := c.backend.Delete(loadCtx, key); err != nil {
+				c.logger.ErrorContext(loadCtx, "invalidating movie cache", "keys", []string{key}, "error", err)
+			}
+		}
+		return encoded, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return res.Err
+		}
+		return json.Unmarshal(res.Val.([]byte), dst)
+	}
+}
+
+func (c *cachedMovieService) generation(key string) generation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return generation{epoch: c.epoch, key: c.generations[key]}
+}
+
+// InvalidateMovie drops the cached movie and the list, for writes that change a movie
+// without going through this service.
+func (c *cachedMovieService) InvalidateMovie(ctx context.Context, movieID int) {
+	c.invalidate(ctx, moviesKey, movieKey(movieID))
+}
+
+// invalidate runs whether or not the write succeeded: a failed write may still have
+// partially changed the repository.
+func (c *cachedMovieService) invalidate(ctx context.Context, keys ...string) {
+	c.mu.Lock()
+	for _, key := range keys {
+		c.generations[key]++
+	}
+	c.mu.Unlock()
+
+	for _, key := range keys {
+		c.group.Forget(key)
+	}
+
+	if err := c.backend.Delete(ctx, keys...); err != nil {
+		c.logger.ErrorContext(ctx, "invalidating movie cache", "keys", keys, "error", err)
+	}
+}
@@ -0,0 +1,253 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestService(next service.IMovieService) *cachedMovieService {
+	return NewMovieService(next, NewLRUBackend(10), time.Minute, logging.NewNop())
+}
+
+// racingBackend runs beforeSet ahead of every Set, to race a write with a load that
+// is about to cache its value.
+type racingBackend struct {
+	IBackend
+	beforeSet func()
+}
+
+func (r racingBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	r.beforeSet()
+	return r.IBackend.Set(ctx, key, value, ttl)
+}
+
+func TestCachedMovieService_GetMovies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Second read is served from cache", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return([]model.Movie{{ID: 1, Title: "Film"}}, nil).Times(1)
+
+		cs := newTestService(mockService)
+		cs.GetMovies(ctx, model.MovieFilter{})
+		movies, err := cs.GetMovies(ctx, model.MovieFilter{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []model.Movie{{ID: 1, Title: "Film"}}, movies)
+	})
+	t.Run("Filtered reads bypass the cache", func(t *testing.T) {
+		filter := model.MovieFilter{Genre: "drama"}
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), filter).Return([]model.Movie{{ID: 1, Title: "Film"}}, nil).Times(2)
+
+		cs := newTestService(mockService)
+		cs.GetMovies(ctx, filter)
+		movies, err := cs.GetMovies(ctx, filter)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []model.Movie{{ID: 1, Title: "Film"}}, movies)
+	})
+	t.Run("Errors are not cached", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{}, service.ErrMovieNotFound).Times(2)
+
+		cs := newTestService(mockService)
+		_, err := cs.GetMovie(ctx, 1)
+		assert.ErrorIs(t, err, service.ErrMovieNotFound)
+		_, err = cs.GetMovie(ctx, 1)
+		assert.ErrorIs(t, err, service.ErrMovieNotFound)
+	})
+	t.Run("Concurrent misses share one call", func(t *testing.T) {
+		release := make(chan struct{})
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetMovies(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+				<-release
+				return []model.Movie{{ID: 1}}, nil
+			}).
+			Times(1)
+
+		cs := newTestService(mockService)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				movies, err := cs.GetMovies(ctx, model.MovieFilter{})
+				assert.Nil(t, err)
+				assert.Len(t, movies, 1)
+			}()
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+	})
+}
+
+func TestCachedMovieService_Invalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("A read in flight during a write is not cached", func(t *testing.T) {
+		loading := make(chan struct{})
+		release := make(chan struct{})
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		gomock.InOrder(
+			mockService.
+				EXPECT().
+				GetMovie(gomock.Any(), 1).
+				DoAndReturn(func(ctx context.Context, id int) (model.Movie, error) {
+					close(loading)
+					<-release
+					return model.Movie{ID: 1, Title: "Before"}, nil
+				}),
+			mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1, Title: "After"}, nil),
+		)
+		mockService.EXPECT().UpdateMovie(gomock.Any(), 1, gomock.Any()).Return(nil).Times(1)
+
+		cs := newTestService(mockService)
+		read := make(chan model.Movie)
+		go func() {
+			movie, _ := cs.GetMovie(ctx, 1)
+			read <- movie
+		}()
+
+		<-loading
+		cs.UpdateMovie(ctx, 1, model.Movie{Title: "After"})
+		close(release)
+		assert.Equal(t, "Before", (<-read).Title)
+
+		movie, err := cs.GetMovie(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "After", movie.Title)
+	})
+	t.Run("A write between the check and the Set of a read is not cached", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		gomock.InOrder(
+			mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1, Title: "Before"}, nil),
+			mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1, Title: "After"}, nil),
+		)
+
+		var cs *cachedMovieService
+		raced := false
+		backend := racingBackend{IBackend: NewLRUBackend(10), beforeSet: func() {
+			if !raced {
+				raced = true
+				cs.InvalidateMovie(ctx, 1)
+			}
+		}}
+		cs = NewMovieService(mockService, backend, time.Minute, logging.NewNop())
+
+		movie, err := cs.GetMovie(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Before", movie.Title)
+
+		movie, err = cs.GetMovie(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "After", movie.Title)
+	})
+	t.Run("A cancelled caller does not fail the others", func(t *testing.T) {
+		loading := make(chan struct{})
+		release := make(chan struct{})
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetMovie(gomock.Any(), 1).
+			DoAndReturn(func(ctx context.Context, id int) (model.Movie, error) {
+				close(loading)
+				<-release
+				return model.Movie{ID: 1}, ctx.Err()
+			}).
+			Times(1)
+
+		cs := newTestService(mockService)
+		cancelled, cancel := context.WithCancel(ctx)
+		first := make(chan error)
+		go func() {
+			_, err := cs.GetMovie(cancelled, 1)
+			first <- err
+		}()
+		<-loading
+
+		second := make(chan error)
+		go func() {
+			_, err := cs.GetMovie(ctx, 1)
+			second <- err
+		}()
+
+		cancel()
+		assert.ErrorIs(t, <-first, context.Canceled)
+		close(release)
+		assert.Nil(t, <-second)
+	})
+
+	t.Run("CreateMovie invalidates the list", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return([]model.Movie{}, nil).Times(2)
+		mockService.EXPECT().CreateMovie(gomock.Any(), model.Movie{Title: "New"}).Return(nil, nil).Times(1)
+
+		cs := newTestService(mockService)
+		cs.GetMovies(ctx, model.MovieFilter{})
+		cs.CreateMovie(ctx, model.Movie{Title: "New"})
+		cs.GetMovies(ctx, model.MovieFilter{})
+	})
+	t.Run("UpdateMovie and DeleteMovie invalidate the movie and the list", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return([]model.Movie{}, nil).Times(3)
+		mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1}, nil).Times(3)
+		mockService.EXPECT().GetMovie(gomock.Any(), 2).Return(model.Movie{ID: 2}, nil).Times(1)
+		mockService.EXPECT().UpdateMovie(gomock.Any(), 1, gomock.Any()).Return(nil).Times(1)
+		mockService.EXPECT().DeleteMovie(gomock.Any(), 1).Return(errors.New("oops!")).Times(1)
+
+		cs := newTestService(mockService)
+		for _, write := range []func(){
+			func() {},
+			func() { cs.UpdateMovie(ctx, 1, model.Movie{Title: "Updated"}) },
+			func() { cs.DeleteMovie(ctx, 1) },
+		} {
+			write()
+			cs.GetMovies(ctx, model.MovieFilter{})
+			cs.GetMovie(ctx, 1)
+			cs.GetMovie(ctx, 2)
+		}
+	})
+	t.Run("DeleteAllMovie clears everything", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovie(gomock.Any(), 2).Return(model.Movie{ID: 2}, nil).Times(2)
+		mockService.EXPECT().DeleteAllMovie(gomock.Any()).Return(nil).Times(1)
+
+		cs := newTestService(mockService)
+		cs.GetMovie(ctx, 2)
+		cs.DeleteAllMovie(ctx)
+		cs.GetMovie(ctx, 2)
+	})
+	t.Run("MergeMovies invalidates both movies and the list", func(t *testing.T) {
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return([]model.Movie{}, nil).Times(2)
+		mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1}, nil).Times(2)
+		mockService.EXPECT().GetMovie(gomock.Any(), 2).Return(model.Movie{ID: 2}, nil).Times(2)
+		mockService.EXPECT().MergeMovies(gomock.Any(), 1, 2).Return(model.Movie{ID: 1}, nil).Times(1)
+
+		cs := newTestService(mockService)
+		for _, write := range []func(){
+			func() {},
+			func() { cs.MergeMovies(ctx, 1, 2) },
+		} {
+			write()
+			cs.GetMovies(ctx, model.MovieFilter{})
+			cs.GetMovie(ctx, 1)
+			cs.GetMovie(ctx, 2)
+		}
+	})
+}
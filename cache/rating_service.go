@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+)
+
+// IMovieInvalidator drops the cached entries of a movie, NewMovieService implements
+// it.
+type IMovieInvalidator interface {
+	InvalidateMovie(ctx context.Context, movieID int)
+}
+
+type invalidatingRatingService struct {
+	service.IRatingService
+	movies IMovieInvalidator
+}
+
+// NewRatingService decorates next so that rating writes invalidate the movie entries
+// NewMovieService caches, as they change the movie's score. Going through movies
+// rather than the backend keeps a read racing with the write from caching the old
+// score.
+func NewRatingService(next service.IRatingService, movies IMovieInvalidator) *invalidatingRatingService {
+	return &invalidatingRatingService{IRatingService: next, movies: movies}
+}
+
+func (c *invalidatingRatingService) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	summary, err := c.IRatingService.RateMovie(ctx, rating)
+	c.movies.InvalidateMovie(ctx, rating.MovieID)
+	return summary, err
+}
+
+func (c *invalidatingRatingService) DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error) {
+	summary, err := c.IRatingService.DeleteRating(ctx, movieID, userID)
+	c.movies.InvalidateMovie(ctx, movieID)
+	return summary, err
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInvalidatingRatingService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RateMovie invalidates the movie and the list", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		mockMovieService := service.NewMockIMovieService(controller)
+		mockMovieService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1, Score: 9.3}, nil).Times(1)
+		mockMovieService.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1, Score: 7.25}, nil).Times(1)
+		mockRatingService := service.NewMockIRatingService(controller)
+		mockRatingService.EXPECT().RateMovie(gomock.Any(), gomock.Any()).Return(model.RatingSummary{}, nil).Times(1)
+
+		cms := NewMovieService(mockMovieService, NewLRUBackend(10), time.Minute, logging.NewNop())
+		crs := NewRatingService(mockRatingService, cms)
+
+		cms.GetMovie(ctx, 1)
+		crs.RateMovie(ctx, model.Rating{MovieID: 1, UserID: 1, Value: 5})
+		movie, err := cms.GetMovie(ctx, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 7.25, movie.Score)
+	})
+	t.Run("Reads are passed through", func(t *testing.T) {
+		mockRatingService := service.NewMockIRatingService(gomock.NewController(t))
+		mockRatingService.EXPECT().GetRatings(gomock.Any(), 1).Return([]model.Rating{{MovieID: 1, UserID: 1, Value: 5}}, nil).Times(1)
+
+		crs := NewRatingService(mockRatingService, newTestService(nil))
+		ratings, err := crs.GetRatings(ctx, 1)
+
+		assert.Nil(t, err)
+		assert.Len(t, ratings, 1)
+	})
+}
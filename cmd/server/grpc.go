@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/grpcapi"
+	moviev1 "github.com/dilaragorum/movie-go/proto/movie/v1"
+	"github.com/dilaragorum/movie-go/ratelimit"
+	"github.com/dilaragorum/movie-go/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// newGRPCServer serves the MovieService along with the standard health and reflection
+// services, the returned health server reports the MovieService as serving. The calls
+// are counted by limiter along with the HTTP requests.
+func newGRPCServer(ms service.IMovieService, authorizer *auth.Authorizer, limiter *ratelimit.Limiter, logger *slog.Logger) (*grpc.Server, *health.Server) {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcapi.UnaryRateLimitInterceptor(limiter, logger), grpcapi.UnaryInterceptor(authorizer, logger)),
+		grpc.ChainStreamInterceptor(grpcapi.StreamRateLimitInterceptor(limiter, logger), grpcapi.StreamInterceptor(authorizer, logger)),
+	)
+	moviev1.RegisterMovieServiceServer(srv, grpcapi.NewMovieServer(ms, logger))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(moviev1.MovieService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+
+	reflection.Register(srv)
+	return srv, healthServer
+}
+
+// serveGRPC runs srv on ln until ctx is cancelled, then reports NOT_SERVING to the
+// health checks and waits up to timeout for in-flight calls before closing the
+// remaining ones, like serve does for the HTTP server.
+func serveGRPC(ctx context.Context, srv *grpc.Server, healthServer *health.Server, ln net.Listener, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	healthServer.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		srv.Stop()
+	}
+	return <-serveErr
+}
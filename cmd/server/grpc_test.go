@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/ratelimit"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServeGRPC(t *testing.T) {
+	t.Run("Health reports serving until shutdown", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+		limiter := ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.DefaultConfig(), authorizer, logging.NewNop())
+		srv, healthServer := newGRPCServer(ms, authorizer, limiter, logging.NewNop())
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- serveGRPC(ctx, srv, healthServer, ln, 5*time.Second)
+		}()
+
+		conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		assert.Nil(t, err)
+		defer conn.Close()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "movie.v1.MovieService"})
+		assert.Nil(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+		cancel()
+
+		select {
+		case err := <-serveErr:
+			assert.Nil(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("serveGRPC did not return after shutdown")
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runEvery runs job right away and then every interval until ctx is cancelled. A
+// failing run is logged and retried at the next tick.
+func runEvery(ctx context.Context, logger *slog.Logger, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil && ctx.Err() == nil {
+			logger.ErrorContext(ctx, "background job failed", "job", name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
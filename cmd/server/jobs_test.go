@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/logging"
+	"testing"
+	"time"
+)
+
+func TestRunEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan struct{}, 10)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runEvery(ctx, logging.NewNop(), "test", time.Millisecond, func(ctx context.Context) error {
+			runs <- struct{}{}
+			return errors.New("oops!")
+		})
+	}()
+
+	// A failing run does not stop the next ones.
+	<-runs
+	<-runs
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runEvery did not return after cancellation")
+	}
+}
@@ -1,34 +1,308 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/cache"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/gql"
 	"github.com/dilaragorum/movie-go/handler"
+	"github.com/dilaragorum/movie-go/health"
+	"github.com/dilaragorum/movie-go/idempotency"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/metrics"
+	"github.com/dilaragorum/movie-go/middleware"
+	"github.com/dilaragorum/movie-go/ratelimit"
 	"github.com/dilaragorum/movie-go/repository"
+	"github.com/dilaragorum/movie-go/requestid"
 	"github.com/dilaragorum/movie-go/service"
+	"github.com/dilaragorum/movie-go/tracing"
+	"github.com/dilaragorum/movie-go/webhook"
 	"github.com/julienschmidt/httprouter"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
 func main() {
-	// movieInMemoryRepository := repository.NewInMemoryMovieRepository()
+	policyFile := flag.String("policy", "", "path to the JSON authorization policy (see config/policy.example.json)")
+	traceExporter := flag.String("trace-exporter", tracing.ExporterNone, "where to export traces: none, stdout, file or otlp")
+	traceFile := flag.String("trace-file", "traces.json", "output of the file trace exporter")
+	cacheTTL := flag.Duration("cache-ttl", 30*time.Second, "how long movie reads are cached, 0 disables the cache")
+	migrate := flag.Bool("migrate", true, "apply pending database migrations on startup")
+	recommendInterval := flag.Duration("recommend-interval", time.Hour, "how often similar movies are precomputed, 0 disables the job")
+	idempotencyWindow := flag.Duration("idempotency-window", 24*time.Hour, "how long an Idempotency-Key is remembered")
+	eventSink := flag.String("event-sink", sinkBus, "where movie events are published besides the in-process bus: bus (nowhere else), file or webhook")
+	eventFile := flag.String("event-file", "events.ndjson", "output of the file event sink")
+	eventWebhookURL := flag.String("event-webhook-url", "", "URL the webhook event sink posts to")
+	outboxInterval := flag.Duration("outbox-interval", time.Second, "how often pending movie events are published, 0 disables the relay")
+	webhookInterval := flag.Duration("webhook-interval", 5*time.Second, "how often due webhook deliveries are sent, 0 disables them")
+	grpcAddr := flag.String("grpc-addr", ":9090", "address of the gRPC server, empty disables it")
+	logLevel := flag.String("log-level", "info", "minimum log level: debug, info, warn or error")
+	flag.Parse()
 
-	moviePostgreSQLRepository := repository.NewPostgreSQLMovieRepository()
-	movieService := service.NewDefaultMovieService(moviePostgreSQLRepository)
-	movieHandler := handler.NewMovieHandler(movieService)
+	level, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		slog.Error("parsing log level", "error", err)
+		os.Exit(1)
+	}
+	logger := logging.New(os.Stdout, level)
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "movie-go",
+		Exporter:    *traceExporter,
+		FilePath:    *traceFile,
+	})
+	if err != nil {
+		logger.Error("setting up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	policy := auth.DefaultPolicy()
+	if *policyFile != "" {
+		policy, err = auth.LoadPolicy(*policyFile)
+		if err != nil {
+			logger.Error("loading authorization policy", "error", err)
+			os.Exit(1)
+		}
+	}
+	authorizer := auth.NewAuthorizer(policy)
+
+	// movieInMemoryRepository := repository.NewInMemoryMovieRepository(logger)
+
+	appMetrics := metrics.New()
+
+	moviePostgreSQLRepository := repository.NewPostgreSQLMovieRepository(logger)
+	if *migrate {
+		if err := moviePostgreSQLRepository.Migrate(context.Background()); err != nil {
+			logger.Error("migrating postgresql schema", "error", err)
+			os.Exit(1)
+		}
+	}
+	appMetrics.RegisterDBStats(moviePostgreSQLRepository.ConnectionPool(), "movie-db")
+
+	movieRepository := metrics.NewMovieRepository(tracing.NewMovieRepository(moviePostgreSQLRepository, "postgresql"), appMetrics)
+	transactor := repository.NewPostgreSQLTransactor(moviePostgreSQLRepository.ConnectionPool())
+	outboxRepository := metrics.NewOutboxRepository(tracing.NewOutboxRepository(
+		repository.NewPostgreSQLOutboxRepository(moviePostgreSQLRepository.ConnectionPool(), logger), "postgresql"), appMetrics)
+	broadcaster := events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer)
+	var movieService service.IMovieService = metrics.NewMovieService(
+		service.NewDefaultMovieService(movieRepository, transactor, outboxRepository, broadcaster, logger), appMetrics)
+	var movieInvalidator cache.IMovieInvalidator
+	if *cacheTTL > 0 {
+		cachedMovieService := cache.NewMovieService(movieService, cache.NewLRUBackend(1000), *cacheTTL, logger)
+		movieService, movieInvalidator = cachedMovieService, cachedMovieService
+	}
+	movieHandler := handler.NewMovieHandler(movieService, logger)
+	movieEventsHandler := handler.NewMovieEventsHandler(broadcaster, logger)
+
+	personRepository := metrics.NewPersonRepository(tracing.NewPersonRepository(
+		repository.NewPostgreSQLPersonRepository(moviePostgreSQLRepository.ConnectionPool(), logger), "postgresql"), appMetrics)
+	personService := metrics.NewPersonService(service.NewDefaultPersonService(personRepository, movieRepository, logger), appMetrics)
+	personHandler := handler.NewPersonHandler(personService, logger)
+
+	ratingRepository := metrics.NewRatingRepository(tracing.NewRatingRepository(
+		repository.NewPostgreSQLRatingRepository(moviePostgreSQLRepository.ConnectionPool(), logger), "postgresql"), appMetrics)
+	var ratingService service.IRatingService = metrics.NewRatingService(service.NewDefaultRatingService(ratingRepository, movieRepository, transactor, outboxRepository, broadcaster, logger), appMetrics)
+	if movieInvalidator != nil {
+		ratingService = cache.NewRatingService(ratingService, movieInvalidator)
+	}
+	ratingHandler := handler.NewRatingHandler(ratingService, logger)
+
+	watchlistRepository := metrics.NewWatchlistRepository(tracing.NewWatchlistRepository(
+		repository.NewPostgreSQLWatchlistRepository(moviePostgreSQLRepository.ConnectionPool(), logger), "postgresql"), appMetrics)
+	watchlistService := metrics.NewWatchlistService(service.NewDefaultWatchlistService(watchlistRepository, logger), appMetrics)
+	watchlistHandler := handler.NewWatchlistHandler(watchlistService, logger)
+
+	recommendationRepository := metrics.NewRecommendationRepository(tracing.NewRecommendationRepository(
+		repository.NewPostgreSQLRecommendationRepository(moviePostgreSQLRepository.ConnectionPool(), logger), "postgresql"), appMetrics)
+	recommendationService := metrics.NewRecommendationService(
+		service.NewDefaultRecommendationService(movieRepository, ratingRepository, recommendationRepository, logger), appMetrics)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService, logger)
+
+	webhookRepository := metrics.NewWebhookRepository(tracing.NewWebhookRepository(
+		repository.NewPostgreSQLWebhookRepository(moviePostgreSQLRepository.ConnectionPool(), logger), "postgresql"), appMetrics)
+	webhookService := metrics.NewWebhookService(service.NewDefaultWebhookService(webhookRepository, logger), appMetrics)
+	webhookHandler := handler.NewWebhookHandler(webhookService, logger)
+
+	graphQLHandler := gql.NewHandler(movieService, personService, ratingService, authorizer, logger)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepository, &http.Client{Timeout: 10 * time.Second}, webhook.DefaultConfig(), logger)
+
+	eventBus := events.NewBus()
+	eventBus.Subscribe(webhookDispatcher.Enqueue)
+	sink, closeSink, err := newEventSink(*eventSink, *eventFile, *eventWebhookURL, eventBus, logger)
+	if err != nil {
+		logger.Error("creating event sink", "error", err)
+		os.Exit(1)
+	}
+	relay := events.NewRelay(outboxRepository, sink, events.DefaultBatchSize, events.DefaultLease)
+
+	idempotencyStore := idempotency.NewPostgreSQLStore(moviePostgreSQLRepository.ConnectionPool())
+	idempotent := idempotency.New(idempotencyStore, idempotency.Config{Window: *idempotencyWindow}, logger).Handle
+
+	healthRegistry := health.NewRegistry(2 * time.Second)
+	healthRegistry.Register(health.NewCheck("repository", moviePostgreSQLRepository.Ping))
+	healthRegistry.Register(health.NewCheck("migrations", moviePostgreSQLRepository.CheckMigrations))
+	healthHandler := health.NewHealthHandler(healthRegistry)
+
+	recoverer := middleware.NewRecoverer(logger, appMetrics.IncPanics)
 
 	router := httprouter.New()
+	router.PanicHandler = recoverer.PanicHandler
+	instrument := func(path string, h httprouter.Handle) httprouter.Handle {
+		return tracing.Middleware(path, appMetrics.InstrumentRoute(path, h))
+	}
+	handle := func(method, path string, h httprouter.Handle) {
+		router.Handle(method, path, instrument(path, h))
+	}
+
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+	router.Handler(http.MethodGet, "/metrics", appMetrics.Handler())
+
+	handle(http.MethodGet, "/movies", authorizer.Authorize(auth.OpGetMovies, middleware.CompressHandle(movieHandler.GetMovies)))
+	router.GET("/movies/:id", staticSegment("id", "events",
+		instrument("/movies/events", authorizer.Authorize(auth.OpGetMovieEvents, movieEventsHandler.GetMovieEvents)),
+		staticSegment("id", "stats",
+			instrument("/movies/stats", authorizer.Authorize(auth.OpGetMovieStats, middleware.CompressHandle(movieHandler.GetMovieStats))),
+			instrument("/movies/:id", authorizer.Authorize(auth.OpGetMovie, middleware.CompressHandle(movieHandler.GetMovie))))))
+
+	handle(http.MethodPost, "/movies", authorizer.Authorize(auth.OpCreateMovie, idempotent(movieHandler.CreateMovie)))
+
+	handle(http.MethodPatch, "/movies/:id", authorizer.Authorize(auth.OpUpdateMovie, movieHandler.UpdateMovie))
+
+	handle(http.MethodDelete, "/movies", authorizer.Authorize(auth.OpDeleteAllMovie, movieHandler.DeleteAllMovies))
+	handle(http.MethodDelete, "/movies/:id", authorizer.Authorize(auth.OpDeleteMovie, movieHandler.DeleteMovie))
+	handle(http.MethodPost, "/movies/:id/merge", authorizer.Authorize(auth.OpMergeMovies, idempotent(movieHandler.MergeMovies)))
+
+	handle(http.MethodGet, "/movies/:id/credits", authorizer.Authorize(auth.OpGetMovieCredits, personHandler.GetMovieCredits))
+	handle(http.MethodPost, "/movies/:id/credits", authorizer.Authorize(auth.OpAddCredit, idempotent(personHandler.AddCredit)))
+	handle(http.MethodDelete, "/movies/:id/credits/:credit_id", authorizer.Authorize(auth.OpDeleteCredit, personHandler.DeleteCredit))
+
+	handle(http.MethodGet, "/movies/:id/ratings", authorizer.Authorize(auth.OpGetRatings, ratingHandler.GetRatings))
+	handle(http.MethodPost, "/movies/:id/ratings", authorizer.Authorize(auth.OpRateMovie, idempotent(ratingHandler.RateMovie)))
+	handle(http.MethodDelete, "/movies/:id/ratings/:user_id", authorizer.Authorize(auth.OpDeleteRating, ratingHandler.DeleteRating))
+
+	handle(http.MethodGet, "/movies/:id/reviews", authorizer.Authorize(auth.OpGetReviews, ratingHandler.GetReviews))
+	handle(http.MethodPost, "/movies/:id/reviews", authorizer.Authorize(auth.OpAddReview, idempotent(ratingHandler.AddReview)))
+	handle(http.MethodDelete, "/movies/:id/reviews/:review_id", authorizer.Authorize(auth.OpDeleteReview, ratingHandler.DeleteReview))
+
+	handle(http.MethodGet, "/movies/:id/similar", authorizer.Authorize(auth.OpGetSimilarMovies, recommendationHandler.GetSimilarMovies))
+	handle(http.MethodGet, "/users/:id/recommendations", authorizer.Authorize(auth.OpGetRecommendations, recommendationHandler.GetRecommendations))
+
+	handle(http.MethodGet, "/people", authorizer.Authorize(auth.OpGetPeople, personHandler.GetPeople))
+	handle(http.MethodGet, "/people/:id", authorizer.Authorize(auth.OpGetPerson, personHandler.GetPerson))
+	handle(http.MethodGet, "/people/:id/movies", authorizer.Authorize(auth.OpGetPersonMovies, personHandler.GetPersonMovies))
+	handle(http.MethodPost, "/people", authorizer.Authorize(auth.OpCreatePerson, idempotent(personHandler.CreatePerson)))
+	handle(http.MethodPatch, "/people/:id", authorizer.Authorize(auth.OpUpdatePerson, personHandler.UpdatePerson))
+	handle(http.MethodDelete, "/people/:id", authorizer.Authorize(auth.OpDeletePerson, personHandler.DeletePerson))
+
+	handle(http.MethodGet, "/users/:id/watchlist", authorizer.Authorize(auth.OpGetWatchlist, watchlistHandler.GetWatchlist))
+	handle(http.MethodPost, "/users/:id/watchlist", authorizer.Authorize(auth.OpAddToWatchlist, idempotent(watchlistHandler.AddToWatchlist)))
+	handle(http.MethodPut, "/users/:id/watchlist", authorizer.Authorize(auth.OpReorderWatchlist, watchlistHandler.ReorderWatchlist))
+	handle(http.MethodDelete, "/users/:id/watchlist/:movie_id", authorizer.Authorize(auth.OpRemoveFromWatchlist, watchlistHandler.RemoveFromWatchlist))
+
+	handle(http.MethodGet, "/users/:id/watched", authorizer.Authorize(auth.OpGetWatched, watchlistHandler.GetWatched))
+	handle(http.MethodPost, "/users/:id/watched", authorizer.Authorize(auth.OpAddWatched, idempotent(watchlistHandler.AddWatched)))
+	handle(http.MethodDelete, "/users/:id/watched/:entry_id", authorizer.Authorize(auth.OpDeleteWatched, watchlistHandler.DeleteWatched))
+
+	// The GraphQL operations are authorized field by field with the policy of the REST routes.
+	handle(http.MethodGet, "/graphql", graphQLHandler.GraphiQL)
+	handle(http.MethodPost, "/graphql", graphQLHandler.Query)
+
+	handle(http.MethodGet, "/webhooks", authorizer.Authorize(auth.OpGetWebhooks, webhookHandler.GetWebhooks))
+	handle(http.MethodGet, "/webhooks/:id", authorizer.Authorize(auth.OpGetWebhook, webhookHandler.GetWebhook))
+	handle(http.MethodPost, "/webhooks", authorizer.Authorize(auth.OpCreateWebhook, idempotent(webhookHandler.CreateWebhook)))
+	handle(http.MethodPatch, "/webhooks/:id", authorizer.Authorize(auth.OpUpdateWebhook, webhookHandler.UpdateWebhook))
+	handle(http.MethodDelete, "/webhooks/:id", authorizer.Authorize(auth.OpDeleteWebhook, webhookHandler.DeleteWebhook))
+	handle(http.MethodGet, "/webhooks/:id/deliveries", authorizer.Authorize(auth.OpGetDeliveries, webhookHandler.GetDeliveries))
+	handle(http.MethodPost, "/webhooks/:id/deliveries/:delivery_id/redeliver", authorizer.Authorize(auth.OpRedeliverDelivery, webhookHandler.RedeliverDelivery))
+
+	limiter := ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.DefaultConfig(), authorizer, logger)
+
+	srv := newHTTPServer(":8080", requestid.Middleware(middleware.AccessLog(logger)(recoverer.Middleware(limiter.Middleware(router)))))
+	// Ends the live feeds, which would otherwise hold the shutdown up.
+	srv.RegisterOnShutdown(broadcaster.Close)
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		logger.Error("listening", "addr", srv.Addr, "error", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+	startJob := func(name string, interval time.Duration, job func(ctx context.Context) error) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			runEvery(jobsCtx, logger, name, interval, job)
+		}()
+	}
+	if *recommendInterval > 0 {
+		startJob("RefreshNeighbours", *recommendInterval, recommendationService.RefreshNeighbours)
+	}
+	startJob("DeleteExpiredIdempotencyKeys", time.Hour, idempotencyStore.DeleteExpired)
+	if *outboxInterval > 0 {
+		startJob("PublishPendingEvents", *outboxInterval, relay.PublishPending)
+	}
+	if *webhookInterval > 0 {
+		startJob("DeliverWebhooks", *webhookInterval, webhookDispatcher.DeliverDue)
+	}
+
+	var grpcDone sync.WaitGroup
+	if *grpcAddr != "" {
+		grpcLn, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			logger.Error("listening", "addr", *grpcAddr, "error", err)
+			os.Exit(1)
+		}
+		grpcSrv, healthServer := newGRPCServer(movieService, authorizer, limiter, logger)
+
+		grpcDone.Add(1)
+		go func() {
+			defer grpcDone.Done()
+			logger.Info("grpc server runs on " + *grpcAddr)
+			if err := serveGRPC(ctx, grpcSrv, healthServer, grpcLn, shutdownTimeout); err != nil {
+				logger.Error("grpc server shutdown", "error", err)
+			}
+		}()
+	}
 
-	router.GET("/movies", movieHandler.GetMovies)
-	router.GET("/movies/:id", movieHandler.GetMovie)
+	logger.Info("http server runs on :8080")
+	if err := serve(ctx, srv, ln, shutdownTimeout); err != nil {
+		logger.Error("http server shutdown", "error", err)
+	}
+	// serve also returns when the HTTP server fails, the gRPC server then stops too
+	// instead of waiting for a signal.
+	stop()
+	grpcDone.Wait()
 
-	router.POST("/movies", movieHandler.CreateMovie)
+	// The jobs use the connection pool, they stop before it is closed.
+	stopJobs()
+	jobs.Wait()
 
-	router.PATCH("/movies/:id", movieHandler.UpdateMovie)
+	if err := closeSink(); err != nil {
+		logger.Error("closing event sink", "error", err)
+	}
 
-	router.DELETE("/movies", movieHandler.DeleteAllMovies)
-	router.DELETE("/movies/:id", movieHandler.DeleteMovie)
+	if err := moviePostgreSQLRepository.Close(); err != nil {
+		logger.Error("closing postgresql connection pool", "error", err)
+	}
 
-	log.Println("http server runs on :8080")
-	err := http.ListenAndServe(":8080", router)
-	log.Fatal(err)
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("flushing traces", "error", err)
+	}
+	logger.Info("http server stopped")
 }
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+// staticSegment serves named when the wildcard param of the route equals name and
+// wildcard otherwise. httprouter cannot register a static segment such as
+// /movies/stats next to /movies/:id, so the static route is dispatched from the
+// wildcard one.
+func staticSegment(param, name string, named, wildcard httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if ps.ByName(param) == name {
+			named(w, r, ps)
+			return
+		}
+		wildcard(w, r, ps)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStaticSegment(t *testing.T) {
+	router := httprouter.New()
+	respond := func(body string) httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			w.Write([]byte(body + " " + ps.ByName("id")))
+		}
+	}
+	router.GET("/movies/:id", staticSegment("id", "stats", respond("stats"), respond("movie")))
+	router.GET("/movies/:id/credits", respond("credits"))
+
+	for path, body := range map[string]string{
+		"/movies/stats":     "stats stats",
+		"/movies/1":         "movie 1",
+		"/movies/statsx":    "movie statsx",
+		"/movies/1/credits": "credits 1",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, body, rec.Body.String(), path)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServe(t *testing.T) {
+	t.Run("In-flight request completes during shutdown", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		srv := newHTTPServer("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.Write([]byte("done"))
+		}))
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- serve(ctx, srv, ln, 5*time.Second)
+		}()
+
+		type response struct {
+			body string
+			err  error
+		}
+		responses := make(chan response, 1)
+		go func() {
+			res, err := http.Get("http://" + ln.Addr().String() + "/movies")
+			if err != nil {
+				responses <- response{err: err}
+				return
+			}
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			responses <- response{body: string(body), err: err}
+		}()
+
+		<-started
+		cancel()
+
+		select {
+		case <-serveErr:
+			t.Fatal("serve returned before the in-flight request completed")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		_, err = net.Dial("tcp", ln.Addr().String())
+		assert.NotNil(t, err, "listener should be closed once shutdown starts")
+
+		close(release)
+
+		res := <-responses
+		assert.Nil(t, res.err)
+		assert.Equal(t, "done", res.body)
+		assert.Nil(t, <-serveErr)
+	})
+	t.Run("Shutdown deadline exceeded", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		srv := newHTTPServer("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}))
+
+		ln, _ := net.Listen("tcp", "127.0.0.1:0")
+		ctx, cancel := context.WithCancel(context.Background())
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- serve(ctx, srv, ln, 50*time.Millisecond)
+		}()
+		go http.Get("http://" + ln.Addr().String())
+
+		<-started
+		cancel()
+
+		assert.ErrorIs(t, <-serveErr, context.DeadlineExceeded)
+	})
+}
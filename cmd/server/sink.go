@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	sinkBus     = "bus"
+	sinkFile    = "file"
+	sinkWebhook = "webhook"
+)
+
+// newEventSink returns the sink the outbox relay publishes to, with a func releasing
+// it. The events always go to the bus, which logs them and hands them to the other
+// in-process consumers, and then to the file or webhook sink if kind names one.
+func newEventSink(kind string, file string, webhookURL string, bus *events.Bus, logger *slog.Logger) (events.ISink, func() error, error) {
+	bus.Subscribe(func(ctx context.Context, event model.Event) error {
+		logger.DebugContext(ctx, "event published", "id", event.ID, "type", event.Type, "movie_id", event.MovieID)
+		return nil
+	})
+
+	switch kind {
+	case sinkBus:
+		return bus, func() error { return nil }, nil
+	case sinkFile:
+		sink, err := events.NewFileSink(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		return events.NewMultiSink(bus, sink), sink.Close, nil
+	case sinkWebhook:
+		if webhookURL == "" {
+			return nil, nil, fmt.Errorf("the webhook sink needs a URL")
+		}
+		sink := events.NewWebhookSink(webhookURL, &http.Client{Timeout: 10 * time.Second})
+		return events.NewMultiSink(bus, sink), func() error { return nil }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown event sink %q", kind)
+	}
+}
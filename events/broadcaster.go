@@ -0,0 +1,146 @@
+package events
+
+import (
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"sync"
+)
+
+const (
+	// DefaultHistory is how many events a Broadcaster keeps for resuming subscribers.
+	DefaultHistory = 1000
+	// DefaultSubscriberBuffer is how many events a subscriber may fall behind.
+	DefaultSubscriberBuffer = 64
+)
+
+var (
+	ErrSubscriberTooSlow = errors.New("subscriber fell too far behind the events")
+	ErrBroadcasterClosed = errors.New("broadcaster is closed")
+)
+
+// Broadcaster hands the events to live subscribers, such as the clients of the movie
+// feed, as they happen. It never waits for a subscriber: one whose buffer is full is
+// dropped and resumes from the history when it subscribes again.
+type Broadcaster struct {
+	mu          sync.Mutex
+	history     []model.Event
+	maxHistory  int
+	buffer      int
+	subscribers map[*Subscription]bool
+	closed      bool
+}
+
+// NewBroadcaster keeps at least the last history events and lets subscribers fall
+// buffer events behind.
+func NewBroadcaster(history int, buffer int) *Broadcaster {
+	return &Broadcaster{
+		maxHistory:  history,
+		buffer:      buffer,
+		subscribers: make(map[*Subscription]bool),
+	}
+}
+
+// Subscription receives the events broadcast since it was created.
+type Subscription struct {
+	broadcaster *Broadcaster
+	events      chan model.Event
+	err         error
+}
+
+// Events is closed when the subscription ends, Err then tells why.
+func (s *Subscription) Events() <-chan model.Event {
+	return s.events
+}
+
+// Err returns ErrSubscriberTooSlow or ErrBroadcasterClosed once Events is closed, or
+// nil when the subscription was closed by its owner.
+func (s *Subscription) Err() error {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription, it is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	s.broadcaster.drop(s, nil)
+}
+
+// Subscribe starts a subscription. With a lastEventID other than 0 the events retained
+// after that one are replayed first; resumed is false when it is no longer retained,
+// the subscriber then missed events and should reload what it shows.
+func (b *Broadcaster) Subscribe(lastEventID int64) (subscription *Subscription, resumed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []model.Event
+	resumed = lastEventID == 0
+	for k := len(b.history) - 1; k >= 0 && lastEventID != 0; k-- {
+		if b.history[k].ID == lastEventID {
+			replay = b.history[k+1:]
+			resumed = true
+			break
+		}
+	}
+
+	s := &Subscription{broadcaster: b, events: make(chan model.Event, b.buffer+len(replay))}
+	for _, event := range replay {
+		s.events <- event
+	}
+
+	if b.closed {
+		s.err = ErrBroadcasterClosed
+		close(s.events)
+		return s, resumed
+	}
+	b.subscribers[s] = true
+	return s, resumed
+}
+
+// Broadcast hands event to every subscriber without blocking.
+func (b *Broadcaster) Broadcast(event model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	// The history is cut down to its last maxHistory events once it holds twice as many,
+	// rather than on every event.
+	b.history = append(b.history, event)
+	if len(b.history) >= 2*b.maxHistory {
+		b.history = append(b.history[:0:0], b.history[len(b.history)-b.maxHistory:]...)
+	}
+
+	for s := range b.subscribers {
+		select {
+		case s.events <- event:
+		default:
+			b.drop(s, ErrSubscriberTooSlow)
+		}
+	}
+}
+
+// Close ends every subscription, for the server to let the live feeds go when it
+// shuts down.
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subscribers {
+		b.drop(s, ErrBroadcasterClosed)
+	}
+}
+
+// drop ends s with err, b.mu must be held.
+func (b *Broadcaster) drop(s *Subscription, err error) {
+	if !b.subscribers[s] {
+		return
+	}
+	delete(b.subscribers, s)
+	s.err = err
+	close(s.events)
+}
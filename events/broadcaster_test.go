@@ -0,0 +1,115 @@
+package events
+
+import (
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func broadcastEvents(b *Broadcaster, ids ...int64) {
+	for _, id := range ids {
+		event := model.NewMovieEvent(model.EventMovieUpdated, model.Movie{ID: 7})
+		event.ID = id
+		b.Broadcast(event)
+	}
+}
+
+func receivedIDs(s *Subscription) []int64 {
+	ids := make([]int64, 0)
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestBroadcaster_Subscribe(t *testing.T) {
+	t.Run("Live", func(t *testing.T) {
+		b := NewBroadcaster(10, 10)
+		broadcastEvents(b, 1, 2)
+
+		s, resumed := b.Subscribe(0)
+		broadcastEvents(b, 3)
+
+		assert.True(t, resumed)
+		assert.Equal(t, []int64{3}, receivedIDs(s))
+	})
+	t.Run("Resumed", func(t *testing.T) {
+		b := NewBroadcaster(10, 10)
+		broadcastEvents(b, 1, 2, 3)
+
+		s, resumed := b.Subscribe(1)
+		broadcastEvents(b, 4)
+
+		assert.True(t, resumed)
+		assert.Equal(t, []int64{2, 3, 4}, receivedIDs(s))
+	})
+	t.Run("Not retained", func(t *testing.T) {
+		b := NewBroadcaster(2, 10)
+		broadcastEvents(b, 1, 2, 3, 4, 5)
+
+		s, resumed := b.Subscribe(1)
+
+		assert.False(t, resumed)
+		assert.Empty(t, receivedIDs(s))
+	})
+	t.Run("Closed", func(t *testing.T) {
+		b := NewBroadcaster(10, 10)
+		b.Close()
+
+		s, _ := b.Subscribe(0)
+
+		_, ok := <-s.Events()
+		assert.False(t, ok)
+		assert.Equal(t, ErrBroadcasterClosed, s.Err())
+	})
+}
+
+func TestBroadcaster_Broadcast(t *testing.T) {
+	t.Run("Slow subscriber is dropped", func(t *testing.T) {
+		b := NewBroadcaster(10, 2)
+		slow, _ := b.Subscribe(0)
+		fast, _ := b.Subscribe(0)
+
+		broadcastEvents(b, 1, 2)
+		assert.Equal(t, []int64{1, 2}, receivedIDs(fast))
+		broadcastEvents(b, 3)
+
+		assert.Equal(t, []int64{1, 2}, receivedIDs(slow))
+		assert.Equal(t, ErrSubscriberTooSlow, slow.Err())
+		assert.Equal(t, []int64{3}, receivedIDs(fast))
+		assert.NoError(t, fast.Err())
+	})
+	t.Run("Dropped subscriber resumes", func(t *testing.T) {
+		b := NewBroadcaster(10, 1)
+		slow, _ := b.Subscribe(0)
+		broadcastEvents(b, 1, 2, 3)
+		assert.Equal(t, []int64{1}, receivedIDs(slow))
+
+		resumedSubscription, resumed := b.Subscribe(1)
+
+		assert.True(t, resumed)
+		assert.Equal(t, []int64{2, 3}, receivedIDs(resumedSubscription))
+	})
+}
+
+func TestBroadcaster_Close(t *testing.T) {
+	b := NewBroadcaster(10, 10)
+	s, _ := b.Subscribe(0)
+	closedByOwner, _ := b.Subscribe(0)
+	closedByOwner.Close()
+	closedByOwner.Close()
+
+	b.Close()
+	broadcastEvents(b, 1)
+
+	assert.Empty(t, receivedIDs(s))
+	assert.Equal(t, ErrBroadcasterClosed, s.Err())
+	assert.NoError(t, closedByOwner.Err())
+}
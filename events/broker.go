@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/model"
+	"strconv"
+)
+
+// NATSPublisher is implemented by *nats.Conn of github.com/nats-io/nats.go, which
+// this package does not depend on.
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+type natsSink struct {
+	conn          NATSPublisher
+	subjectPrefix string
+}
+
+// NewNATSSink publishes every event as JSON on the subject subjectPrefix + "." + its
+// type, e.g. "catalog.movie.created". Use a JetStream publisher for delivery guarantees.
+func NewNATSSink(conn NATSPublisher, subjectPrefix string) *natsSink {
+	return &natsSink{conn: conn, subjectPrefix: subjectPrefix}
+}
+
+func (n *natsSink) Publish(ctx context.Context, event model.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return n.conn.Publish(n.subjectPrefix+"."+string(event.Type), data)
+}
+
+// KafkaProducer sends a message to a topic and returns once the brokers acknowledged
+// it. It takes a few lines over the writer or synchronous producer of a Kafka client.
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key []byte, value []byte) error
+}
+
+type kafkaSink struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// NewKafkaSink produces every event as JSON to topic, keyed by movie ID so that the
+// events of a movie land on the same partition, in order.
+func NewKafkaSink(producer KafkaProducer, topic string) *kafkaSink {
+	return &kafkaSink{producer: producer, topic: topic}
+}
+
+func (k *kafkaSink) Publish(ctx context.Context, event model.Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return k.producer.Produce(ctx, k.topic, []byte(strconv.Itoa(event.MovieID)), value)
+}
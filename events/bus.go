@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"sync"
+)
+
+// Handler reacts to an event published on a Bus.
+type Handler func(ctx context.Context, event model.Event) error
+
+// Bus is an in-process sink handing the events to the handlers subscribed to it.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[int]Handler
+	nextID   int
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[int]Handler)}
+}
+
+// Subscribe adds h to the handlers until the returned func is called.
+func (b *Bus) Subscribe(h Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = h
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Publish calls every handler, even when one fails, and returns the first error. The
+// event is then published again to all of them, so handlers must tolerate duplicates.
+func (b *Bus) Publish(ctx context.Context, event model.Event) error {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	var first error
+	for _, h := range handlers {
+		if err := h(ctx, event); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
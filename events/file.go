@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/model"
+	"os"
+	"sync"
+)
+
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink appends the events to the file at path as JSON lines.
+func NewFileSink(path string) (*fileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (f *fileSink) Publish(ctx context.Context, event model.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	// The event leaves the outbox once published, it must be on disk by then.
+	return f.file.Sync()
+}
+
+func (f *fileSink) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+// DefaultBatchSize is how many events the relay claims at once.
+const DefaultBatchSize = 100
+
+// DefaultLease is how long the relay holds the events it claimed. It has to outlast the
+// publication of a whole batch, or another relay publishes the events again.
+const DefaultLease = 5 * time.Minute
+
+type Relay struct {
+	outbox    repository.IOutboxRepository
+	sink      ISink
+	batchSize int
+	lease     time.Duration
+}
+
+// NewRelay moves the events of outbox to sink. Several relays may share an outbox: the
+// events a relay is publishing are claimed for lease and the others skip them.
+func NewRelay(outbox repository.IOutboxRepository, sink ISink, batchSize int, lease time.Duration) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		sink:      sink,
+		batchSize: batchSize,
+		lease:     lease,
+	}
+}
+
+// PublishPending publishes the pending events, oldest first, until none is left or
+// one fails. An event is deleted from the outbox only after it was published, so a
+// crash in between publishes it again once its claim ran out: delivery is at least once.
+func (r *Relay) PublishPending(ctx context.Context) error {
+	for {
+		published, err := r.publishBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if published < r.batchSize {
+			return nil
+		}
+	}
+}
+
+// publishBatch claims a batch of events and publishes it outside any transaction, so
+// that no row stays locked while the sink is called.
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	events, err := r.outbox.ClaimPendingEvents(ctx, r.batchSize, r.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var publishErr error
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		// Stop at the first failure, so that the next events are not published
+		// ahead of it.
+		if err := r.sink.Publish(ctx, event); err != nil {
+			publishErr = fmt.Errorf("publishing event %d: %w", event.ID, err)
+			break
+		}
+		ids = append(ids, event.ID)
+	}
+
+	// The events left are handed back right away rather than when the lease runs out.
+	unpublished := make([]int64, 0, len(events)-len(ids))
+	for _, event := range events[len(ids):] {
+		unpublished = append(unpublished, event.ID)
+	}
+
+	// The published events are deleted even when a later one failed.
+	if len(ids) > 0 {
+		if err := r.outbox.DeleteEvents(ctx, ids); err != nil {
+			// They are published again by the next run.
+			unpublished = append(ids, unpublished...)
+			ids = nil
+			publishErr = errors.Join(publishErr, err)
+		}
+	}
+	if len(unpublished) > 0 {
+		// The context may be the cause of the failure, the release must not be.
+		if err := r.outbox.ReleaseEvents(context.WithoutCancel(ctx), unpublished); err != nil {
+			publishErr = errors.Join(publishErr, err)
+		}
+	}
+	return len(ids), publishErr
+}
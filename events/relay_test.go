@@ -0,0 +1,133 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// recordingSink stands in for a broker, it fails the events listed in fail once.
+type recordingSink struct {
+	published []int64
+	fail      map[int64]bool
+}
+
+func (r *recordingSink) Publish(ctx context.Context, event model.Event) error {
+	if r.fail[event.ID] {
+		delete(r.fail, event.ID)
+		return errors.New("oops!")
+	}
+	r.published = append(r.published, event.ID)
+	return nil
+}
+
+func newTestOutbox(t *testing.T, n int) repository.IOutboxRepository {
+	outbox := repository.NewInMemoryOutboxRepository(logging.NewNop())
+	events := make([]model.Event, 0, n)
+	for k := 1; k <= n; k++ {
+		events = append(events, model.NewMovieDeletedEvent(k))
+	}
+	assert.Nil(t, outbox.AppendEvents(context.Background(), events))
+	return outbox
+}
+
+func TestRelay_PublishPending(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - every batch is published in order", func(t *testing.T) {
+		outbox := newTestOutbox(t, 5)
+		sink := &recordingSink{}
+		relay := NewRelay(outbox, sink, 2, time.Minute)
+
+		assert.Nil(t, relay.PublishPending(ctx))
+
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, sink.published)
+		assert.Empty(t, pendingIDs(t, outbox))
+	})
+	t.Run("Failing sink - the event and the next ones are published again", func(t *testing.T) {
+		outbox := newTestOutbox(t, 4)
+		sink := &recordingSink{fail: map[int64]bool{3: true}}
+		relay := NewRelay(outbox, sink, 10, time.Minute)
+
+		err := relay.PublishPending(ctx)
+		assert.EqualError(t, err, "publishing event 3: oops!")
+		assert.Equal(t, []int64{1, 2}, sink.published)
+
+		assert.Equal(t, []int64{3, 4}, pendingIDs(t, outbox))
+
+		assert.Nil(t, relay.PublishPending(ctx))
+		assert.Equal(t, []int64{1, 2, 3, 4}, sink.published)
+	})
+	t.Run("Failing outbox - published events are kept for the next run", func(t *testing.T) {
+		outbox := &failingDeleteOutbox{IOutboxRepository: newTestOutbox(t, 2)}
+		sink := &recordingSink{}
+		relay := NewRelay(outbox, sink, 10, time.Minute)
+
+		assert.Error(t, relay.PublishPending(ctx))
+		outbox.fixed = true
+		assert.Nil(t, relay.PublishPending(ctx))
+
+		// At least once: the events are delivered again rather than lost.
+		assert.Equal(t, []int64{1, 2, 1, 2}, sink.published)
+	})
+	t.Run("Claimed events - skipped until they are released", func(t *testing.T) {
+		outbox := newTestOutbox(t, 3)
+		sink := &recordingSink{}
+		relay := NewRelay(outbox, sink, 10, time.Minute)
+
+		// Another relay is publishing the first two events.
+		claimed, err := outbox.ClaimPendingEvents(ctx, 2, time.Minute)
+		assert.Nil(t, err)
+		assert.Len(t, claimed, 2)
+
+		assert.Nil(t, relay.PublishPending(ctx))
+		assert.Equal(t, []int64{3}, sink.published)
+
+		assert.Nil(t, outbox.ReleaseEvents(ctx, []int64{1, 2}))
+		assert.Nil(t, relay.PublishPending(ctx))
+		assert.Equal(t, []int64{3, 1, 2}, sink.published)
+	})
+	t.Run("Claimed events - claimed again once the lease ran out", func(t *testing.T) {
+		outbox := newTestOutbox(t, 1)
+		sink := &recordingSink{}
+		relay := NewRelay(outbox, sink, 10, time.Minute)
+
+		// The relay holding the event crashed without releasing it.
+		_, err := outbox.ClaimPendingEvents(ctx, 1, time.Millisecond)
+		assert.Nil(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		assert.Nil(t, relay.PublishPending(ctx))
+		assert.Equal(t, []int64{1}, sink.published)
+	})
+}
+
+// pendingIDs lists the events left in outbox, without keeping them claimed.
+func pendingIDs(t *testing.T, outbox repository.IOutboxRepository) []int64 {
+	events, err := outbox.ClaimPendingEvents(context.Background(), 100, time.Minute)
+	assert.Nil(t, err)
+
+	var ids []int64
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	assert.Nil(t, outbox.ReleaseEvents(context.Background(), ids))
+	return ids
+}
+
+type failingDeleteOutbox struct {
+	repository.IOutboxRepository
+	fixed bool
+}
+
+func (f *failingDeleteOutbox) DeleteEvents(ctx context.Context, ids []int64) error {
+	if !f.fixed {
+		return errors.New("oops!")
+	}
+	return f.IOutboxRepository.DeleteEvents(ctx, ids)
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// ISink is where the relay publishes the events of the outbox. Publish must return an
+// error unless the event was delivered: the relay then publishes it again later.
+type ISink interface {
+	Publish(ctx context.Context, event model.Event) error
+}
+
+type multiSink []ISink
+
+// NewMultiSink publishes every event to all of sinks, in order, and stops at the first
+// one failing: the event is then published again to all of them.
+func NewMultiSink(sinks ...ISink) ISink {
+	return multiSink(sinks)
+}
+
+func (m multiSink) Publish(ctx context.Context, event model.Event) error {
+	for _, sink := range m {
+		if err := sink.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testEvent() model.Event {
+	event := model.NewMovieEvent(model.EventMovieCreated, model.Movie{ID: 7, Title: "Heat"})
+	event.ID = 42
+	return event
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus()
+	var received []int64
+	bus.Subscribe(func(ctx context.Context, event model.Event) error {
+		received = append(received, event.ID)
+		return nil
+	})
+	unsubscribe := bus.Subscribe(func(ctx context.Context, event model.Event) error {
+		return errors.New("oops!")
+	})
+
+	assert.EqualError(t, bus.Publish(context.Background(), testEvent()), "oops!")
+	unsubscribe()
+	assert.Nil(t, bus.Publish(context.Background(), testEvent()))
+
+	assert.Equal(t, []int64{42, 42}, received)
+}
+
+func TestFileSink_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	sink, err := NewFileSink(path)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sink.Publish(context.Background(), testEvent()))
+	assert.Nil(t, sink.Publish(context.Background(), model.NewMovieDeletedEvent(7)))
+	assert.Nil(t, sink.Close())
+
+	file, _ := os.Open(path)
+	defer file.Close()
+	var types []model.EventType
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event model.Event
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &event))
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []model.EventType{model.EventMovieCreated, model.EventMovieDeleted}, types)
+}
+
+func TestWebhookSink_Publish(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var received model.Event
+		var eventType string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			eventType = r.Header.Get(EventTypeHeader)
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		err := NewWebhookSink(server.URL, server.Client()).Publish(context.Background(), testEvent())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "movie.created", eventType)
+		assert.Equal(t, "Heat", received.Movie.Title)
+	})
+	t.Run("Error response - delivery fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := NewWebhookSink(server.URL, server.Client()).Publish(context.Background(), testEvent())
+
+		assert.Error(t, err)
+	})
+}
+
+type fakeNATS struct {
+	subjects []string
+}
+
+func (f *fakeNATS) Publish(subject string, data []byte) error {
+	f.subjects = append(f.subjects, subject)
+	return nil
+}
+
+type fakeKafka struct {
+	topic string
+	key   string
+}
+
+func (f *fakeKafka) Produce(ctx context.Context, topic string, key []byte, value []byte) error {
+	f.topic, f.key = topic, string(key)
+	return nil
+}
+
+func TestBrokerSinks_Publish(t *testing.T) {
+	conn := &fakeNATS{}
+	assert.Nil(t, NewNATSSink(conn, "catalog").Publish(context.Background(), testEvent()))
+	assert.Equal(t, []string{"catalog.movie.created"}, conn.subjects)
+
+	producer := &fakeKafka{}
+	assert.Nil(t, NewKafkaSink(producer, "movies").Publish(context.Background(), testEvent()))
+	assert.Equal(t, "movies", producer.topic)
+	assert.Equal(t, "7", producer.key)
+}
+
+func TestMultiSink_Publish(t *testing.T) {
+	first := &recordingSink{}
+	failing := &recordingSink{fail: map[int64]bool{42: true}}
+	last := &recordingSink{}
+	sink := NewMultiSink(first, failing, last)
+
+	assert.EqualError(t, sink.Publish(context.Background(), testEvent()), "oops!")
+	assert.Nil(t, sink.Publish(context.Background(), testEvent()))
+
+	assert.Equal(t, []int64{42, 42}, first.published)
+	assert.Equal(t, []int64{42}, last.published)
+}
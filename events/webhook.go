@@ -0,0 +1,55 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dilaragorum/movie-go/model"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	EventIDHeader   = "X-Event-Id"
+	EventTypeHeader = "X-Event-Type"
+)
+
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink posts every event as JSON to url, a response other than 2xx fails
+// the delivery.
+func NewWebhookSink(url string, client *http.Client) *webhookSink {
+	return &webhookSink{url: url, client: client}
+}
+
+func (w *webhookSink) Publish(ctx context.Context, event model.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatInt(event.ID, 10))
+	req.Header.Set(EventTypeHeader, string(event.Type))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s answered %s", w.url, resp.Status)
+	}
+	return nil
+}
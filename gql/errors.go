@@ -0,0 +1,67 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/service"
+	"log/slog"
+	"net/http"
+)
+
+// Error codes reported in the extensions of a GraphQL error, along with the status
+// code the REST API answers the same error with.
+const (
+	CodeBadUserInput    = "BAD_USER_INPUT"
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeInternal        = "INTERNAL"
+)
+
+// errInternal is reported in place of the failures that are not the caller's, whose
+// details stay in the log.
+var errInternal = errors.New("internal error")
+
+type resolverError struct {
+	err    error
+	code   string
+	status int
+}
+
+func (e *resolverError) Error() string {
+	return e.err.Error()
+}
+
+func (e *resolverError) Unwrap() error {
+	return e.err
+}
+
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code, "status": e.status}
+}
+
+func badUserInput(err error) error {
+	return &resolverError{err: err, code: CodeBadUserInput, status: http.StatusBadRequest}
+}
+
+// classify maps the service errors like the REST handlers do, anything else is logged
+// as a failure of op and reported as errInternal.
+func classify(ctx context.Context, logger *slog.Logger, op string, err error) error {
+	switch {
+	case service.IsValidationError(err):
+		return badUserInput(err)
+	case service.IsNotFoundError(err):
+		return &resolverError{err: err, code: CodeNotFound, status: http.StatusNotFound}
+	case service.IsConflictError(err):
+		return &resolverError{err: err, code: CodeConflict, status: http.StatusConflict}
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return &resolverError{err: err, code: CodeUnauthenticated, status: http.StatusUnauthorized}
+	case errors.Is(err, auth.ErrForbidden):
+		return &resolverError{err: err, code: CodeForbidden, status: http.StatusForbidden}
+	}
+
+	logger.ErrorContext(ctx, op+" failed", "error", err)
+	return &resolverError{err: errInternal, code: CodeInternal, status: http.StatusInternalServerError}
+}
@@ -0,0 +1,93 @@
+package gql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	// maxDepth bounds the nesting of a query, the schema needs about half of it.
+	maxDepth = 10
+	// maxBodySize bounds a request, queries and their variables are small.
+	maxBodySize = 1 << 20
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+//go:embed graphiql.html
+var graphiQL []byte
+
+type handler struct {
+	schema        *graphql.Schema
+	personService service.IPersonService
+	ratingService service.IRatingService
+	authorizer    *auth.Authorizer
+	logger        *slog.Logger
+}
+
+// NewHandler serves the schema on top of the services. The operations are authorized
+// one by one with the same policy as the REST routes, a query may therefore return
+// the fields the caller is allowed to see along with errors for the others.
+func NewHandler(ms service.IMovieService, ps service.IPersonService, rs service.IRatingService,
+	authorizer *auth.Authorizer, logger *slog.Logger) *handler {
+	root := &resolver{service: ms, logger: logger}
+	return &handler{
+		schema:        graphql.MustParseSchema(schemaSDL, root, graphql.MaxDepth(maxDepth), graphql.Tracer(otel.DefaultTracer())),
+		personService: ps,
+		ratingService: rs,
+		authorizer:    authorizer,
+		logger:        logger,
+	}
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// curl localhost:8080/graphql -d '{"query": "{ movies(first: 5) { nodes { title credits { personName role } } } }"}' | jq
+func (h *handler) Query(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var req request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+	if req.Query == "" {
+		problem.Write(w, r, http.StatusBadRequest, "query is missing")
+		return
+	}
+
+	s := &scope{
+		request:    r,
+		authorizer: h.authorizer,
+		credits:    &creditLoader{service: h.personService, credits: make(map[int][]model.Credit)},
+		reviews:    &reviewLoader{service: h.ratingService, reviews: make(map[int][]model.Review)},
+		logger:     h.logger,
+	}
+	response := h.schema.Exec(withScope(r.Context(), s), req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WarnContext(r.Context(), "writing response", "error", err)
+	}
+}
+
+// GraphiQL serves an in-browser IDE for the schema, it posts the queries to the same
+// path.
+// open localhost:8080/graphql
+func (h *handler) GraphiQL(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(graphiQL)
+}
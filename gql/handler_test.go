@@ -0,0 +1,201 @@
+package gql
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+type testServices struct {
+	movies  *service.MockIMovieService
+	people  *service.MockIPersonService
+	ratings *service.MockIRatingService
+}
+
+func newTestServices(t *testing.T) testServices {
+	ctrl := gomock.NewController(t)
+	return testServices{
+		movies:  service.NewMockIMovieService(ctrl),
+		people:  service.NewMockIPersonService(ctrl),
+		ratings: service.NewMockIRatingService(ctrl),
+	}
+}
+
+func (s testServices) query(t *testing.T, apiKey string, query string, variables map[string]interface{}) response {
+	policy := auth.DefaultPolicy()
+	policy.APIKeys["editor-key"] = auth.RoleEditor
+	h := NewHandler(s.movies, s.people, s.ratings, auth.NewAuthorizer(policy), logging.NewNop())
+
+	body, _ := json.Marshal(request{Query: query, Variables: variables})
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	if apiKey != "" {
+		req.Header.Set(auth.APIKeyHeader, apiKey)
+	}
+	rec := httptest.NewRecorder()
+
+	h.Query(rec, req, nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp response
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp
+}
+
+func TestHandler_Query(t *testing.T) {
+	movies := []model.Movie{
+		{ID: 1, Title: "Heat", ReleaseYear: 1995, RatingCount: 2, RatingMean: 8.5, Score: 8.1},
+		{ID: 2, Title: "Ronin", ReleaseYear: 1998},
+		{ID: 3, Title: "Collateral", ReleaseYear: 2004},
+	}
+	const moviesQuery = `query ($after: String) {
+		movies(filter: {genre: " Crime "}, first: 2, after: $after) {
+			totalCount
+			nodes { id title ratings { count mean } credits(role: "director") { personName } reviews { body } }
+			pageInfo { endCursor hasNextPage }
+		}
+	}`
+
+	t.Run("Success - relations are loaded in one batch", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().GetMovies(gomock.Any(), model.MovieFilter{Genre: "crime"}).Return(movies, nil).Times(1)
+		services.people.
+			EXPECT().
+			GetCreditsByMovies(gomock.Any(), []int{1, 2}).
+			Return(map[int][]model.Credit{
+				1: {{ID: 1, MovieID: 1, PersonName: "Michael Mann", Role: model.RoleDirector}, {ID: 2, MovieID: 1, PersonName: "Al Pacino", Role: model.RoleActor}},
+			}, nil).
+			Times(1)
+		services.ratings.
+			EXPECT().
+			GetReviewsByMovies(gomock.Any(), []int{1, 2}).
+			Return(map[int][]model.Review{2: {{ID: 5, MovieID: 2, Body: "Great car chases"}}}, nil).
+			Times(1)
+
+		resp := services.query(t, "", moviesQuery, nil)
+
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{
+			"totalCount": 3,
+			"nodes": [
+				{"id": "1", "title": "Heat", "ratings": {"count": 2, "mean": 8.5}, "credits": [{"personName": "Michael Mann"}], "reviews": []},
+				{"id": "2", "title": "Ronin", "ratings": {"count": 0, "mean": 0}, "credits": [], "reviews": [{"body": "Great car chases"}]}
+			],
+			"pageInfo": {"endCursor": "`+encodeCursor(2)+`", "hasNextPage": true}
+		}`, string(resp.Data["movies"]))
+	})
+	t.Run("Success - next page", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return(movies, nil).Times(1)
+		services.people.EXPECT().GetCreditsByMovies(gomock.Any(), []int{3}).Return(map[int][]model.Credit{}, nil).Times(1)
+		services.ratings.EXPECT().GetReviewsByMovies(gomock.Any(), []int{3}).Return(map[int][]model.Review{}, nil).Times(1)
+
+		resp := services.query(t, "", moviesQuery, map[string]interface{}{"after": encodeCursor(2)})
+
+		assert.Empty(t, resp.Errors)
+		assert.Contains(t, string(resp.Data["movies"]), `"title":"Collateral"`)
+		assert.Contains(t, string(resp.Data["movies"]), `"hasNextPage":false`)
+	})
+	t.Run("Error - cursor is not valid", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := services.query(t, "", moviesQuery, map[string]interface{}{"after": "2"})
+
+		assert.Len(t, resp.Errors, 1)
+		assert.Equal(t, CodeBadUserInput, resp.Errors[0].Extensions["code"])
+	})
+	t.Run("Movie not found", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().GetMovie(gomock.Any(), 9).Return(model.Movie{}, service.ErrMovieNotFound).Times(1)
+
+		resp := services.query(t, "", `{ movie(id: "9") { title } }`, nil)
+
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `null`, string(resp.Data["movie"]))
+	})
+}
+
+func TestHandler_Mutation(t *testing.T) {
+	const createMovie = `mutation { createMovie(input: {title: "Heat", releaseYear: 1995}) { duplicates { movie { id } similarity } } }`
+
+	t.Run("Success", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.
+			EXPECT().
+			CreateMovie(gomock.Any(), model.Movie{Title: "Heat", ReleaseYear: 1995}).
+			Return([]model.Duplicate{{Movie: model.Movie{ID: 4, Title: "Heat!"}, Similarity: 0.9}}, nil).
+			Times(1)
+
+		resp := services.query(t, "editor-key", createMovie, nil)
+
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"duplicates": [{"movie": {"id": "4"}, "similarity": 0.9}]}`, string(resp.Data["createMovie"]))
+	})
+	t.Run("Error - Forbidden", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().CreateMovie(gomock.Any(), gomock.Any()).Times(0)
+
+		resp := services.query(t, "", createMovie, nil)
+
+		assert.Len(t, resp.Errors, 1)
+		assert.Equal(t, CodeForbidden, resp.Errors[0].Extensions["code"])
+		assert.Equal(t, float64(http.StatusForbidden), resp.Errors[0].Extensions["status"])
+	})
+	t.Run("Error - Conflict", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().CreateMovie(gomock.Any(), gomock.Any()).Return(nil, service.ErrMovieAlreadyExists).Times(1)
+
+		resp := services.query(t, "editor-key", createMovie, nil)
+
+		assert.Len(t, resp.Errors, 1)
+		assert.Equal(t, CodeConflict, resp.Errors[0].Extensions["code"])
+	})
+	t.Run("Error - Internal hides the cause", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().CreateMovie(gomock.Any(), gomock.Any()).Return(nil, errors.New("pq: password authentication failed")).Times(1)
+
+		resp := services.query(t, "editor-key", createMovie, nil)
+
+		assert.Len(t, resp.Errors, 1)
+		assert.Equal(t, CodeInternal, resp.Errors[0].Extensions["code"])
+		assert.Equal(t, "internal error", resp.Errors[0].Message)
+	})
+	t.Run("Success - update returns the movie", func(t *testing.T) {
+		services := newTestServices(t)
+		services.movies.EXPECT().UpdateMovie(gomock.Any(), 4, model.Movie{Title: "Heat", Genres: []string{"crime"}}).Return(nil).Times(1)
+		services.movies.EXPECT().GetMovie(gomock.Any(), 4).Return(model.Movie{ID: 4, Title: "Heat", Genres: []string{"crime"}}, nil).Times(1)
+
+		resp := services.query(t, "editor-key", `mutation { updateMovie(id: "4", input: {title: "Heat", genres: ["crime"]}) { title genres } }`, nil)
+
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"title": "Heat", "genres": ["crime"]}`, string(resp.Data["updateMovie"]))
+	})
+}
+
+func TestHandler_BadRequest(t *testing.T) {
+	services := newTestServices(t)
+	h := NewHandler(services.movies, services.people, services.ratings, auth.NewAuthorizer(auth.DefaultPolicy()), logging.NewNop())
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ "query": `))
+	rec := httptest.NewRecorder()
+
+	h.Query(rec, req, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
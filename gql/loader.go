@@ -0,0 +1,130 @@
+package gql
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// batch remembers the movies of a response as they are resolved, so that the first
+// one asking for a relation loads it for every movie not loaded yet: one service call
+// per relation and page instead of one per movie.
+type batch struct {
+	mu     sync.Mutex
+	queued []int
+	seen   map[int]bool
+}
+
+func (b *batch) add(movieID int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queue(movieID)
+}
+
+// take empties the queue, which it returns with movieID, b.mu must be held.
+func (b *batch) take(movieID int) []int {
+	b.queue(movieID)
+	movieIDs := b.queued
+	b.queued = nil
+	return movieIDs
+}
+
+// queue adds movieID unless it was queued before, b.mu must be held.
+func (b *batch) queue(movieID int) {
+	if b.seen == nil {
+		b.seen = make(map[int]bool)
+	}
+	if !b.seen[movieID] {
+		b.seen[movieID] = true
+		b.queued = append(b.queued, movieID)
+	}
+}
+
+type creditLoader struct {
+	batch
+	service service.IPersonService
+	credits map[int][]model.Credit
+}
+
+func (l *creditLoader) load(ctx context.Context, movieID int) ([]model.Credit, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if credits, ok := l.credits[movieID]; ok {
+		return credits, nil
+	}
+
+	movieIDs := l.take(movieID)
+	loaded, err := l.service.GetCreditsByMovies(ctx, movieIDs)
+	if err != nil {
+		// The movies are queued again for the next one to retry.
+		l.queued = append(l.queued, movieIDs...)
+		return nil, err
+	}
+	for _, id := range movieIDs {
+		l.credits[id] = append([]model.Credit{}, loaded[id]...)
+	}
+	return l.credits[movieID], nil
+}
+
+type reviewLoader struct {
+	batch
+	service service.IRatingService
+	reviews map[int][]model.Review
+}
+
+func (l *reviewLoader) load(ctx context.Context, movieID int) ([]model.Review, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if reviews, ok := l.reviews[movieID]; ok {
+		return reviews, nil
+	}
+
+	movieIDs := l.take(movieID)
+	loaded, err := l.service.GetReviewsByMovies(ctx, movieIDs)
+	if err != nil {
+		l.queued = append(l.queued, movieIDs...)
+		return nil, err
+	}
+	for _, id := range movieIDs {
+		l.reviews[id] = append([]model.Review{}, loaded[id]...)
+	}
+	return l.reviews[movieID], nil
+}
+
+// scope is the state of one GraphQL request, the schema and its resolvers are shared
+// by all of them.
+type scope struct {
+	request    *http.Request
+	authorizer *auth.Authorizer
+	credits    *creditLoader
+	reviews    *reviewLoader
+	logger     *slog.Logger
+}
+
+type scopeKey struct{}
+
+func withScope(ctx context.Context, s *scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+func scopeFrom(ctx context.Context) *scope {
+	return ctx.Value(scopeKey{}).(*scope)
+}
+
+// authorize checks the caller of the request against op, like auth.Authorizer does for
+// the REST routes.
+func (s *scope) authorize(op auth.Operation) error {
+	return s.authorizer.Check(s.request, op)
+}
+
+// track queues movie for the relations a later field may ask for.
+func (s *scope) track(movieID int) {
+	s.credits.add(movieID)
+	s.reviews.add(movieID)
+}
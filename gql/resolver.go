@@ -0,0 +1,340 @@
+package gql
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/graph-gophers/graphql-go"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxPageSize  = 100
+	cursorPrefix = "movie:"
+)
+
+var errCursorIsNotValid = errors.New("after is not a valid cursor")
+
+// resolver is the root of the schema, for both queries and mutations. The relations
+// of a movie are resolved through the loaders of the request scope.
+type resolver struct {
+	service service.IMovieService
+	logger  *slog.Logger
+}
+
+func parseID(id graphql.ID) (int, error) {
+	parsed, err := strconv.Atoi(string(id))
+	if err != nil {
+		return 0, service.ErrIDIsNotValid
+	}
+	return parsed, nil
+}
+
+func toID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+func encodeCursor(movieID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(movieID)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, errCursorIsNotValid
+	}
+	movieID, err := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+	if err != nil {
+		return 0, errCursorIsNotValid
+	}
+	return movieID, nil
+}
+
+func (r *resolver) Movie(ctx context.Context, args struct{ ID graphql.ID }) (*movieResolver, error) {
+	s := scopeFrom(ctx)
+	if err := s.authorize(auth.OpGetMovie); err != nil {
+		return nil, classify(ctx, r.logger, "GetMovie", err)
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, badUserInput(err)
+	}
+
+	movie, err := r.service.GetMovie(ctx, id)
+	if service.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, classify(ctx, r.logger, "GetMovie", err)
+	}
+	return newMovieResolver(s, movie), nil
+}
+
+type movieFilterInput struct {
+	Genre      *string
+	Language   *string
+	Country    *string
+	AgeRating  *string
+	YearFrom   *int32
+	YearTo     *int32
+	MinRuntime *int32
+	MaxRuntime *int32
+}
+
+// toFilter checks and normalizes the filter like the query string of GET /movies.
+func (f *movieFilterInput) toFilter() (model.MovieFilter, error) {
+	filter := model.MovieFilter{}
+	if f == nil {
+		return filter, nil
+	}
+
+	strs := []struct {
+		value  *string
+		target *string
+	}{
+		{f.Genre, &filter.Genre},
+		{f.Language, &filter.Language},
+		{f.Country, &filter.Country},
+		{f.AgeRating, &filter.AgeRating},
+	}
+	for _, field := range strs {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+
+	ints := []struct {
+		name   string
+		value  *int32
+		target *int
+	}{
+		{"yearFrom", f.YearFrom, &filter.YearFrom},
+		{"yearTo", f.YearTo, &filter.YearTo},
+		{"minRuntime", f.MinRuntime, &filter.MinRuntime},
+		{"maxRuntime", f.MaxRuntime, &filter.MaxRuntime},
+	}
+	for _, field := range ints {
+		if field.value == nil {
+			continue
+		}
+		if *field.value < 0 {
+			return model.MovieFilter{}, fmt.Errorf("%s must be a non-negative integer", field.name)
+		}
+		*field.target = int(*field.value)
+	}
+
+	return filter.Normalized(), nil
+}
+
+type moviesArgs struct {
+	Filter *movieFilterInput
+	First  int32
+	After  *string
+}
+
+// Movies pages through the movies in ID order, the order of IMovieService.GetMovies.
+// A cursor is the ID of the last movie of a page, so that deleted movies do not shift
+// the next page.
+func (r *resolver) Movies(ctx context.Context, args moviesArgs) (*movieConnectionResolver, error) {
+	s := scopeFrom(ctx)
+	if err := s.authorize(auth.OpGetMovies); err != nil {
+		return nil, classify(ctx, r.logger, "GetMovies", err)
+	}
+
+	filter, err := args.Filter.toFilter()
+	if err != nil {
+		return nil, badUserInput(err)
+	}
+
+	first := int(args.First)
+	if first < 1 || first > maxPageSize {
+		return nil, badUserInput(fmt.Errorf("first must be between 1 and %d", maxPageSize))
+	}
+
+	after := 0
+	if args.After != nil {
+		if after, err = decodeCursor(*args.After); err != nil {
+			return nil, badUserInput(err)
+		}
+	}
+
+	movies, err := r.service.GetMovies(ctx, filter)
+	if err != nil {
+		return nil, classify(ctx, r.logger, "GetMovies", err)
+	}
+
+	start := len(movies)
+	for k, movie := range movies {
+		if movie.ID > after {
+			start = k
+			break
+		}
+	}
+	page := movies[start:]
+	hasNextPage := len(page) > first
+	if hasNextPage {
+		page = page[:first]
+	}
+
+	return &movieConnectionResolver{
+		totalCount:  len(movies),
+		nodes:       newMovieResolvers(s, page),
+		hasNextPage: hasNextPage,
+	}, nil
+}
+
+type movieInput struct {
+	Title            string
+	ReleaseYear      *int32
+	Score            *float64
+	Genres           *[]string
+	RuntimeMinutes   *int32
+	Synopsis         *string
+	OriginalLanguage *string
+	Country          *string
+	AgeRating        *string
+	PosterURL        *string
+}
+
+// toMovie leaves the fields that are not given zero, which the service reads as
+// unknown on create and unchanged on update.
+func (i movieInput) toMovie() model.Movie {
+	movie := model.Movie{Title: i.Title}
+	if i.ReleaseYear != nil {
+		movie.ReleaseYear = int(*i.ReleaseYear)
+	}
+	if i.Score != nil {
+		movie.Score = *i.Score
+	}
+	if i.Genres != nil {
+		movie.Genres = append([]string{}, *i.Genres...)
+	}
+	if i.RuntimeMinutes != nil {
+		movie.RuntimeMinutes = int(*i.RuntimeMinutes)
+	}
+	if i.Synopsis != nil {
+		movie.Synopsis = *i.Synopsis
+	}
+	if i.OriginalLanguage != nil {
+		movie.OriginalLanguage = *i.OriginalLanguage
+	}
+	if i.Country != nil {
+		movie.Country = *i.Country
+	}
+	if i.AgeRating != nil {
+		movie.AgeRating = *i.AgeRating
+	}
+	if i.PosterURL != nil {
+		movie.PosterURL = *i.PosterURL
+	}
+	return movie
+}
+
+func (r *resolver) CreateMovie(ctx context.Context, args struct{ Input movieInput }) (*createMoviePayloadResolver, error) {
+	s := scopeFrom(ctx)
+	if err := s.authorize(auth.OpCreateMovie); err != nil {
+		return nil, classify(ctx, r.logger, "CreateMovie", err)
+	}
+
+	duplicates, err := r.service.CreateMovie(ctx, args.Input.toMovie())
+	if err != nil {
+		return nil, classify(ctx, r.logger, "CreateMovie", err)
+	}
+
+	payload := &createMoviePayloadResolver{duplicates: make([]*duplicateResolver, 0, len(duplicates))}
+	for _, duplicate := range duplicates {
+		payload.duplicates = append(payload.duplicates, &duplicateResolver{
+			duplicate: duplicate,
+			movie:     newMovieResolver(s, duplicate.Movie),
+		})
+	}
+	return payload, nil
+}
+
+type updateMovieArgs struct {
+	ID    graphql.ID
+	Input movieInput
+}
+
+func (r *resolver) UpdateMovie(ctx context.Context, args updateMovieArgs) (*movieResolver, error) {
+	s := scopeFrom(ctx)
+	if err := s.authorize(auth.OpUpdateMovie); err != nil {
+		return nil, classify(ctx, r.logger, "UpdateMovie", err)
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, badUserInput(err)
+	}
+
+	if err := r.service.UpdateMovie(ctx, id, args.Input.toMovie()); err != nil {
+		return nil, classify(ctx, r.logger, "UpdateMovie", err)
+	}
+
+	movie, err := r.service.GetMovie(ctx, id)
+	if err != nil {
+		return nil, classify(ctx, r.logger, "GetMovie", err)
+	}
+	return newMovieResolver(s, movie), nil
+}
+
+func (r *resolver) DeleteMovie(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if err := scopeFrom(ctx).authorize(auth.OpDeleteMovie); err != nil {
+		return false, classify(ctx, r.logger, "DeleteMovie", err)
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, badUserInput(err)
+	}
+
+	if err := r.service.DeleteMovie(ctx, id); err != nil {
+		return false, classify(ctx, r.logger, "DeleteMovie", err)
+	}
+	return true, nil
+}
+
+func (r *resolver) DeleteAllMovies(ctx context.Context) (bool, error) {
+	if err := scopeFrom(ctx).authorize(auth.OpDeleteAllMovie); err != nil {
+		return false, classify(ctx, r.logger, "DeleteAllMovie", err)
+	}
+
+	if err := r.service.DeleteAllMovie(ctx); err != nil {
+		return false, classify(ctx, r.logger, "DeleteAllMovie", err)
+	}
+	return true, nil
+}
+
+type mergeMoviesArgs struct {
+	TargetID graphql.ID
+	SourceID graphql.ID
+}
+
+func (r *resolver) MergeMovies(ctx context.Context, args mergeMoviesArgs) (*movieResolver, error) {
+	s := scopeFrom(ctx)
+	if err := s.authorize(auth.OpMergeMovies); err != nil {
+		return nil, classify(ctx, r.logger, "MergeMovies", err)
+	}
+
+	targetID, err := parseID(args.TargetID)
+	if err != nil {
+		return nil, badUserInput(err)
+	}
+	sourceID, err := parseID(args.SourceID)
+	if err != nil {
+		return nil, badUserInput(err)
+	}
+
+	merged, err := r.service.MergeMovies(ctx, targetID, sourceID)
+	if err != nil {
+		return nil, classify(ctx, r.logger, "MergeMovies", err)
+	}
+	return newMovieResolver(s, merged), nil
+}
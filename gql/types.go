@@ -0,0 +1,265 @@
+package gql
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/graph-gophers/graphql-go"
+	"log/slog"
+)
+
+type movieResolver struct {
+	movie  model.Movie
+	scope  *scope
+	logger *slog.Logger
+}
+
+// newMovieResolver tracks movie for its relations to be loaded along with the other
+// movies of the response.
+func newMovieResolver(s *scope, movie model.Movie) *movieResolver {
+	s.track(movie.ID)
+	return &movieResolver{movie: movie, scope: s, logger: s.logger}
+}
+
+func newMovieResolvers(s *scope, movies []model.Movie) []*movieResolver {
+	resolvers := make([]*movieResolver, 0, len(movies))
+	for _, movie := range movies {
+		resolvers = append(resolvers, newMovieResolver(s, movie))
+	}
+	return resolvers
+}
+
+// optionalInt and optionalString report the zero values the model uses for unknown
+// details as null.
+func optionalInt(i int) *int32 {
+	if i == 0 {
+		return nil
+	}
+	v := int32(i)
+	return &v
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (m *movieResolver) ID() graphql.ID {
+	return toID(m.movie.ID)
+}
+
+func (m *movieResolver) Title() string {
+	return m.movie.Title
+}
+
+func (m *movieResolver) ReleaseYear() int32 {
+	return int32(m.movie.ReleaseYear)
+}
+
+func (m *movieResolver) Score() float64 {
+	return m.movie.Score
+}
+
+func (m *movieResolver) Ratings() *ratingSummaryResolver {
+	return &ratingSummaryResolver{summary: model.RatingSummary{
+		MovieID: m.movie.ID,
+		Count:   m.movie.RatingCount,
+		Mean:    m.movie.RatingMean,
+		Score:   m.movie.Score,
+	}}
+}
+
+func (m *movieResolver) Genres() []string {
+	return append([]string{}, m.movie.Genres...)
+}
+
+func (m *movieResolver) RuntimeMinutes() *int32 {
+	return optionalInt(m.movie.RuntimeMinutes)
+}
+
+func (m *movieResolver) Synopsis() *string {
+	return optionalString(m.movie.Synopsis)
+}
+
+func (m *movieResolver) OriginalLanguage() *string {
+	return optionalString(m.movie.OriginalLanguage)
+}
+
+func (m *movieResolver) Country() *string {
+	return optionalString(m.movie.Country)
+}
+
+func (m *movieResolver) AgeRating() *string {
+	return optionalString(m.movie.AgeRating)
+}
+
+func (m *movieResolver) PosterURL() *string {
+	return optionalString(m.movie.PosterURL)
+}
+
+func (m *movieResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: m.movie.UpdatedAt}
+}
+
+func (m *movieResolver) Credits(ctx context.Context, args struct{ Role *string }) ([]*creditResolver, error) {
+	if err := m.scope.authorize(auth.OpGetMovieCredits); err != nil {
+		return nil, classify(ctx, m.logger, "GetMovieCredits", err)
+	}
+
+	credits, err := m.scope.credits.load(ctx, m.movie.ID)
+	if err != nil {
+		return nil, classify(ctx, m.logger, "GetCreditsByMovies", err)
+	}
+
+	resolvers := make([]*creditResolver, 0, len(credits))
+	for _, credit := range credits {
+		if args.Role == nil || credit.Role == *args.Role {
+			resolvers = append(resolvers, &creditResolver{credit: credit})
+		}
+	}
+	return resolvers, nil
+}
+
+func (m *movieResolver) Reviews(ctx context.Context) ([]*reviewResolver, error) {
+	if err := m.scope.authorize(auth.OpGetReviews); err != nil {
+		return nil, classify(ctx, m.logger, "GetReviews", err)
+	}
+
+	reviews, err := m.scope.reviews.load(ctx, m.movie.ID)
+	if err != nil {
+		return nil, classify(ctx, m.logger, "GetReviewsByMovies", err)
+	}
+
+	resolvers := make([]*reviewResolver, 0, len(reviews))
+	for _, review := range reviews {
+		resolvers = append(resolvers, &reviewResolver{review: review})
+	}
+	return resolvers, nil
+}
+
+type ratingSummaryResolver struct {
+	summary model.RatingSummary
+}
+
+func (r *ratingSummaryResolver) Count() int32 {
+	return int32(r.summary.Count)
+}
+
+func (r *ratingSummaryResolver) Mean() float64 {
+	return r.summary.Mean
+}
+
+func (r *ratingSummaryResolver) Score() float64 {
+	return r.summary.Score
+}
+
+type creditResolver struct {
+	credit model.Credit
+}
+
+func (c *creditResolver) ID() graphql.ID {
+	return toID(c.credit.ID)
+}
+
+func (c *creditResolver) PersonID() graphql.ID {
+	return toID(c.credit.PersonID)
+}
+
+func (c *creditResolver) PersonName() string {
+	return c.credit.PersonName
+}
+
+func (c *creditResolver) Role() string {
+	return c.credit.Role
+}
+
+func (c *creditResolver) Character() *string {
+	return optionalString(c.credit.Character)
+}
+
+func (c *creditResolver) BillingOrder() int32 {
+	return int32(c.credit.BillingOrder)
+}
+
+type reviewResolver struct {
+	review model.Review
+}
+
+func (r *reviewResolver) ID() graphql.ID {
+	return toID(r.review.ID)
+}
+
+func (r *reviewResolver) UserID() graphql.ID {
+	return toID(r.review.UserID)
+}
+
+func (r *reviewResolver) Body() string {
+	return r.review.Body
+}
+
+func (r *reviewResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.review.CreatedAt}
+}
+
+type movieConnectionResolver struct {
+	totalCount  int
+	nodes       []*movieResolver
+	hasNextPage bool
+}
+
+func (c *movieConnectionResolver) TotalCount() int32 {
+	return int32(c.totalCount)
+}
+
+func (c *movieConnectionResolver) Nodes() []*movieResolver {
+	return c.nodes
+}
+
+func (c *movieConnectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNextPage: c.hasNextPage}
+	if len(c.nodes) > 0 {
+		cursor := encodeCursor(c.nodes[len(c.nodes)-1].movie.ID)
+		info.endCursor = &cursor
+	}
+	return info
+}
+
+type pageInfoResolver struct {
+	endCursor   *string
+	hasNextPage bool
+}
+
+func (p *pageInfoResolver) EndCursor() *string {
+	return p.endCursor
+}
+
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+type duplicateResolver struct {
+	duplicate model.Duplicate
+	movie     *movieResolver
+}
+
+func (d *duplicateResolver) Movie() *movieResolver {
+	return d.movie
+}
+
+func (d *duplicateResolver) Similarity() float64 {
+	return d.duplicate.Similarity
+}
+
+func (d *duplicateResolver) Exact() bool {
+	return d.duplicate.Exact
+}
+
+type createMoviePayloadResolver struct {
+	duplicates []*duplicateResolver
+}
+
+func (p *createMoviePayloadResolver) Duplicates() []*duplicateResolver {
+	return p.duplicates
+}
@@ -0,0 +1,77 @@
+package grpcapi
+
+import (
+	"fmt"
+	"github.com/dilaragorum/movie-go/model"
+	moviev1 "github.com/dilaragorum/movie-go/proto/movie/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoMovie(movie model.Movie) *moviev1.Movie {
+	return &moviev1.Movie{
+		Id:               int64(movie.ID),
+		Title:            movie.Title,
+		ReleaseYear:      int32(movie.ReleaseYear),
+		Score:            movie.Score,
+		RatingCount:      int32(movie.RatingCount),
+		RatingMean:       movie.RatingMean,
+		Genres:           movie.Genres,
+		RuntimeMinutes:   int32(movie.RuntimeMinutes),
+		Synopsis:         movie.Synopsis,
+		OriginalLanguage: movie.OriginalLanguage,
+		Country:          movie.Country,
+		AgeRating:        movie.AgeRating,
+		PosterUrl:        movie.PosterURL,
+		UpdatedAt:        timestamppb.New(movie.UpdatedAt),
+	}
+}
+
+// fromProtoMovie reads the details of a movie to create or update, an empty list of
+// genres stays nil, which means unknown or unchanged.
+func fromProtoMovie(movie *moviev1.Movie) model.Movie {
+	m := model.Movie{
+		Title:            movie.GetTitle(),
+		ReleaseYear:      int(movie.GetReleaseYear()),
+		Score:            movie.GetScore(),
+		RuntimeMinutes:   int(movie.GetRuntimeMinutes()),
+		Synopsis:         movie.GetSynopsis(),
+		OriginalLanguage: movie.GetOriginalLanguage(),
+		Country:          movie.GetCountry(),
+		AgeRating:        movie.GetAgeRating(),
+		PosterURL:        movie.GetPosterUrl(),
+	}
+	if len(movie.GetGenres()) > 0 {
+		m.Genres = append([]string{}, movie.GetGenres()...)
+	}
+	return m
+}
+
+// fromProtoFilter checks and normalizes the filters like the query string of GET
+// /movies.
+func fromProtoFilter(req *moviev1.ListMoviesRequest) (model.MovieFilter, error) {
+	filter := model.MovieFilter{
+		Genre:     req.GetGenre(),
+		Language:  req.GetLanguage(),
+		Country:   req.GetCountry(),
+		AgeRating: req.GetAgeRating(),
+	}
+
+	ints := []struct {
+		name   string
+		value  int32
+		target *int
+	}{
+		{"year_from", req.GetYearFrom(), &filter.YearFrom},
+		{"year_to", req.GetYearTo(), &filter.YearTo},
+		{"min_runtime", req.GetMinRuntime(), &filter.MinRuntime},
+		{"max_runtime", req.GetMaxRuntime(), &filter.MaxRuntime},
+	}
+	for _, field := range ints {
+		if field.value < 0 {
+			return model.MovieFilter{}, fmt.Errorf("%s must be a non-negative integer", field.name)
+		}
+		*field.target = int(field.value)
+	}
+
+	return filter.Normalized(), nil
+}
@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// statusError maps the service errors to the codes that grpc-gateway translates back
+// to the statuses the REST handlers answer with: 400, 401, 403, 404 and 409. Anything
+// else is logged as a failure of op and answered with a bare Internal error, so that
+// its details do not reach the clients.
+func statusError(ctx context.Context, logger *slog.Logger, op string, err error) error {
+	switch {
+	case service.IsValidationError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case service.IsNotFoundError(err):
+		return status.Error(codes.NotFound, err.Error())
+	case service.IsConflictError(err):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, auth.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	logger.ErrorContext(ctx, op+" failed", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,87 @@
+package grpcapi
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+)
+
+// operations maps the methods of MovieService onto the operations of the
+// authorization policy. Methods that are not listed, those of the health and
+// reflection services, are open to everyone.
+var operations = map[string]auth.Operation{
+	"/movie.v1.MovieService/GetMovie":        auth.OpGetMovie,
+	"/movie.v1.MovieService/ListMovies":      auth.OpGetMovies,
+	"/movie.v1.MovieService/CreateMovie":     auth.OpCreateMovie,
+	"/movie.v1.MovieService/UpdateMovie":     auth.OpUpdateMovie,
+	"/movie.v1.MovieService/DeleteMovie":     auth.OpDeleteMovie,
+	"/movie.v1.MovieService/DeleteAllMovies": auth.OpDeleteAllMovie,
+}
+
+// apiKey returns the key sent as x-api-key or as an "authorization: Bearer" token,
+// the metadata counterparts of the headers auth.APIKey reads.
+func apiKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get(strings.ToLower(auth.APIKeyHeader)); len(keys) > 0 && keys[0] != "" {
+		return keys[0]
+	}
+
+	for _, authorization := range md.Get("authorization") {
+		if strings.HasPrefix(authorization, "Bearer ") {
+			return strings.TrimPrefix(authorization, "Bearer ")
+		}
+	}
+	return ""
+}
+
+func authorize(ctx context.Context, authorizer *auth.Authorizer, logger *slog.Logger, method string) error {
+	op, ok := operations[method]
+	if !ok {
+		return nil
+	}
+	if err := authorizer.CheckKey(apiKey(ctx), op); err != nil {
+		return statusError(ctx, logger, string(op), err)
+	}
+	return nil
+}
+
+// recovered turns a panic of a handler into an Internal error, like the recoverer
+// middleware does for the HTTP handlers.
+func recovered(ctx context.Context, logger *slog.Logger, method string, err *error) {
+	if p := recover(); p != nil {
+		logger.ErrorContext(ctx, "panic recovered", "method", method, "panic", p, "stack", string(debug.Stack()))
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+// UnaryInterceptor recovers from panics and authorizes the calls with the policy of
+// the REST routes.
+func UnaryInterceptor(authorizer *auth.Authorizer, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer recovered(ctx, logger, info.FullMethod, &err)
+
+		if err := authorize(ctx, authorizer, logger, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is UnaryInterceptor for the streaming methods.
+func StreamInterceptor(authorizer *auth.Authorizer, logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := ss.Context()
+		defer recovered(ctx, logger, info.FullMethod, &err)
+
+		if err := authorize(ctx, authorizer, logger, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
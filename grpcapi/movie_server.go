@@ -0,0 +1,96 @@
+package grpcapi
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	moviev1 "github.com/dilaragorum/movie-go/proto/movie/v1"
+	"github.com/dilaragorum/movie-go/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"log/slog"
+)
+
+type movieServer struct {
+	moviev1.UnimplementedMovieServiceServer
+	service service.IMovieService
+	logger  *slog.Logger
+}
+
+// NewMovieServer implements the MovieService of proto/movie/v1 on top of ms, the
+// authorization is left to the interceptors.
+func NewMovieServer(ms service.IMovieService, logger *slog.Logger) *movieServer {
+	return &movieServer{service: ms, logger: logger}
+}
+
+// grpcurl -plaintext -d '{"id": 1}' localhost:9090 movie.v1.MovieService/GetMovie
+func (s *movieServer) GetMovie(ctx context.Context, req *moviev1.GetMovieRequest) (*moviev1.Movie, error) {
+	movie, err := s.service.GetMovie(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, statusError(ctx, s.logger, "GetMovie", err)
+	}
+	return toProtoMovie(movie), nil
+}
+
+// grpcurl -plaintext -d '{"genre": "drama"}' localhost:9090 movie.v1.MovieService/ListMovies
+func (s *movieServer) ListMovies(req *moviev1.ListMoviesRequest, stream moviev1.MovieService_ListMoviesServer) error {
+	ctx := stream.Context()
+
+	filter, err := fromProtoFilter(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.service.StreamMovies(ctx, filter, func(movie model.Movie) error {
+		return stream.Send(toProtoMovie(movie))
+	})
+	if err != nil {
+		return statusError(ctx, s.logger, "ListMovies", err)
+	}
+	return nil
+}
+
+func (s *movieServer) CreateMovie(ctx context.Context, req *moviev1.CreateMovieRequest) (*moviev1.CreateMovieResponse, error) {
+	duplicates, err := s.service.CreateMovie(ctx, fromProtoMovie(req.GetMovie()))
+	if err != nil {
+		return nil, statusError(ctx, s.logger, "CreateMovie", err)
+	}
+
+	resp := &moviev1.CreateMovieResponse{Duplicates: make([]*moviev1.Duplicate, 0, len(duplicates))}
+	for _, duplicate := range duplicates {
+		resp.Duplicates = append(resp.Duplicates, &moviev1.Duplicate{
+			Movie:      toProtoMovie(duplicate.Movie),
+			Similarity: duplicate.Similarity,
+			Exact:      duplicate.Exact,
+		})
+	}
+	return resp, nil
+}
+
+func (s *movieServer) UpdateMovie(ctx context.Context, req *moviev1.UpdateMovieRequest) (*emptypb.Empty, error) {
+	movie := fromProtoMovie(req.GetMovie())
+	for _, path := range req.GetUpdateMask().GetPaths() {
+		if path == "genres" && movie.Genres == nil {
+			movie.Genres = []string{}
+		}
+	}
+
+	if err := s.service.UpdateMovie(ctx, int(req.GetId()), movie); err != nil {
+		return nil, statusError(ctx, s.logger, "UpdateMovie", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *movieServer) DeleteMovie(ctx context.Context, req *moviev1.DeleteMovieRequest) (*emptypb.Empty, error) {
+	if err := s.service.DeleteMovie(ctx, int(req.GetId())); err != nil {
+		return nil, statusError(ctx, s.logger, "DeleteMovie", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *movieServer) DeleteAllMovies(ctx context.Context, _ *moviev1.DeleteAllMoviesRequest) (*emptypb.Empty, error) {
+	if err := s.service.DeleteAllMovie(ctx); err != nil {
+		return nil, statusError(ctx, s.logger, "DeleteAllMovie", err)
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,293 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	moviev1 "github.com/dilaragorum/movie-go/proto/movie/v1"
+	"github.com/dilaragorum/movie-go/ratelimit"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"io"
+	"net"
+	"testing"
+)
+
+// newTestClient serves a MovieService on top of ms over an in-memory connection.
+func newTestClient(t *testing.T, ms service.IMovieService) moviev1.MovieServiceClient {
+	policy := auth.DefaultPolicy()
+	policy.APIKeys["editor-key"] = auth.RoleEditor
+	authorizer := auth.NewAuthorizer(policy)
+	logger := logging.NewNop()
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryInterceptor(authorizer, logger)),
+		grpc.ChainStreamInterceptor(StreamInterceptor(authorizer, logger)),
+	)
+	moviev1.RegisterMovieServiceServer(srv, NewMovieServer(ms, logger))
+
+	ln := bufconn.Listen(1 << 20)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return moviev1.NewMovieServiceClient(conn)
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestMovieServer_GetMovie(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		ms.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1, Title: "Heat", ReleaseYear: 1995, Genres: []string{"crime"}}, nil).Times(1)
+
+		movie, err := newTestClient(t, ms).GetMovie(context.Background(), &moviev1.GetMovieRequest{Id: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), movie.GetId())
+		assert.Equal(t, "Heat", movie.GetTitle())
+		assert.Equal(t, int32(1995), movie.GetReleaseYear())
+		assert.Equal(t, []string{"crime"}, movie.GetGenres())
+	})
+
+	t.Run("Error - codes match the REST statuses", func(t *testing.T) {
+		testCases := []struct {
+			err  error
+			code codes.Code
+		}{
+			{service.ErrIDIsNotValid, codes.InvalidArgument},
+			{service.ErrMovieNotFound, codes.NotFound},
+			{errors.New("connection refused"), codes.Internal},
+		}
+		for _, tc := range testCases {
+			ms := service.NewMockIMovieService(gomock.NewController(t))
+			ms.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{}, tc.err).Times(1)
+
+			_, err := newTestClient(t, ms).GetMovie(context.Background(), &moviev1.GetMovieRequest{Id: 1})
+
+			assert.Equal(t, tc.code, status.Code(err), tc.err.Error())
+		}
+	})
+
+	t.Run("Error - Internal hides the cause", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		ms.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{}, errors.New("pq: password authentication failed")).Times(1)
+
+		_, err := newTestClient(t, ms).GetMovie(context.Background(), &moviev1.GetMovieRequest{Id: 1})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, "internal error", status.Convert(err).Message())
+	})
+
+	t.Run("Error - Unauthenticated with an unknown key", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+
+		_, err := newTestClient(t, ms).GetMovie(withAPIKey("unknown"), &moviev1.GetMovieRequest{Id: 1})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestMovieServer_ListMovies(t *testing.T) {
+	t.Run("Success - movies are streamed", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		ms.
+			EXPECT().
+			StreamMovies(gomock.Any(), model.MovieFilter{Genre: "crime", YearFrom: 1990}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ model.MovieFilter, fn func(movie model.Movie) error) error {
+				for _, movie := range []model.Movie{{ID: 1, Title: "Heat"}, {ID: 2, Title: "Ronin"}} {
+					if err := fn(movie); err != nil {
+						return err
+					}
+				}
+				return nil
+			}).
+			Times(1)
+
+		stream, err := newTestClient(t, ms).ListMovies(context.Background(), &moviev1.ListMoviesRequest{Genre: " Crime ", YearFrom: 1990})
+		assert.NoError(t, err)
+
+		var titles []string
+		for {
+			movie, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			titles = append(titles, movie.GetTitle())
+		}
+		assert.Equal(t, []string{"Heat", "Ronin"}, titles)
+	})
+
+	t.Run("Error - InvalidArgument for a negative filter", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+
+		stream, err := newTestClient(t, ms).ListMovies(context.Background(), &moviev1.ListMoviesRequest{MinRuntime: -1})
+		assert.NoError(t, err)
+		_, err = stream.Recv()
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), "min_runtime")
+	})
+}
+
+func TestMovieServer_CreateMovie(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		ms.
+			EXPECT().
+			CreateMovie(gomock.Any(), model.Movie{Title: "Heat", ReleaseYear: 1995}).
+			Return([]model.Duplicate{{Movie: model.Movie{ID: 7, Title: "Heat 2"}, Similarity: 0.8}}, nil).
+			Times(1)
+
+		resp, err := newTestClient(t, ms).CreateMovie(withAPIKey("editor-key"), &moviev1.CreateMovieRequest{
+			Movie: &moviev1.Movie{Title: "Heat", ReleaseYear: 1995},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.GetDuplicates(), 1)
+		assert.Equal(t, int64(7), resp.GetDuplicates()[0].GetMovie().GetId())
+		assert.Equal(t, 0.8, resp.GetDuplicates()[0].GetSimilarity())
+	})
+
+	t.Run("Error - PermissionDenied for a reader", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+
+		_, err := newTestClient(t, ms).CreateMovie(context.Background(), &moviev1.CreateMovieRequest{
+			Movie: &moviev1.Movie{Title: "Heat", ReleaseYear: 1995},
+		})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Error - AlreadyExists for an exact duplicate", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		ms.EXPECT().CreateMovie(gomock.Any(), gomock.Any()).Return(nil, service.ErrMovieAlreadyExists).Times(1)
+
+		_, err := newTestClient(t, ms).CreateMovie(withAPIKey("editor-key"), &moviev1.CreateMovieRequest{
+			Movie: &moviev1.Movie{Title: "Heat", ReleaseYear: 1995},
+		})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+}
+
+func TestMovieServer_UpdateMovie(t *testing.T) {
+	t.Run("Success - update_mask clears the genres", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		ms.EXPECT().UpdateMovie(gomock.Any(), 1, model.Movie{Title: "Heat", Genres: []string{}}).Return(nil).Times(1)
+
+		_, err := newTestClient(t, ms).UpdateMovie(withAPIKey("editor-key"), &moviev1.UpdateMovieRequest{
+			Id:         1,
+			Movie:      &moviev1.Movie{Title: "Heat"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title", "genres"}},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - genres stay unchanged without a mask", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+		ms.EXPECT().UpdateMovie(gomock.Any(), 1, model.Movie{Title: "Heat"}).Return(nil).Times(1)
+
+		_, err := newTestClient(t, ms).UpdateMovie(withAPIKey("editor-key"), &moviev1.UpdateMovieRequest{
+			Id:    1,
+			Movie: &moviev1.Movie{Title: "Heat"},
+		})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestMovieServer_DeleteMovie(t *testing.T) {
+	t.Run("Error - PermissionDenied for an editor", func(t *testing.T) {
+		ms := service.NewMockIMovieService(gomock.NewController(t))
+
+		_, err := newTestClient(t, ms).DeleteMovie(withAPIKey("editor-key"), &moviev1.DeleteMovieRequest{Id: 1})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	t.Run("Panic - Internal error", func(t *testing.T) {
+		interceptor := UnaryInterceptor(auth.NewAuthorizer(auth.DefaultPolicy()), logging.NewNop())
+		info := &grpc.UnaryServerInfo{FullMethod: "/movie.v1.MovieService/GetMovie"}
+
+		_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("boom")
+		})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("Success - unlisted methods are not authorized", func(t *testing.T) {
+		policy := auth.DefaultPolicy()
+		policy.DefaultRole = auth.RoleNone
+		interceptor := UnaryInterceptor(auth.NewAuthorizer(policy), logging.NewNop())
+		info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+		resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+}
+
+func TestUnaryRateLimitInterceptor(t *testing.T) {
+	newInterceptor := func() grpc.UnaryServerInterceptor {
+		authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+		limiter := ratelimit.NewLimiter(ratelimit.NewInMemoryStore(), ratelimit.Config{
+			Read:  ratelimit.Limit{Rate: 1, Burst: 1},
+			Write: ratelimit.Limit{Rate: 1, Burst: 1},
+		}, authorizer, logging.NewNop())
+		return UnaryRateLimitInterceptor(limiter, logging.NewNop())
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	t.Run("Exhausted bucket - ResourceExhausted", func(t *testing.T) {
+		interceptor := newInterceptor()
+		info := &grpc.UnaryServerInfo{FullMethod: "/movie.v1.MovieService/GetMovie"}
+
+		_, err := interceptor(context.Background(), nil, info, handler)
+		assert.NoError(t, err)
+		_, err = interceptor(context.Background(), nil, info, handler)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		info = &grpc.UnaryServerInfo{FullMethod: "/movie.v1.MovieService/CreateMovie"}
+		_, err = interceptor(context.Background(), nil, info, handler)
+		assert.NoError(t, err, "writes have a bucket of their own")
+	})
+
+	t.Run("Success - health checks are not limited", func(t *testing.T) {
+		interceptor := newInterceptor()
+		info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+		for i := 0; i < 3; i++ {
+			_, err := interceptor(context.Background(), nil, info, handler)
+			assert.NoError(t, err)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"math"
+	"strconv"
+)
+
+// reads are the methods of MovieService counted in the read buckets, the others are
+// counted as writes.
+var reads = map[string]bool{
+	"/movie.v1.MovieService/GetMovie":   true,
+	"/movie.v1.MovieService/ListMovies": true,
+}
+
+// rateLimit takes a token of the client of ctx for method. The methods of the health
+// and reflection services are not limited, like the probes of the HTTP server.
+func rateLimit(ctx context.Context, limiter *ratelimit.Limiter, logger *slog.Logger, method string) (metadata.MD, error) {
+	if _, ok := operations[method]; !ok {
+		return nil, nil
+	}
+
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	result, err := limiter.Take(reads[method], apiKey(ctx), remoteAddr)
+	if err != nil {
+		// Prefer serving the call over failing it when the store is unavailable.
+		logger.ErrorContext(ctx, "rate limit store", "error", err)
+		return nil, nil
+	}
+	if result.Allowed {
+		return nil, nil
+	}
+
+	retryAfter := metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+	return retryAfter, status.Error(codes.ResourceExhausted, "too many requests")
+}
+
+// UnaryRateLimitInterceptor limits the calls with the buckets of the HTTP requests, so
+// that a client has one budget across both APIs.
+func UnaryRateLimitInterceptor(limiter *ratelimit.Limiter, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		header, err := rateLimit(ctx, limiter, logger, info.FullMethod)
+		if err != nil {
+			grpc.SetHeader(ctx, header)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor is UnaryRateLimitInterceptor for the streaming methods.
+func StreamRateLimitInterceptor(limiter *ratelimit.Limiter, logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		header, err := rateLimit(ss.Context(), limiter, logger, info.FullMethod)
+		if err != nil {
+			ss.SetHeader(header)
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/dilaragorum/movie-go/model"
+	"net/url"
+	"strconv"
+)
+
+// parseMovieFilter reads the GET /movies filters from the query string, normalized
+// the way the service stores them.
+//
+//	/movies?genre=drama&language=en&country=US&age_rating=R
+//	/movies?year_from=1990&year_to=1999&min_runtime=90&max_runtime=150
+func parseMovieFilter(query url.Values) (model.MovieFilter, error) {
+	filter := model.MovieFilter{
+		Genre:     query.Get("genre"),
+		Language:  query.Get("language"),
+		Country:   query.Get("country"),
+		AgeRating: query.Get("age_rating"),
+	}
+
+	ints := []struct {
+		name  string
+		value *int
+	}{
+		{"year_from", &filter.YearFrom},
+		{"year_to", &filter.YearTo},
+		{"min_runtime", &filter.MinRuntime},
+		{"max_runtime", &filter.MaxRuntime},
+	}
+	for _, param := range ints {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return model.MovieFilter{}, fmt.Errorf("%s must be a non-negative integer", param.name)
+		}
+		*param.value = value
+	}
+
+	return filter.Normalized(), nil
+}
+
+// defaultLimit is the page size of the list endpoints taking ?limit=.
+const defaultLimit = 10
+
+// parseLimit reads ?limit=, the service checks its range.
+func parseLimit(query url.Values) (int, error) {
+	raw := query.Get("limit")
+	if raw == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("limit must be an integer")
+	}
+	return limit, nil
+}
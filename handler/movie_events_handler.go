@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/gorilla/websocket"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// heartbeatInterval keeps idle feeds from being closed by proxies in between.
+	heartbeatInterval = 15 * time.Second
+	// reconnectDelay is how long an SSE client waits before it reconnects.
+	reconnectDelay = 3 * time.Second
+	// feedWriteTimeout bounds writing one message to a WebSocket client.
+	feedWriteTimeout = 10 * time.Second
+	// pongTimeout is how long a WebSocket client may leave a ping unanswered.
+	pongTimeout = 2 * heartbeatInterval
+)
+
+// resetEventType tells a client that it missed events, either because it connected
+// without a Last-Event-ID or because that event is no longer retained.
+const resetEventType = "reset"
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+}
+
+type movieEventsHandler struct {
+	broadcaster *events.Broadcaster
+	logger      *slog.Logger
+}
+
+func NewMovieEventsHandler(broadcaster *events.Broadcaster, logger *slog.Logger) *movieEventsHandler {
+	return &movieEventsHandler{broadcaster: broadcaster, logger: logger}
+}
+
+// GetMovieEvents streams the changes of the catalog as server-sent events, or over a
+// WebSocket when the request asks for an upgrade. A client resumes where it stopped
+// with the Last-Event-ID header or the last_event_id parameter.
+// curl -N localhost:8080/movies/events
+// curl -N -H "Last-Event-ID: 42" localhost:8080/movies/events
+func (eh *movieEventsHandler) GetMovieEvents(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	lastEventID, err := lastEventID(r)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		eh.serveWebSocket(w, r, lastEventID)
+		return
+	}
+	eh.serveSSE(w, r, lastEventID)
+}
+
+func lastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("last event id must be a non-negative integer")
+	}
+	return id, nil
+}
+
+func (eh *movieEventsHandler) serveSSE(w http.ResponseWriter, r *http.Request, lastEventID int64) {
+	rc := http.NewResponseController(w)
+	// The feed lasts longer than the write timeout of the server allows a response to.
+	rc.SetWriteDeadline(time.Time{})
+
+	subscription, resumed := eh.broadcaster.Subscribe(lastEventID)
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	if !resumed {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", resetEventType)
+	}
+	if err := rc.Flush(); err != nil {
+		eh.logger.ErrorContext(r.Context(), "GetMovieEvents cannot stream", "error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-subscription.Events():
+			if !ok {
+				eh.logEnd(r, subscription.Err())
+				return
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event model.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// serveWebSocket sends every event as a JSON text message. Messages from the client
+// are read only to answer pings and notice the close.
+// websocat ws://localhost:8080/movies/events
+func (eh *movieEventsHandler) serveWebSocket(w http.ResponseWriter, r *http.Request, lastEventID int64) {
+	// Upgrade answers the failed handshakes itself.
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	subscription, resumed := eh.broadcaster.Subscribe(lastEventID)
+	defer subscription.Close()
+
+	disconnected := make(chan struct{})
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if !resumed {
+		conn.SetWriteDeadline(time.Now().Add(feedWriteTimeout))
+		if err := conn.WriteJSON(map[string]string{"type": resetEventType}); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-disconnected:
+			return
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(feedWriteTimeout)); err != nil {
+				return
+			}
+		case event, ok := <-subscription.Events():
+			if !ok {
+				err := subscription.Err()
+				eh.logEnd(r, err)
+				code := websocket.CloseGoingAway
+				if errors.Is(err, events.ErrSubscriberTooSlow) {
+					code = websocket.CloseTryAgainLater
+				}
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, err.Error()),
+					time.Now().Add(feedWriteTimeout))
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(feedWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// logEnd reports why the broadcaster ended the feed, which the client notices as a
+// closed connection.
+func (eh *movieEventsHandler) logEnd(r *http.Request, err error) {
+	if errors.Is(err, events.ErrSubscriberTooSlow) {
+		eh.logger.WarnContext(r.Context(), "GetMovieEvents dropped a slow client", "error", err)
+		return
+	}
+	eh.logger.DebugContext(r.Context(), "GetMovieEvents ended", "error", err)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/gorilla/websocket"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func movieEvent(id int64) model.Event {
+	event := model.NewMovieEvent(model.EventMovieCreated, model.Movie{ID: 7, Title: "Heat"})
+	event.ID = id
+	return event
+}
+
+// readSSE returns the lines of the stream up to and including the first one that
+// starts with until.
+func readSSE(t *testing.T, reader *bufio.Reader, until string) []string {
+	lines := make([]string, 0)
+	for {
+		line, err := reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return lines
+		}
+		line = strings.TrimSuffix(line, "\n")
+		lines = append(lines, line)
+		if strings.HasPrefix(line, until) {
+			return lines
+		}
+	}
+}
+
+func newMovieEventsServer(broadcaster *events.Broadcaster) *httptest.Server {
+	eh := NewMovieEventsHandler(broadcaster, logging.NewNop())
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		eh.GetMovieEvents(w, r, httprouter.Params{})
+	}))
+}
+
+func TestMovieEventsHandler_GetMovieEvents(t *testing.T) {
+	t.Run("SSE - Resumed", func(t *testing.T) {
+		broadcaster := events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer)
+		broadcaster.Broadcast(movieEvent(1))
+		broadcaster.Broadcast(movieEvent(2))
+		server := newMovieEventsServer(broadcaster)
+		defer server.Close()
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Last-Event-ID", "1")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		assert.Equal(t, []string{"retry: 3000", "", "id: 2", "event: movie.created"}, readSSE(t, reader, "event:"))
+		assert.Contains(t, readSSE(t, reader, "data:")[0], `"title":"Heat"`)
+
+		broadcaster.Broadcast(movieEvent(3))
+		assert.Equal(t, []string{"", "id: 3"}, readSSE(t, reader, "id:"))
+	})
+	t.Run("SSE - Reset", func(t *testing.T) {
+		broadcaster := events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer)
+		server := newMovieEventsServer(broadcaster)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "?last_event_id=99")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+		assert.Equal(t, []string{"retry: 3000", "", "event: reset"}, readSSE(t, reader, "event:"))
+	})
+	t.Run("SSE - Ends when the broadcaster closes", func(t *testing.T) {
+		broadcaster := events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer)
+		server := newMovieEventsServer(broadcaster)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+		readSSE(t, reader, "retry:")
+		broadcaster.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		done := make(chan error)
+		go func() {
+			_, err := reader.ReadString(0)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.Error(t, err)
+		case <-ctx.Done():
+			t.Fatal("stream did not end")
+		}
+	})
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/events", nil)
+		req.Header.Set("Last-Event-ID", "abc")
+		rec := httptest.NewRecorder()
+
+		broadcaster := events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer)
+		NewMovieEventsHandler(broadcaster, logging.NewNop()).GetMovieEvents(rec, req, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("WebSocket", func(t *testing.T) {
+		broadcaster := events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer)
+		broadcaster.Broadcast(movieEvent(1))
+		broadcaster.Broadcast(movieEvent(2))
+		server := newMovieEventsServer(broadcaster)
+		defer server.Close()
+
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?last_event_id=1", nil)
+		assert.NoError(t, err)
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		var received model.Event
+		assert.NoError(t, conn.ReadJSON(&received))
+		assert.Equal(t, int64(2), received.ID)
+
+		broadcaster.Broadcast(movieEvent(3))
+		assert.NoError(t, conn.ReadJSON(&received))
+		assert.Equal(t, int64(3), received.ID)
+		assert.Equal(t, model.EventMovieCreated, received.Type)
+
+		broadcaster.Close()
+		_, _, err = conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+	})
+}
@@ -3,89 +3,153 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
 	"github.com/dilaragorum/movie-go/service"
 	"github.com/julienschmidt/httprouter"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type movieHandler struct {
 	service service.IMovieService
+	logger  *slog.Logger
 }
 
-func NewMovieHandler(ms service.IMovieService) *movieHandler {
-	return &movieHandler{service: ms}
+func NewMovieHandler(ms service.IMovieService, logger *slog.Logger) *movieHandler {
+	return &movieHandler{service: ms, logger: logger}
 }
 
 // curl localhost:8080/movies | jq
+// curl "localhost:8080/movies?genre=drama&year_from=1990" | jq
 func (mh *movieHandler) GetMovies(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	movies, err := mh.service.GetMovies()
+	w.Header().Add("Vary", "Accept")
+	f, ok := negotiateFormat(r.Header.Get("Accept"))
+	if !ok {
+		problem.Write(w, r, http.StatusNotAcceptable, "supported types: "+supportedContentTypes())
+		return
+	}
+
+	filter, err := parseMovieFilter(r.URL.Query())
 	if err != nil {
-		http.Error(w, "Unable to get all movies", http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if wantsStream(r, f) {
+		mh.streamMovies(w, r, f, filter)
 		return
 	}
 
-	jsonStr, err := json.Marshal(movies)
+	movies, err := mh.service.GetMovies(r.Context(), filter)
 	if err != nil {
-		http.Error(w, "", http.StatusInternalServerError)
+		mh.logger.ErrorContext(r.Context(), "GetMovies failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "Unable to get all movies")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(jsonStr)
+	body, err := f.encodeMovies(movies)
+	if err != nil {
+		mh.logger.ErrorContext(r.Context(), "GetMovies failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "")
+		return
+	}
+
+	// The list has no Last-Modified: deleting a movie does not move the latest
+	// updated_at, so If-Modified-Since would keep answering with the stale list. The
+	// ETag changes with the body.
+	writeCacheable(w, r, body, f.contentType, time.Time{})
 }
 
 // curl "localhost:8080/movies/1" | jq
 func (mh *movieHandler) GetMovie(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	w.Header().Add("Vary", "Accept")
+	f, ok := negotiateFormat(r.Header.Get("Accept"))
+	if !ok {
+		problem.Write(w, r, http.StatusNotAcceptable, "supported types: "+supportedContentTypes())
+		return
+	}
+
 	id, _ := strconv.Atoi(ps.ByName("id"))
 
-	movie, err := mh.service.GetMovie(id)
+	movie, err := mh.service.GetMovie(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, service.ErrIDIsNotValid) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, err.Error())
 			return
 		} else if errors.Is(err, service.ErrMovieNotFound) { // Test yaz
-			http.Error(w, err.Error(), http.StatusNotFound)
+			problem.Write(w, r, http.StatusNotFound, err.Error())
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		mh.logger.ErrorContext(r.Context(), "GetMovie failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	jsonStr, err := json.Marshal(movie)
+	body, err := f.encodeMovie(movie)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		mh.logger.ErrorContext(r.Context(), "GetMovie failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(jsonStr)
+	writeCacheable(w, r, body, f.contentType, movie.UpdatedAt)
+}
+
+// curl "localhost:8080/movies/stats?genre=drama&limit=5" | jq
+func (mh *movieHandler) GetMovieStats(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	filter, err := parseMovieFilter(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	topN, err := parseLimit(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := mh.service.GetMovieStats(r.Context(), filter, topN)
+	if err != nil {
+		writeError(w, r, mh.logger, "GetMovieStats", err)
+		return
+	}
+
+	writeJSON(w, r, mh.logger, stats)
 }
 
 /*
 curl -X POST localhost:8080/movies \
 -H 'Content-Type: application/json' \
--d '{ "title": "A New Movie" }'
+-d '{ "title": "A New Movie", "genres": ["drama"], "runtime_minutes": 120, "original_language": "en" }'
 */
 func (mh *movieHandler) CreateMovie(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	var movie model.Movie
 	err := json.NewDecoder(r.Body).Decode(&movie)
 	if err != nil {
-		http.Error(w, "error when decoding json", http.StatusInternalServerError)
+		mh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "error when decoding json")
 		return
 	}
 
-	err = mh.service.CreateMovie(movie)
+	duplicates, err := mh.service.CreateMovie(r.Context(), movie)
 	if err != nil {
-		if errors.Is(err, service.ErrTitleIsNotEmpty) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, mh.logger, "CreateMovie", err)
 		return
 	}
 
+	// The movie is created all the same, the possible duplicates are left to the
+	// client, e.g. for a merge.
+	for _, duplicate := range duplicates {
+		text := fmt.Sprintf("possible duplicate of movie %d %q (similarity %.2f)",
+			duplicate.Movie.ID, duplicate.Movie.Title, duplicate.Similarity)
+		w.Header().Add("Warning", "299 - "+strconv.Quote(text))
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte("Movie is successfully created"))
 }
@@ -93,13 +157,14 @@ func (mh *movieHandler) CreateMovie(w http.ResponseWriter, r *http.Request, _ ht
 func (mh *movieHandler) DeleteMovie(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	id, _ := strconv.Atoi(ps.ByName("id"))
 
-	err := mh.service.DeleteMovie(id)
+	err := mh.service.DeleteMovie(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, service.ErrIDIsNotValid) || errors.Is(err, service.ErrMovieNotFound) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, err.Error())
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		mh.logger.ErrorContext(r.Context(), "DeleteMovie failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -108,9 +173,10 @@ func (mh *movieHandler) DeleteMovie(w http.ResponseWriter, r *http.Request, ps h
 }
 
 func (mh *movieHandler) DeleteAllMovies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	err := mh.service.DeleteAllMovie()
+	err := mh.service.DeleteAllMovie(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		mh.logger.ErrorContext(r.Context(), "DeleteAllMovies failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -125,25 +191,49 @@ func (mh *movieHandler) UpdateMovie(w http.ResponseWriter, r *http.Request, ps h
 	var movie model.Movie
 	err := json.NewDecoder(r.Body).Decode(&movie)
 	if err != nil {
-		http.Error(w, "error when decoding json", http.StatusInternalServerError)
+		mh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "error when decoding json")
 		return
 	}
 
-	err = mh.service.UpdateMovie(id, movie)
+	err = mh.service.UpdateMovie(r.Context(), id, movie)
 
 	if err != nil {
-		if errors.Is(err, service.ErrIDIsNotValid) ||
-			errors.Is(err, service.ErrTitleIsNotEmpty) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if service.IsValidationError(err) {
+			problem.Write(w, r, http.StatusBadRequest, err.Error())
 			return
 		} else if errors.Is(err, service.ErrMovieNotFound) {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			problem.Write(w, r, http.StatusNotFound, err.Error())
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		mh.logger.ErrorContext(r.Context(), "UpdateMovie failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 	w.Write([]byte("Successfully Updated"))
 }
+
+type movieMerge struct {
+	SourceID int `json:"source_id"`
+}
+
+// curl -X POST localhost:8080/movies/2/merge -d '{ "source_id": 4 }' | jq
+func (mh *movieHandler) MergeMovies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var merge movieMerge
+	if err := json.NewDecoder(r.Body).Decode(&merge); err != nil {
+		mh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	movie, err := mh.service.MergeMovies(r.Context(), id, merge.SourceID)
+	if err != nil {
+		writeError(w, r, mh.logger, "MergeMovies", err)
+		return
+	}
+
+	writeJSON(w, r, mh.logger, movie)
+}
@@ -2,17 +2,23 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/dilaragorum/movie-go/logging"
 	"github.com/dilaragorum/movie-go/model"
 	"github.com/dilaragorum/movie-go/service"
 	"github.com/golang/mock/gomock"
 	"github.com/julienschmidt/httprouter"
 	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMovieHandler_GetMovies(t *testing.T) {
@@ -23,11 +29,11 @@ func TestMovieHandler_GetMovies(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			GetMovies().
+			GetMovies(gomock.Any(), gomock.Any()).
 			Return([]model.Movie{}, errors.New("oops!")).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 
 		mh.GetMovies(rec, req, nil)
 
@@ -40,11 +46,11 @@ func TestMovieHandler_GetMovies(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			GetMovies().
+			GetMovies(gomock.Any(), gomock.Any()).
 			Return([]model.Movie{{ID: 1, Title: "Film"}}, nil).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 
 		mh.GetMovies(rec, req, nil)
 
@@ -56,6 +62,67 @@ func TestMovieHandler_GetMovies(t *testing.T) {
 		assert.Equal(t, 1, returnedMovies[0].ID)
 		assert.Equal(t, "Film", returnedMovies[0].Title)
 	})
+	t.Run("No Last-Modified - If-Modified-Since is ignored", func(t *testing.T) {
+		updatedAt := time.Date(2022, 4, 24, 10, 0, 0, 0, time.UTC)
+		req, _ := http.NewRequest(http.MethodGet, "/movies", http.NoBody)
+		req.Header.Set("If-Modified-Since", updatedAt.Format(http.TimeFormat))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetMovies(gomock.Any(), gomock.Any()).
+			Return([]model.Movie{{ID: 1, Title: "Film", UpdatedAt: updatedAt}}, nil).
+			Times(1)
+
+		NewMovieHandler(mockService, logging.NewNop()).GetMovies(rec, req, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Last-Modified"))
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+	})
+}
+
+func TestMovieHandler_GetMovies_Filter(t *testing.T) {
+	t.Run("Query parameters become the filter", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet,
+			"/movies?genre=Drama&language=EN&country=us&age_rating=r&year_from=1990&year_to=1999&min_runtime=90&max_runtime=150",
+			http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetMovies(gomock.Any(), model.MovieFilter{
+				Genre:      "drama",
+				Language:   "en",
+				Country:    "US",
+				AgeRating:  "R",
+				YearFrom:   1990,
+				YearTo:     1999,
+				MinRuntime: 90,
+				MaxRuntime: 150,
+			}).
+			Return([]model.Movie{}, nil).
+			Times(1)
+
+		NewMovieHandler(mockService, logging.NewNop()).GetMovies(rec, req, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Invalid number - BadRequest", func(t *testing.T) {
+		for _, target := range []string{"/movies?year_from=nineties", "/movies?min_runtime=-5"} {
+			req, _ := http.NewRequest(http.MethodGet, target, http.NoBody)
+			rec := httptest.NewRecorder()
+
+			mockService := service.NewMockIMovieService(gomock.NewController(t))
+			mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Times(0)
+
+			NewMovieHandler(mockService, logging.NewNop()).GetMovies(rec, req, nil)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
 }
 
 func TestMovieHandler_GetMovie(t *testing.T) {
@@ -72,11 +139,11 @@ func TestMovieHandler_GetMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			GetMovie(1).
+			GetMovie(gomock.Any(), 1).
 			Return(model.Movie{}, service.ErrIDIsNotValid).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.GetMovie(rec, req, ps)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
@@ -89,11 +156,11 @@ func TestMovieHandler_GetMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			GetMovie(1).
+			GetMovie(gomock.Any(), 1).
 			Return(model.Movie{}, service.ErrMovieNotFound).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.GetMovie(rec, req, ps)
 
 		assert.Equal(t, http.StatusNotFound, rec.Code)
@@ -105,11 +172,11 @@ func TestMovieHandler_GetMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			GetMovie(1).
+			GetMovie(gomock.Any(), 1).
 			Return(model.Movie{}, errors.New("")).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.GetMovie(rec, req, ps)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
@@ -122,11 +189,11 @@ func TestMovieHandler_GetMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			GetMovie(1).
+			GetMovie(gomock.Any(), 1).
 			Return(model.Movie{ID: 1}, nil).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.GetMovie(rec, req, ps)
 
 		assert.Equal(t, http.StatusOK, rec.Code)
@@ -148,11 +215,11 @@ func TestMovieHandler_CreateMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			CreateMovie(model.Movie{Title: ""}).
-			Return(service.ErrTitleIsNotEmpty).
+			CreateMovie(gomock.Any(), model.Movie{Title: ""}).
+			Return(nil, service.ErrTitleIsNotEmpty).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.CreateMovie(rec, req, nil)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
@@ -166,11 +233,11 @@ func TestMovieHandler_CreateMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			CreateMovie(model.Movie{Title: "Test Movie"}).
-			Return(errors.New("")).
+			CreateMovie(gomock.Any(), model.Movie{Title: "Test Movie"}).
+			Return(nil, errors.New("")).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.CreateMovie(rec, req, nil)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
@@ -184,15 +251,91 @@ func TestMovieHandler_CreateMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			CreateMovie(model.Movie{Title: "Test Movie"}).
-			Return(nil).
+			CreateMovie(gomock.Any(), model.Movie{Title: "Test Movie"}).
+			Return(nil, nil).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.CreateMovie(rec, req, nil)
 
 		responseBodyStr := rec.Body.String()
 		assert.Equal(t, "Movie is successfully created", responseBodyStr)
+		assert.Empty(t, rec.Header().Values("Warning"))
+	})
+	t.Run("Error create movie - ErrMovieAlreadyExists - Conflict", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies", strings.NewReader(`{"title": "The Godfather", "release_year": 1972}`))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			CreateMovie(gomock.Any(), model.Movie{Title: "The Godfather", ReleaseYear: 1972}).
+			Return(nil, fmt.Errorf("%w with id %d", service.ErrMovieAlreadyExists, 2)).
+			Times(1)
+
+		mh := NewMovieHandler(mockService, logging.NewNop())
+		mh.CreateMovie(rec, req, nil)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "with id 2")
+	})
+	t.Run("Success - near duplicates are warned about", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies", strings.NewReader(`{"title": "The Godfather", "release_year": 1973}`))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			CreateMovie(gomock.Any(), model.Movie{Title: "The Godfather", ReleaseYear: 1973}).
+			Return([]model.Duplicate{{Movie: model.Movie{ID: 2, Title: "The Godfather"}, Similarity: 1}}, nil).
+			Times(1)
+
+		mh := NewMovieHandler(mockService, logging.NewNop())
+		mh.CreateMovie(rec, req, nil)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, []string{`299 - "possible duplicate of movie 2 \"The Godfather\" (similarity 1.00)"`}, rec.Header().Values("Warning"))
+	})
+}
+
+func TestMovieHandler_MergeMovies(t *testing.T) {
+	ps := httprouter.Params{{Key: "id", Value: "2"}}
+
+	t.Run("Error merge movies - invalid body - Bad Request", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/2/merge", strings.NewReader("{"))
+		rec := httptest.NewRecorder()
+
+		mh := NewMovieHandler(service.NewMockIMovieService(gomock.NewController(t)), logging.NewNop())
+		mh.MergeMovies(rec, req, ps)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Error merge movies - ErrMovieNotFound - Not Found", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/2/merge", strings.NewReader(`{"source_id": 9}`))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().MergeMovies(gomock.Any(), 2, 9).Return(model.Movie{}, service.ErrMovieNotFound).Times(1)
+
+		mh := NewMovieHandler(mockService, logging.NewNop())
+		mh.MergeMovies(rec, req, ps)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/2/merge", strings.NewReader(`{"source_id": 4}`))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().MergeMovies(gomock.Any(), 2, 4).Return(model.Movie{ID: 2, Title: "The Godfather"}, nil).Times(1)
+
+		mh := NewMovieHandler(mockService, logging.NewNop())
+		mh.MergeMovies(rec, req, ps)
+
+		var movie model.Movie
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &movie))
+		assert.Equal(t, 2, movie.ID)
 	})
 }
 
@@ -221,11 +364,11 @@ func TestMovieHandler_DeleteMovie(t *testing.T) {
 			mockService := service.NewMockIMovieService(gomock.NewController(t))
 			mockService.
 				EXPECT().
-				DeleteMovie(1).
+				DeleteMovie(gomock.Any(), 1).
 				Return(testError.serviceErr).
 				Times(1)
 
-			mh := NewMovieHandler(mockService)
+			mh := NewMovieHandler(mockService, logging.NewNop())
 
 			mh.DeleteMovie(rec, req, ps)
 
@@ -240,11 +383,11 @@ func TestMovieHandler_DeleteMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			DeleteMovie(1).
+			DeleteMovie(gomock.Any(), 1).
 			Return(errors.New("")).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 
 		mh.DeleteMovie(rec, req, ps)
 
@@ -258,11 +401,11 @@ func TestMovieHandler_DeleteMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			DeleteMovie(1).
+			DeleteMovie(gomock.Any(), 1).
 			Return(nil).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 
 		mh.DeleteMovie(rec, req, ps)
 
@@ -279,11 +422,11 @@ func TestMovieHandler_DeleteAllMovies(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			DeleteAllMovie().
+			DeleteAllMovie(gomock.Any()).
 			Return(errors.New("")).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.DeleteAllMovies(rec, req, nil)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
@@ -296,10 +439,10 @@ func TestMovieHandler_DeleteAllMovies(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			DeleteAllMovie().
+			DeleteAllMovie(gomock.Any()).
 			Return(nil)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.DeleteAllMovies(rec, req, nil)
 
 		assert.Equal(t, http.StatusNoContent, rec.Code)
@@ -334,11 +477,11 @@ func TestMovieHandler_UpdateMovie(t *testing.T) {
 			mockService := service.NewMockIMovieService(gomock.NewController(t))
 			mockService.
 				EXPECT().
-				UpdateMovie(1, updatedMovie).
+				UpdateMovie(gomock.Any(), 1, updatedMovie).
 				Return(testError.returnedServiceErr).
 				Times(1)
 
-			mh := NewMovieHandler(mockService)
+			mh := NewMovieHandler(mockService, logging.NewNop())
 			mh.UpdateMovie(rec, req, ps)
 
 			assert.Equal(t, testError.expectedHTTPStatusCode, rec.Code)
@@ -352,11 +495,11 @@ func TestMovieHandler_UpdateMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			UpdateMovie(1, updatedMovie).
+			UpdateMovie(gomock.Any(), 1, updatedMovie).
 			Return(service.ErrMovieNotFound).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.UpdateMovie(rec, req, ps)
 
 		assert.Equal(t, http.StatusNotFound, rec.Code)
@@ -369,11 +512,11 @@ func TestMovieHandler_UpdateMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			UpdateMovie(1, updatedMovie).
+			UpdateMovie(gomock.Any(), 1, updatedMovie).
 			Return(errors.New("")).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.UpdateMovie(rec, req, ps)
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
@@ -386,14 +529,332 @@ func TestMovieHandler_UpdateMovie(t *testing.T) {
 		mockService := service.NewMockIMovieService(gomock.NewController(t))
 		mockService.
 			EXPECT().
-			UpdateMovie(1, updatedMovie).
+			UpdateMovie(gomock.Any(), 1, updatedMovie).
 			Return(nil).
 			Times(1)
 
-		mh := NewMovieHandler(mockService)
+		mh := NewMovieHandler(mockService, logging.NewNop())
 		mh.UpdateMovie(rec, req, ps)
 
 		assert.Equal(t, http.StatusNoContent, rec.Code)
 		assert.Equal(t, "Successfully Updated", rec.Body.String())
 	})
 }
+
+func TestMovieHandler_ConditionalGet(t *testing.T) {
+	updatedAt := time.Date(2022, 4, 24, 10, 0, 0, 0, time.UTC)
+	movie := model.Movie{ID: 1, Title: "Film", UpdatedAt: updatedAt}
+	ps := httprouter.Params{{Key: "id", Value: "1"}}
+
+	getMovie := func(t *testing.T, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/1", http.NoBody)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(movie, nil).Times(1)
+
+		NewMovieHandler(mockService, logging.NewNop()).GetMovie(rec, req, ps)
+		return rec
+	}
+
+	t.Run("emits validators", func(t *testing.T) {
+		rec := getMovie(t, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, no-cache", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "Sun, 24 Apr 2022 10:00:00 GMT", rec.Header().Get("Last-Modified"))
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+	})
+	t.Run("If-None-Match matches - NotModified", func(t *testing.T) {
+		etag := getMovie(t, nil).Header().Get("ETag")
+
+		rec := getMovie(t, map[string]string{"If-None-Match": `"other", W/` + etag})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, 0, rec.Body.Len())
+	})
+	t.Run("If-None-Match does not match - If-Modified-Since is ignored", func(t *testing.T) {
+		rec := getMovie(t, map[string]string{
+			"If-None-Match":     `"stale"`,
+			"If-Modified-Since": "Sun, 24 Apr 2022 10:00:00 GMT",
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("If-Modified-Since", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotModified, getMovie(t, map[string]string{"If-Modified-Since": "Sun, 24 Apr 2022 10:00:00 GMT"}).Code)
+		assert.Equal(t, http.StatusOK, getMovie(t, map[string]string{"If-Modified-Since": "Sun, 24 Apr 2022 09:59:59 GMT"}).Code)
+	})
+	t.Run("list ETag changes when any movie changes", func(t *testing.T) {
+		etagOf := func(movies []model.Movie) string {
+			req, _ := http.NewRequest(http.MethodGet, "/movies", http.NoBody)
+			rec := httptest.NewRecorder()
+
+			mockService := service.NewMockIMovieService(gomock.NewController(t))
+			mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return(movies, nil).Times(1)
+
+			NewMovieHandler(mockService, logging.NewNop()).GetMovies(rec, req, nil)
+			return rec.Header().Get("ETag")
+		}
+
+		other := model.Movie{ID: 2, Title: "Other", UpdatedAt: updatedAt}
+		original := etagOf([]model.Movie{movie, other})
+
+		renamed := other
+		renamed.Title = "Renamed"
+
+		assert.Equal(t, original, etagOf([]model.Movie{movie, other}))
+		assert.NotEqual(t, original, etagOf([]model.Movie{movie, renamed}))
+		assert.NotEqual(t, original, etagOf([]model.Movie{movie}))
+	})
+}
+
+func TestMovieHandler_ContentNegotiation(t *testing.T) {
+	movies := []model.Movie{
+		{ID: 1, Title: "Film, The", ReleaseYear: 1994, Score: 9.3, UpdatedAt: time.Date(2022, 4, 24, 10, 0, 0, 0, time.UTC)},
+	}
+
+	getMovies := func(t *testing.T, accept string, times int) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/movies", http.NoBody)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return(movies, nil).Times(times)
+
+		NewMovieHandler(mockService, logging.NewNop()).GetMovies(rec, req, nil)
+		return rec
+	}
+
+	t.Run("unsupported type - NotAcceptable", func(t *testing.T) {
+		rec := getMovies(t, "image/png", 0)
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	})
+	t.Run("XML", func(t *testing.T) {
+		rec := getMovies(t, "application/xml", 1)
+
+		assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `<movies><movie><id>1</id><title>Film, The</title><release_year>1994</release_year>`+
+			`<score>9.3</score><genres></genres><updated_at>2022-04-24T10:00:00Z</updated_at></movie></movies>`, rec.Body.String())
+	})
+	t.Run("CSV", func(t *testing.T) {
+		rec := getMovies(t, "text/csv", 1)
+
+		assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "id,title,release_year,score,rating_count,rating_mean,genres,runtime_minutes,synopsis,original_language,country,age_rating,poster_url,updated_at\n"+
+			"1,\"Film, The\",1994,9.3,0,0,,0,,,,,,2022-04-24T10:00:00Z\n", rec.Body.String())
+	})
+	t.Run("MessagePack", func(t *testing.T) {
+		rec := getMovies(t, "application/x-msgpack", 1)
+
+		assert.Equal(t, "application/msgpack", rec.Header().Get("Content-Type"))
+
+		var decoded []map[string]interface{}
+		assert.Nil(t, msgpack.Unmarshal(rec.Body.Bytes(), &decoded))
+		assert.Equal(t, "Film, The", decoded[0]["title"])
+	})
+	t.Run("formats have distinct ETags", func(t *testing.T) {
+		assert.NotEqual(t, getMovies(t, "application/json", 1).Header().Get("ETag"), getMovies(t, "text/csv", 1).Header().Get("ETag"))
+	})
+	t.Run("single movie as XML", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/1", http.NoBody)
+		req.Header.Set("Accept", "text/xml;q=0.9, application/json;q=0.1")
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.EXPECT().GetMovie(gomock.Any(), 1).Return(movies[0], nil).Times(1)
+
+		NewMovieHandler(mockService, logging.NewNop()).GetMovie(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, "application/xml", rec.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "<movie><id>1</id>"))
+	})
+}
+
+func TestMovieHandler_StreamMovies(t *testing.T) {
+	movies := []model.Movie{{ID: 1, Title: "Film"}, {ID: 2, Title: "Other"}}
+
+	streamMovies := func(t *testing.T, target, accept string, movies []model.Movie, streamErr error) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, target, http.NoBody)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			StreamMovies(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter model.MovieFilter, fn func(model.Movie) error) error {
+				for _, movie := range movies {
+					if err := fn(movie); err != nil {
+						return err
+					}
+				}
+				return streamErr
+			}).
+			Times(1)
+
+		NewMovieHandler(mockService, logging.NewNop()).GetMovies(rec, req, nil)
+		return rec
+	}
+
+	t.Run("JSON array", func(t *testing.T) {
+		rec := streamMovies(t, "/movies?stream=true", "", movies, nil)
+
+		var returnedMovies []model.Movie
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &returnedMovies))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "", rec.Header().Get("ETag"))
+		assert.Len(t, returnedMovies, 2)
+		assert.Equal(t, "Other", returnedMovies[1].Title)
+	})
+	t.Run("empty JSON array", func(t *testing.T) {
+		rec := streamMovies(t, "/movies?stream=true", "", nil, nil)
+
+		assert.Equal(t, "[]", rec.Body.String())
+	})
+	t.Run("NDJSON", func(t *testing.T) {
+		rec := streamMovies(t, "/movies", "application/x-ndjson", movies, nil)
+
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		assert.Len(t, lines, 2)
+
+		var movie model.Movie
+		assert.Nil(t, json.Unmarshal([]byte(lines[0]), &movie))
+		assert.Equal(t, 1, movie.ID)
+	})
+	t.Run("error before the first movie - InternalServerError", func(t *testing.T) {
+		rec := streamMovies(t, "/movies?stream=true", "", nil, errors.New("oops!"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+	t.Run("error after the first movie - truncated body", func(t *testing.T) {
+		rec := streamMovies(t, "/movies?stream=true", "", movies, errors.New("oops!"))
+
+		var returnedMovies []model.Movie
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotNil(t, json.Unmarshal(rec.Body.Bytes(), &returnedMovies))
+	})
+}
+
+// catalogService generates its movies on the fly, so that the benchmarks below only
+// measure the memory the handler itself holds on to.
+type catalogService struct {
+	service.IMovieService
+	size int
+}
+
+func (c catalogService) movie(i int) model.Movie {
+	return model.Movie{ID: i, Title: "The Shawshank Redemption", ReleaseYear: 1994, Score: 9.3}
+}
+
+func (c catalogService) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+	movies := make([]model.Movie, 0, c.size)
+	for i := 1; i <= c.size; i++ {
+		movies = append(movies, c.movie(i))
+	}
+	return movies, nil
+}
+
+func (c catalogService) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(model.Movie) error) error {
+	for i := 1; i <= c.size; i++ {
+		if err := fn(c.movie(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discardResponseWriter drops the body, sampling the heap every thousand writes.
+type discardResponseWriter struct {
+	header   http.Header
+	writes   int
+	peakHeap uint64
+}
+
+func (d *discardResponseWriter) Header() http.Header { return d.header }
+func (d *discardResponseWriter) WriteHeader(int)     {}
+
+func (d *discardResponseWriter) Write(b []byte) (int, error) {
+	if d.writes%1000 == 0 {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc > d.peakHeap {
+			d.peakHeap = stats.HeapAlloc
+		}
+	}
+	d.writes++
+	return len(b), nil
+}
+
+// go test ./handler -run '^$' -bench GetMovies -benchmem
+// peak-heap-B grows with the catalog when buffering; streaming keeps it bounded by
+// the GC heap target because each movie becomes garbage once it is written.
+func BenchmarkMovieHandler_GetMovies(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		mh := NewMovieHandler(catalogService{size: size}, logging.NewNop())
+
+		for _, target := range []string{"/movies", "/movies?stream=true"} {
+			b.Run(fmt.Sprintf("%s/%d", target, size), func(b *testing.B) {
+				req, _ := http.NewRequest(http.MethodGet, target, http.NoBody)
+				var peak uint64
+
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					runtime.GC()
+					var before runtime.MemStats
+					runtime.ReadMemStats(&before)
+					w := &discardResponseWriter{header: http.Header{}}
+					b.StartTimer()
+
+					mh.GetMovies(w, req, nil)
+
+					if w.peakHeap > before.HeapAlloc && w.peakHeap-before.HeapAlloc > peak {
+						peak = w.peakHeap - before.HeapAlloc
+					}
+				}
+				b.ReportMetric(float64(peak), "peak-heap-B")
+			})
+		}
+	}
+}
+
+func TestMovieHandler_GetMovieStats(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/stats?genre=Drama&limit=3", nil)
+		rec := httptest.NewRecorder()
+
+		stats := model.MovieStats{Count: 3, AverageScore: 9.17}
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetMovieStats(gomock.Any(), model.MovieFilter{Genre: "drama"}, 3).
+			Return(stats, nil).
+			Times(1)
+
+		NewMovieHandler(mockService, logging.NewNop()).GetMovieStats(rec, req, httprouter.Params{{Key: "id", Value: "stats"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var returned model.MovieStats
+		json.NewDecoder(rec.Body).Decode(&returned)
+		assert.Equal(t, stats, returned)
+	})
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		for _, query := range []string{"year_from=abc", "limit=ten"} {
+			req, _ := http.NewRequest(http.MethodGet, "/movies/stats?"+query, nil)
+			rec := httptest.NewRecorder()
+
+			mockService := service.NewMockIMovieService(gomock.NewController(t))
+
+			NewMovieHandler(mockService, logging.NewNop()).GetMovieStats(rec, req, httprouter.Params{{Key: "id", Value: "stats"}})
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/negotiation"
+	"github.com/vmihailenco/msgpack/v5"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type format struct {
+	contentType  string
+	aliases      []string
+	encodeMovie  func(movie model.Movie) ([]byte, error)
+	encodeMovies func(movies []model.Movie) ([]byte, error)
+}
+
+// formats are listed in order of preference, the first one is used for "*/*".
+var formats = []format{
+	{
+		contentType:  "application/json",
+		encodeMovie:  func(movie model.Movie) ([]byte, error) { return json.Marshal(movie) },
+		encodeMovies: func(movies []model.Movie) ([]byte, error) { return json.Marshal(movies) },
+	},
+	{
+		contentType:  ndjsonContentType,
+		encodeMovie:  func(movie model.Movie) ([]byte, error) { return encodeNDJSON([]model.Movie{movie}) },
+		encodeMovies: encodeNDJSON,
+	},
+	{
+		contentType: "application/xml",
+		aliases:     []string{"text/xml"},
+		encodeMovie: func(movie model.Movie) ([]byte, error) {
+			return xml.Marshal(struct {
+				XMLName xml.Name `xml:"movie"`
+				model.Movie
+			}{Movie: movie})
+		},
+		encodeMovies: func(movies []model.Movie) ([]byte, error) {
+			return xml.Marshal(struct {
+				XMLName xml.Name      `xml:"movies"`
+				Movies  []model.Movie `xml:"movie"`
+			}{Movies: movies})
+		},
+	},
+	{
+		contentType:  "text/csv",
+		encodeMovie:  func(movie model.Movie) ([]byte, error) { return encodeCSV([]model.Movie{movie}) },
+		encodeMovies: encodeCSV,
+	},
+	{
+		contentType:  "application/msgpack",
+		aliases:      []string{"application/x-msgpack", "application/vnd.msgpack"},
+		encodeMovie:  func(movie model.Movie) ([]byte, error) { return encodeMsgpack(movie) },
+		encodeMovies: func(movies []model.Movie) ([]byte, error) { return encodeMsgpack(movies) },
+	},
+}
+
+func supportedContentTypes() string {
+	contentTypes := make([]string, 0, len(formats))
+	for _, f := range formats {
+		contentTypes = append(contentTypes, f.contentType)
+	}
+	return strings.Join(contentTypes, ", ")
+}
+
+// negotiateFormat picks the format the Accept header prefers, or false when none of
+// the formats is acceptable.
+func negotiateFormat(accept string) (format, bool) {
+	var offers []string
+	byContentType := make(map[string]format)
+	for _, f := range formats {
+		for _, contentType := range append([]string{f.contentType}, f.aliases...) {
+			offers = append(offers, contentType)
+			byContentType[contentType] = f
+		}
+	}
+
+	contentType, ok := negotiation.MediaType(accept, offers)
+	return byContentType[contentType], ok
+}
+
+func encodeNDJSON(movies []model.Movie) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, movie := range movies {
+		if err := enc.Encode(movie); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeCSV(movies []model.Movie) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{
+		"id", "title", "release_year", "score", "rating_count", "rating_mean", "genres", "runtime_minutes", "synopsis",
+		"original_language", "country", "age_rating", "poster_url", "updated_at",
+	})
+	for _, movie := range movies {
+		w.Write([]string{
+			strconv.Itoa(movie.ID),
+			movie.Title,
+			strconv.Itoa(movie.ReleaseYear),
+			strconv.FormatFloat(movie.Score, 'f', -1, 64),
+			strconv.Itoa(movie.RatingCount),
+			strconv.FormatFloat(movie.RatingMean, 'f', -1, 64),
+			strings.Join(movie.Genres, "|"),
+			strconv.Itoa(movie.RuntimeMinutes),
+			movie.Synopsis,
+			movie.OriginalLanguage,
+			movie.Country,
+			movie.AgeRating,
+			movie.PosterURL,
+			movie.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func encodeMsgpack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+
+	err := enc.Encode(v)
+	return buf.Bytes(), err
+}
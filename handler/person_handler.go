@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type personHandler struct {
+	service service.IPersonService
+	logger  *slog.Logger
+}
+
+func NewPersonHandler(ps service.IPersonService, logger *slog.Logger) *personHandler {
+	return &personHandler{service: ps, logger: logger}
+}
+
+// curl localhost:8080/people | jq
+func (ph *personHandler) GetPeople(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	people, err := ph.service.GetPeople(r.Context())
+	if err != nil {
+		ph.logger.ErrorContext(r.Context(), "GetPeople failed", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "Unable to get all people")
+		return
+	}
+
+	writeJSON(w, r, ph.logger, people)
+}
+
+// curl localhost:8080/people/1 | jq
+func (ph *personHandler) GetPerson(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	person, err := ph.service.GetPerson(r.Context(), id)
+	if err != nil {
+		writeError(w, r, ph.logger, "GetPerson", err)
+		return
+	}
+
+	writeJSON(w, r, ph.logger, person)
+}
+
+/*
+curl -X POST localhost:8080/people \
+-H 'Content-Type: application/json' \
+-d '{ "name": "Sofia Coppola", "birth_year": 1971 }'
+*/
+func (ph *personHandler) CreatePerson(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var person model.Person
+	if err := json.NewDecoder(r.Body).Decode(&person); err != nil {
+		ph.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+
+	if err := ph.service.CreatePerson(r.Context(), person); err != nil {
+		writeError(w, r, ph.logger, "CreatePerson", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("Person is successfully created"))
+}
+
+// curl -X PATCH localhost:8080/people/1 -d '{ "name": "Frank Darabont" }'
+func (ph *personHandler) UpdatePerson(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	var person model.Person
+	if err := json.NewDecoder(r.Body).Decode(&person); err != nil {
+		ph.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+
+	if err := ph.service.UpdatePerson(r.Context(), id, person); err != nil {
+		writeError(w, r, ph.logger, "UpdatePerson", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ph *personHandler) DeletePerson(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	if err := ph.service.DeletePerson(r.Context(), id); err != nil {
+		writeError(w, r, ph.logger, "DeletePerson", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// curl "localhost:8080/people/1/movies?role=director" | jq
+func (ph *personHandler) GetPersonMovies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	movies, err := ph.service.GetPersonMovies(r.Context(), id, r.URL.Query().Get("role"))
+	if err != nil {
+		writeError(w, r, ph.logger, "GetPersonMovies", err)
+		return
+	}
+
+	writeJSON(w, r, ph.logger, movies)
+}
+
+// curl localhost:8080/movies/1/credits | jq
+func (ph *personHandler) GetMovieCredits(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	movieID, _ := strconv.Atoi(ps.ByName("id"))
+
+	credits, err := ph.service.GetMovieCredits(r.Context(), movieID)
+	if err != nil {
+		writeError(w, r, ph.logger, "GetMovieCredits", err)
+		return
+	}
+
+	writeJSON(w, r, ph.logger, credits)
+}
+
+/*
+curl -X POST localhost:8080/movies/1/credits \
+-H 'Content-Type: application/json' \
+-d '{ "person_id": 3, "role": "actor", "character": "Red", "billing_order": 3 }'
+*/
+func (ph *personHandler) AddCredit(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var credit model.Credit
+	if err := json.NewDecoder(r.Body).Decode(&credit); err != nil {
+		ph.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+	credit.MovieID, _ = strconv.Atoi(ps.ByName("id"))
+
+	if err := ph.service.AddCredit(r.Context(), credit); err != nil {
+		writeError(w, r, ph.logger, "AddCredit", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("Credit is successfully created"))
+}
+
+func (ph *personHandler) DeleteCredit(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	movieID, _ := strconv.Atoi(ps.ByName("id"))
+	creditID, _ := strconv.Atoi(ps.ByName("credit_id"))
+
+	if err := ph.service.DeleteCredit(r.Context(), movieID, creditID); err != nil {
+		writeError(w, r, ph.logger, "DeleteCredit", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
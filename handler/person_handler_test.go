@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPersonHandler_GetPersonMovies(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/people/7/movies?role=director", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIPersonService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetPersonMovies(gomock.Any(), 7, "director").
+			Return([]model.Movie{{ID: 3, Title: "The Dark Knight"}}, nil).
+			Times(1)
+
+		NewPersonHandler(mockService, logging.NewNop()).GetPersonMovies(rec, req, httprouter.Params{{Key: "id", Value: "7"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var movies []model.Movie
+		json.NewDecoder(rec.Body).Decode(&movies)
+		assert.Equal(t, []model.Movie{{ID: 3, Title: "The Dark Knight"}}, movies)
+	})
+	t.Run("Error - NotFound", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/people/7/movies", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIPersonService(gomock.NewController(t))
+		mockService.EXPECT().GetPersonMovies(gomock.Any(), 7, "").Return(nil, service.ErrPersonNotFound).Times(1)
+
+		NewPersonHandler(mockService, logging.NewNop()).GetPersonMovies(rec, req, httprouter.Params{{Key: "id", Value: "7"}})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/people/7/movies?role=gaffer", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIPersonService(gomock.NewController(t))
+		mockService.EXPECT().GetPersonMovies(gomock.Any(), 7, "gaffer").Return(nil, service.ErrRoleIsNotValid).Times(1)
+
+		NewPersonHandler(mockService, logging.NewNop()).GetPersonMovies(rec, req, httprouter.Params{{Key: "id", Value: "7"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestPersonHandler_GetMovieCredits(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		credits := []model.Credit{{ID: 1, MovieID: 1, PersonID: 1, PersonName: "Frank Darabont", Role: model.RoleDirector}}
+		req, _ := http.NewRequest(http.MethodGet, "/movies/1/credits", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIPersonService(gomock.NewController(t))
+		mockService.EXPECT().GetMovieCredits(gomock.Any(), 1).Return(credits, nil).Times(1)
+
+		NewPersonHandler(mockService, logging.NewNop()).GetMovieCredits(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var returned []model.Credit
+		json.NewDecoder(rec.Body).Decode(&returned)
+		assert.Equal(t, credits, returned)
+	})
+	t.Run("Error - InternalServerError", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/1/credits", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIPersonService(gomock.NewController(t))
+		mockService.EXPECT().GetMovieCredits(gomock.Any(), 1).Return(nil, errors.New("oops!")).Times(1)
+
+		NewPersonHandler(mockService, logging.NewNop()).GetMovieCredits(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestPersonHandler_AddCredit(t *testing.T) {
+	t.Run("Success - movie id comes from the path", func(t *testing.T) {
+		body := `{ "movie_id": 99, "person_id": 3, "role": "actor", "character": "Red", "billing_order": 3 }`
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/credits", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIPersonService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			AddCredit(gomock.Any(), model.Credit{MovieID: 1, PersonID: 3, Role: "actor", Character: "Red", BillingOrder: 3}).
+			Return(nil).
+			Times(1)
+
+		NewPersonHandler(mockService, logging.NewNop()).AddCredit(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+	t.Run("Error - invalid json", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/credits", strings.NewReader("{"))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIPersonService(gomock.NewController(t))
+		mockService.EXPECT().AddCredit(gomock.Any(), gomock.Any()).Times(0)
+
+		NewPersonHandler(mockService, logging.NewNop()).AddCredit(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
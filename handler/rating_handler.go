@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ratingHandler struct {
+	service service.IRatingService
+	logger  *slog.Logger
+}
+
+func NewRatingHandler(rs service.IRatingService, logger *slog.Logger) *ratingHandler {
+	return &ratingHandler{service: rs, logger: logger}
+}
+
+// curl localhost:8080/movies/1/ratings | jq
+func (rh *ratingHandler) GetRatings(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	movieID, _ := strconv.Atoi(ps.ByName("id"))
+
+	ratings, err := rh.service.GetRatings(r.Context(), movieID)
+	if err != nil {
+		writeError(w, r, rh.logger, "GetRatings", err)
+		return
+	}
+
+	writeJSON(w, r, rh.logger, ratings)
+}
+
+/*
+curl -X POST localhost:8080/movies/1/ratings \
+-H 'X-API-Key: change-me-user-key' \
+-H 'Content-Type: application/json' \
+-d '{ "value": 9 }'
+*/
+// The rating is given as the user the API key is bound to, a user_id in the body is
+// ignored.
+func (rh *ratingHandler) RateMovie(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, err := auth.UserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var rating model.Rating
+	if err := json.NewDecoder(r.Body).Decode(&rating); err != nil {
+		rh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+	rating.MovieID, _ = strconv.Atoi(ps.ByName("id"))
+	rating.UserID = userID
+
+	summary, err := rh.service.RateMovie(r.Context(), rating)
+	if err != nil {
+		writeError(w, r, rh.logger, "RateMovie", err)
+		return
+	}
+
+	writeJSON(w, r, rh.logger, summary)
+}
+
+// curl -X DELETE localhost:8080/movies/1/ratings/42
+func (rh *ratingHandler) DeleteRating(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	movieID, _ := strconv.Atoi(ps.ByName("id"))
+	userID, _ := strconv.Atoi(ps.ByName("user_id"))
+
+	summary, err := rh.service.DeleteRating(r.Context(), movieID, userID)
+	if err != nil {
+		writeError(w, r, rh.logger, "DeleteRating", err)
+		return
+	}
+
+	writeJSON(w, r, rh.logger, summary)
+}
+
+// curl localhost:8080/movies/1/reviews | jq
+func (rh *ratingHandler) GetReviews(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	movieID, _ := strconv.Atoi(ps.ByName("id"))
+
+	reviews, err := rh.service.GetReviews(r.Context(), movieID)
+	if err != nil {
+		writeError(w, r, rh.logger, "GetReviews", err)
+		return
+	}
+
+	writeJSON(w, r, rh.logger, reviews)
+}
+
+/*
+curl -X POST localhost:8080/movies/1/reviews \
+-H 'X-API-Key: change-me-user-key' \
+-H 'Content-Type: application/json' \
+-d '{ "body": "Hope is a good thing." }'
+*/
+// Like RateMovie, the review is written as the user the API key is bound to.
+func (rh *ratingHandler) AddReview(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, err := auth.UserID(r.Context())
+	if err != nil {
+		problem.Write(w, r, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var review model.Review
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		rh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+	review.MovieID, _ = strconv.Atoi(ps.ByName("id"))
+	review.UserID = userID
+
+	if err := rh.service.AddReview(r.Context(), review); err != nil {
+		writeError(w, r, rh.logger, "AddReview", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("Review is successfully created"))
+}
+
+func (rh *ratingHandler) DeleteReview(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	movieID, _ := strconv.Atoi(ps.ByName("id"))
+	reviewID, _ := strconv.Atoi(ps.ByName("review_id"))
+
+	if err := rh.service.DeleteReview(r.Context(), movieID, reviewID); err != nil {
+		writeError(w, r, rh.logger, "DeleteReview", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// asUser returns req as sent with an API key bound to userID.
+func asUser(req *http.Request, userID int) *http.Request {
+	return req.WithContext(auth.WithCaller(context.Background(), auth.Caller{Role: auth.RoleUser, UserID: userID}))
+}
+
+func TestRatingHandler_RateMovie(t *testing.T) {
+	t.Run("Success - rated as the user of the key", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/ratings", strings.NewReader(`{ "user_id": 7, "value": 9 }`))
+		req = asUser(req, 42)
+		rec := httptest.NewRecorder()
+
+		summary := model.RatingSummary{MovieID: 1, Count: 1, Mean: 9, Score: 7.2}
+		mockService := service.NewMockIRatingService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			RateMovie(gomock.Any(), model.Rating{MovieID: 1, UserID: 42, Value: 9}).
+			Return(summary, nil).
+			Times(1)
+
+		NewRatingHandler(mockService, logging.NewNop()).RateMovie(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var returned model.RatingSummary
+		json.NewDecoder(rec.Body).Decode(&returned)
+		assert.Equal(t, summary, returned)
+	})
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/ratings", strings.NewReader(`{ "value": 11 }`))
+		req = asUser(req, 42)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRatingService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			RateMovie(gomock.Any(), gomock.Any()).
+			Return(model.RatingSummary{}, service.ErrRatingIsNotValid).
+			Times(1)
+
+		NewRatingHandler(mockService, logging.NewNop()).RateMovie(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Error - NotFound", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/9/ratings", strings.NewReader(`{ "value": 9 }`))
+		req = asUser(req, 42)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRatingService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			RateMovie(gomock.Any(), gomock.Any()).
+			Return(model.RatingSummary{}, service.ErrMovieNotFound).
+			Times(1)
+
+		NewRatingHandler(mockService, logging.NewNop()).RateMovie(rec, req, httprouter.Params{{Key: "id", Value: "9"}})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Error - Forbidden for a key of no user", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/ratings", strings.NewReader(`{ "user_id": 42, "value": 9 }`))
+		req = req.WithContext(auth.WithCaller(context.Background(), auth.Caller{Role: auth.RoleEditor}))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRatingService(gomock.NewController(t))
+		mockService.EXPECT().RateMovie(gomock.Any(), gomock.Any()).Times(0)
+
+		NewRatingHandler(mockService, logging.NewNop()).RateMovie(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestRatingHandler_AddReview(t *testing.T) {
+	t.Run("Success - written as the user of the key", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/movies/1/reviews", strings.NewReader(`{ "user_id": 7, "body": "Hope is a good thing." }`))
+		req = asUser(req, 42)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRatingService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			AddReview(gomock.Any(), model.Review{MovieID: 1, UserID: 42, Body: "Hope is a good thing."}).
+			Return(nil).
+			Times(1)
+
+		NewRatingHandler(mockService, logging.NewNop()).AddReview(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+}
+
+func TestRatingHandler_DeleteReview(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, "/movies/1/reviews/3", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRatingService(gomock.NewController(t))
+		mockService.EXPECT().DeleteReview(gomock.Any(), 1, 3).Return(nil).Times(1)
+
+		NewRatingHandler(mockService, logging.NewNop()).
+			DeleteReview(rec, req, httprouter.Params{{Key: "id", Value: "1"}, {Key: "review_id", Value: "3"}})
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Error - NotFound", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, "/movies/1/reviews/3", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRatingService(gomock.NewController(t))
+		mockService.EXPECT().DeleteReview(gomock.Any(), 1, 3).Return(service.ErrReviewNotFound).Times(1)
+
+		NewRatingHandler(mockService, logging.NewNop()).
+			DeleteReview(rec, req, httprouter.Params{{Key: "id", Value: "1"}, {Key: "review_id", Value: "3"}})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type recommendationHandler struct {
+	service service.IRecommendationService
+	logger  *slog.Logger
+}
+
+func NewRecommendationHandler(rs service.IRecommendationService, logger *slog.Logger) *recommendationHandler {
+	return &recommendationHandler{service: rs, logger: logger}
+}
+
+// curl "localhost:8080/movies/1/similar?limit=5" | jq
+func (rh *recommendationHandler) GetSimilarMovies(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	movieID, _ := strconv.Atoi(ps.ByName("id"))
+
+	limit, err := parseLimit(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	similar, err := rh.service.GetSimilarMovies(r.Context(), movieID, limit)
+	if err != nil {
+		writeError(w, r, rh.logger, "GetSimilarMovies", err)
+		return
+	}
+
+	writeJSON(w, r, rh.logger, similar)
+}
+
+// curl "localhost:8080/users/1/recommendations?limit=5" | jq
+func (rh *recommendationHandler) GetRecommendations(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, _ := strconv.Atoi(ps.ByName("id"))
+
+	limit, err := parseLimit(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	recommendations, err := rh.service.GetRecommendations(r.Context(), userID, limit)
+	if err != nil {
+		writeError(w, r, rh.logger, "GetRecommendations", err)
+		return
+	}
+
+	writeJSON(w, r, rh.logger, recommendations)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecommendationHandler_GetSimilarMovies(t *testing.T) {
+	t.Run("Success - default limit", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/1/similar", nil)
+		rec := httptest.NewRecorder()
+
+		similar := []model.SimilarMovie{{Movie: model.Movie{ID: 2, Title: "The Godfather"}, Similarity: 0.53}}
+		mockService := service.NewMockIRecommendationService(gomock.NewController(t))
+		mockService.EXPECT().GetSimilarMovies(gomock.Any(), 1, defaultLimit).Return(similar, nil).Times(1)
+
+		NewRecommendationHandler(mockService, logging.NewNop()).GetSimilarMovies(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var returned []model.SimilarMovie
+		json.NewDecoder(rec.Body).Decode(&returned)
+		assert.Equal(t, similar, returned)
+	})
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/1/similar?limit=ten", nil)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRecommendationService(gomock.NewController(t))
+
+		NewRecommendationHandler(mockService, logging.NewNop()).GetSimilarMovies(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("Error - NotFound", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/9/similar", nil)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRecommendationService(gomock.NewController(t))
+		mockService.EXPECT().GetSimilarMovies(gomock.Any(), 9, defaultLimit).Return(nil, service.ErrMovieNotFound).Times(1)
+
+		NewRecommendationHandler(mockService, logging.NewNop()).GetSimilarMovies(rec, req, httprouter.Params{{Key: "id", Value: "9"}})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestRecommendationHandler_GetRecommendations(t *testing.T) {
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/1/recommendations?limit=500", nil)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIRecommendationService(gomock.NewController(t))
+		mockService.EXPECT().GetRecommendations(gomock.Any(), 1, 500).Return(nil, service.ErrLimitIsNotValid).Times(1)
+
+		NewRecommendationHandler(mockService, logging.NewNop()).GetRecommendations(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/service"
+	"log/slog"
+	"net/http"
+)
+
+func writeJSON(w http.ResponseWriter, r *http.Request, logger *slog.Logger, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.WarnContext(r.Context(), "writing response", "error", err)
+	}
+}
+
+// writeError maps the service errors to 400, 404 and 409, anything else is logged as
+// a failure of op and answered with 500.
+func writeError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, op string, err error) {
+	if service.IsValidationError(err) {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if service.IsNotFoundError(err) {
+		problem.Write(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if service.IsConflictError(err) {
+		problem.Write(w, r, http.StatusConflict, err.Error())
+		return
+	}
+
+	logger.ErrorContext(r.Context(), op+" failed", "error", err)
+	problem.Write(w, r, http.StatusInternalServerError, err.Error())
+}
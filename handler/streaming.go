@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"net/http"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// flushEvery bounds how many encoded movies wait in the response buffer.
+const flushEvery = 100
+
+// wantsStream reports whether GetMovies should stream instead of buffering the list.
+// Streamed responses carry no validators, as those need the complete body.
+func wantsStream(r *http.Request, f format) bool {
+	return f.contentType == ndjsonContentType ||
+		f.contentType == "application/json" && r.URL.Query().Get("stream") == "true"
+}
+
+// curl "localhost:8080/movies?stream=true"
+// curl -H "Accept: application/x-ndjson" localhost:8080/movies
+func (mh *movieHandler) streamMovies(w http.ResponseWriter, r *http.Request, f format, filter model.MovieFilter) {
+	ndjson := f.contentType == ndjsonContentType
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+
+	w.Header().Set("Content-Type", f.contentType)
+	w.Header().Set("Cache-Control", "no-store")
+
+	count := 0
+	err := mh.service.StreamMovies(r.Context(), filter, func(movie model.Movie) error {
+		if !ndjson {
+			separator := ","
+			if count == 0 {
+				separator = "["
+			}
+			if _, err := w.Write([]byte(separator)); err != nil {
+				return err
+			}
+		}
+
+		// Encode terminates every value with a newline, which is what NDJSON needs and
+		// is insignificant whitespace inside a JSON array.
+		if err := enc.Encode(movie); err != nil {
+			return err
+		}
+
+		count++
+		if flusher != nil && count%flushEvery == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	if err != nil {
+		// Once the first movie is out the status can no longer change: the client
+		// notices the truncated body instead.
+		mh.logger.ErrorContext(r.Context(), "StreamMovies failed", "error", err, "streamed", count)
+		if count == 0 {
+			problem.Write(w, r, http.StatusInternalServerError, "Unable to get all movies")
+		}
+		return
+	}
+
+	if !ndjson {
+		closing := "]"
+		if count == 0 {
+			closing = "[]"
+		}
+		w.Write([]byte(closing))
+	}
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type watchlistHandler struct {
+	service service.IWatchlistService
+	logger  *slog.Logger
+}
+
+func NewWatchlistHandler(ws service.IWatchlistService, logger *slog.Logger) *watchlistHandler {
+	return &watchlistHandler{service: ws, logger: logger}
+}
+
+// owner parses the user of /users/:id and checks that the caller acts as that user.
+// It writes the 400 or 403 itself and reports whether the handler may go on.
+func owner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) (int, bool) {
+	userID, ok := pathID(w, r, ps, "id")
+	if !ok {
+		return 0, false
+	}
+
+	if err := auth.CheckUser(r.Context(), userID); err != nil {
+		problem.Write(w, r, http.StatusForbidden, err.Error())
+		return 0, false
+	}
+	return userID, true
+}
+
+// pathID parses the integer path parameter name, writing a 400 when it is not one.
+func pathID(w http.ResponseWriter, r *http.Request, ps httprouter.Params, name string) (int, bool) {
+	id, err := strconv.Atoi(ps.ByName(name))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, name+" must be an integer")
+		return 0, false
+	}
+	return id, true
+}
+
+// curl -H 'X-API-Key: change-me-user-key' localhost:8080/users/42/watchlist | jq
+func (wh *watchlistHandler) GetWatchlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, ok := owner(w, r, ps)
+	if !ok {
+		return
+	}
+
+	items, err := wh.service.GetWatchlist(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, wh.logger, "GetWatchlist", err)
+		return
+	}
+
+	writeJSON(w, r, wh.logger, items)
+}
+
+// curl -X POST localhost:8080/users/42/watchlist -H 'X-API-Key: change-me-user-key' -d '{ "movie_id": 2 }'
+func (wh *watchlistHandler) AddToWatchlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, ok := owner(w, r, ps)
+	if !ok {
+		return
+	}
+
+	var item model.WatchlistItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		wh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+
+	if err := wh.service.AddToWatchlist(r.Context(), userID, item.MovieID); err != nil {
+		writeError(w, r, wh.logger, "AddToWatchlist", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("Movie is successfully added to the watchlist"))
+}
+
+type watchlistOrder struct {
+	MovieIDs []int `json:"movie_ids"`
+}
+
+// curl -X PUT localhost:8080/users/42/watchlist -H 'X-API-Key: change-me-user-key' -d '{ "movie_ids": [3, 1, 2] }'
+func (wh *watchlistHandler) ReorderWatchlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, ok := owner(w, r, ps)
+	if !ok {
+		return
+	}
+
+	var order watchlistOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		wh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+
+	if err := wh.service.ReorderWatchlist(r.Context(), userID, order.MovieIDs); err != nil {
+		writeError(w, r, wh.logger, "ReorderWatchlist", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (wh *watchlistHandler) RemoveFromWatchlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, ok := owner(w, r, ps)
+	if !ok {
+		return
+	}
+	movieID, ok := pathID(w, r, ps, "movie_id")
+	if !ok {
+		return
+	}
+
+	if err := wh.service.RemoveFromWatchlist(r.Context(), userID, movieID); err != nil {
+		writeError(w, r, wh.logger, "RemoveFromWatchlist", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// curl -H 'X-API-Key: change-me-user-key' localhost:8080/users/42/watched | jq
+func (wh *watchlistHandler) GetWatched(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, ok := owner(w, r, ps)
+	if !ok {
+		return
+	}
+
+	entries, err := wh.service.GetWatched(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, wh.logger, "GetWatched", err)
+		return
+	}
+
+	writeJSON(w, r, wh.logger, entries)
+}
+
+/*
+curl -X POST localhost:8080/users/42/watched \
+-H 'X-API-Key: change-me-user-key' \
+-H 'Content-Type: application/json' \
+-d '{ "movie_id": 2, "watched_at": "2022-09-24T20:30:00Z" }'
+*/
+func (wh *watchlistHandler) AddWatched(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, ok := owner(w, r, ps)
+	if !ok {
+		return
+	}
+
+	var entry model.WatchedEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		wh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+	entry.UserID = userID
+
+	if err := wh.service.AddWatched(r.Context(), entry); err != nil {
+		writeError(w, r, wh.logger, "AddWatched", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("Movie is successfully marked as watched"))
+}
+
+func (wh *watchlistHandler) DeleteWatched(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	userID, ok := owner(w, r, ps)
+	if !ok {
+		return
+	}
+	entryID, ok := pathID(w, r, ps, "entry_id")
+	if !ok {
+		return
+	}
+
+	if err := wh.service.DeleteWatched(r.Context(), userID, entryID); err != nil {
+		writeError(w, r, wh.logger, "DeleteWatched", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWatchlistHandler_ReorderWatchlist(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, "/users/1/watchlist", strings.NewReader(`{ "movie_ids": [3, 1, 2] }`))
+		req = asUser(req, 1)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWatchlistService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			ReorderWatchlist(gomock.Any(), 1, []int{3, 1, 2}).
+			Return(nil).
+			Times(1)
+
+		NewWatchlistHandler(mockService, logging.NewNop()).ReorderWatchlist(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, "/users/1/watchlist", strings.NewReader(`{ "movie_ids": [3] }`))
+		req = asUser(req, 1)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWatchlistService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			ReorderWatchlist(gomock.Any(), 1, []int{3}).
+			Return(service.ErrWatchlistOrderIsNotValid).
+			Times(1)
+
+		NewWatchlistHandler(mockService, logging.NewNop()).ReorderWatchlist(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestWatchlistHandler_RemoveFromWatchlist(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, "/users/1/watchlist/2", nil)
+		req = asUser(req, 1)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWatchlistService(gomock.NewController(t))
+		mockService.EXPECT().RemoveFromWatchlist(gomock.Any(), 1, 2).Return(nil).Times(1)
+
+		NewWatchlistHandler(mockService, logging.NewNop()).
+			RemoveFromWatchlist(rec, req, httprouter.Params{{Key: "id", Value: "1"}, {Key: "movie_id", Value: "2"}})
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("Error - NotFound", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, "/users/1/watchlist/9", nil)
+		req = asUser(req, 1)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWatchlistService(gomock.NewController(t))
+		mockService.EXPECT().RemoveFromWatchlist(gomock.Any(), 1, 9).Return(service.ErrWatchlistItemNotFound).Times(1)
+
+		NewWatchlistHandler(mockService, logging.NewNop()).
+			RemoveFromWatchlist(rec, req, httprouter.Params{{Key: "id", Value: "1"}, {Key: "movie_id", Value: "9"}})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestWatchlistHandler_GetWatchlist(t *testing.T) {
+	t.Run("Error - Forbidden for another user", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/2/watchlist", nil)
+		req = asUser(req, 1)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWatchlistService(gomock.NewController(t))
+		mockService.EXPECT().GetWatchlist(gomock.Any(), gomock.Any()).Times(0)
+
+		NewWatchlistHandler(mockService, logging.NewNop()).GetWatchlist(rec, req, httprouter.Params{{Key: "id", Value: "2"}})
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("Success - admin reads every watchlist", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/2/watchlist", nil)
+		req = req.WithContext(auth.WithCaller(context.Background(), auth.Caller{Role: auth.RoleAdmin}))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWatchlistService(gomock.NewController(t))
+		mockService.EXPECT().GetWatchlist(gomock.Any(), 2).Return(nil, nil).Times(1)
+
+		NewWatchlistHandler(mockService, logging.NewNop()).GetWatchlist(rec, req, httprouter.Params{{Key: "id", Value: "2"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("Error - BadRequest for a non-numeric user id", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/users/me/watchlist", nil)
+		req = req.WithContext(auth.WithCaller(context.Background(), auth.Caller{Role: auth.RoleAdmin}))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWatchlistService(gomock.NewController(t))
+		mockService.EXPECT().GetWatchlist(gomock.Any(), gomock.Any()).Times(0)
+
+		NewWatchlistHandler(mockService, logging.NewNop()).GetWatchlist(rec, req, httprouter.Params{{Key: "id", Value: "me"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/julienschmidt/httprouter"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type webhookHandler struct {
+	service service.IWebhookService
+	logger  *slog.Logger
+}
+
+func NewWebhookHandler(ws service.IWebhookService, logger *slog.Logger) *webhookHandler {
+	return &webhookHandler{service: ws, logger: logger}
+}
+
+// curl localhost:8080/webhooks -H 'X-API-Key: change-me-admin-key' | jq
+func (wh *webhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	webhooks, err := wh.service.GetWebhooks(r.Context())
+	if err != nil {
+		writeError(w, r, wh.logger, "GetWebhooks", err)
+		return
+	}
+
+	writeJSON(w, r, wh.logger, webhooks)
+}
+
+func (wh *webhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	webhook, err := wh.service.GetWebhook(r.Context(), id)
+	if err != nil {
+		writeError(w, r, wh.logger, "GetWebhook", err)
+		return
+	}
+
+	writeJSON(w, r, wh.logger, webhook)
+}
+
+/*
+curl -X POST localhost:8080/webhooks \
+-H 'X-API-Key: change-me-admin-key' \
+-d '{ "url": "https://example.com/hooks/movies", "events": ["movie.created", "movie.deleted"] }'
+*/
+func (wh *webhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var webhook model.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		wh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+
+	created, err := wh.service.CreateWebhook(r.Context(), webhook)
+	if err != nil {
+		writeError(w, r, wh.logger, "CreateWebhook", err)
+		return
+	}
+
+	w.Header().Set("Location", "/webhooks/"+strconv.Itoa(created.ID))
+	// The secret is only shown here, it must not be kept by caches nor by the
+	// idempotency keys.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, r, wh.logger, created)
+}
+
+// curl -X PATCH localhost:8080/webhooks/1 -H 'X-API-Key: change-me-admin-key' -d '{ "active": false }'
+func (wh *webhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	var patch model.WebhookPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		wh.logger.WarnContext(r.Context(), "decoding request body", "error", err)
+		problem.Write(w, r, http.StatusBadRequest, "error when decoding json")
+		return
+	}
+
+	if err := wh.service.UpdateWebhook(r.Context(), id, patch); err != nil {
+		writeError(w, r, wh.logger, "UpdateWebhook", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (wh *webhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id, _ := strconv.Atoi(ps.ByName("id"))
+
+	if err := wh.service.DeleteWebhook(r.Context(), id); err != nil {
+		writeError(w, r, wh.logger, "DeleteWebhook", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// The dead letters of a webhook are its deliveries in the dead status:
+// curl "localhost:8080/webhooks/1/deliveries?status=dead&limit=20" -H 'X-API-Key: change-me-admin-key' | jq
+func (wh *webhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	webhookID, _ := strconv.Atoi(ps.ByName("id"))
+
+	limit, err := parseLimit(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := model.DeliveryStatus(r.URL.Query().Get("status"))
+
+	deliveries, err := wh.service.GetDeliveries(r.Context(), webhookID, status, limit)
+	if err != nil {
+		writeError(w, r, wh.logger, "GetDeliveries", err)
+		return
+	}
+
+	writeJSON(w, r, wh.logger, deliveries)
+}
+
+// curl -X POST localhost:8080/webhooks/1/deliveries/7/redeliver -H 'X-API-Key: change-me-admin-key'
+func (wh *webhookHandler) RedeliverDelivery(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	webhookID, _ := strconv.Atoi(ps.ByName("id"))
+	deliveryID, _ := strconv.ParseInt(ps.ByName("delivery_id"), 10, 64)
+
+	if err := wh.service.RedeliverDelivery(r.Context(), webhookID, deliveryID); err != nil {
+		writeError(w, r, wh.logger, "RedeliverDelivery", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
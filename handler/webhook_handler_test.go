@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{ "url": "https://example.com/hooks" }`))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWebhookService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			CreateWebhook(gomock.Any(), model.Webhook{URL: "https://example.com/hooks"}).
+			Return(model.Webhook{ID: 4, URL: "https://example.com/hooks", Secret: "whsec_test", Active: true}, nil).
+			Times(1)
+
+		NewWebhookHandler(mockService, logging.NewNop()).CreateWebhook(rec, req, nil)
+
+		var created model.Webhook
+		json.NewDecoder(rec.Body).Decode(&created)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "/webhooks/4", rec.Header().Get("Location"))
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "whsec_test", created.Secret)
+	})
+	t.Run("Error - BadRequest", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{ "url": "example.com" }`))
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWebhookService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			CreateWebhook(gomock.Any(), gomock.Any()).
+			Return(model.Webhook{}, service.ErrWebhookURLIsNotValid).
+			Times(1)
+
+		NewWebhookHandler(mockService, logging.NewNop()).CreateWebhook(rec, req, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestWebhookHandler_GetDeliveries(t *testing.T) {
+	t.Run("Success - dead letters", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/webhooks/1/deliveries?status=dead&limit=5", nil)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWebhookService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetDeliveries(gomock.Any(), 1, model.DeliveryDead, 5).
+			Return([]model.WebhookDelivery{{ID: 3, WebhookID: 1, Status: model.DeliveryDead}}, nil).
+			Times(1)
+
+		NewWebhookHandler(mockService, logging.NewNop()).GetDeliveries(rec, req, httprouter.Params{{Key: "id", Value: "1"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"dead"`)
+	})
+	t.Run("Error - NotFound", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/webhooks/9/deliveries", nil)
+		rec := httptest.NewRecorder()
+
+		mockService := service.NewMockIWebhookService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetDeliveries(gomock.Any(), 9, model.DeliveryStatus(""), defaultLimit).
+			Return(nil, service.ErrWebhookNotFound).
+			Times(1)
+
+		NewWebhookHandler(mockService, logging.NewNop()).GetDeliveries(rec, req, httprouter.Params{{Key: "id", Value: "9"}})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestWebhookHandler_RedeliverDelivery(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "/webhooks/1/deliveries/3/redeliver", nil)
+	rec := httptest.NewRecorder()
+
+	mockService := service.NewMockIWebhookService(gomock.NewController(t))
+	mockService.
+		EXPECT().
+		RedeliverDelivery(gomock.Any(), 1, int64(3)).
+		Return(nil).
+		Times(1)
+
+	NewWebhookHandler(mockService, logging.NewNop()).RedeliverDelivery(rec, req,
+		httprouter.Params{{Key: "id", Value: "1"}, {Key: "delivery_id", Value: "3"}})
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
@@ -0,0 +1,40 @@
+package health
+
+import (
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type healthHandler struct {
+	registry *Registry
+}
+
+func NewHealthHandler(registry *Registry) *healthHandler {
+	return &healthHandler{registry: registry}
+}
+
+// curl localhost:8080/healthz
+func (hh *healthHandler) Liveness(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	writeReport(w, Report{Status: StatusUp, Checks: []CheckResult{}})
+}
+
+// curl localhost:8080/readyz | jq
+func (hh *healthHandler) Readiness(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	writeReport(w, hh.registry.Run(r.Context()))
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	jsonStr, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != StatusUp {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(jsonStr)
+}
@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register(NewCheck("repository", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", http.NoBody)
+	rec := httptest.NewRecorder()
+	NewHealthHandler(registry).Liveness(rec, req, nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	t.Run("All checks up", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register(NewCheck("repository", func(ctx context.Context) error { return nil }))
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+		rec := httptest.NewRecorder()
+		NewHealthHandler(registry).Readiness(rec, req, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var report Report
+		json.NewDecoder(rec.Body).Decode(&report)
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Equal(t, "repository", report.Checks[0].Name)
+		assert.NotEmpty(t, report.Checks[0].Latency)
+	})
+	t.Run("Failing and timed out checks - ServiceUnavailable", func(t *testing.T) {
+		registry := NewRegistry(10 * time.Millisecond)
+		registry.Register(NewCheck("repository", func(ctx context.Context) error { return nil }))
+		registry.Register(NewCheck("migrations", func(ctx context.Context) error {
+			return errors.New("movies table is missing")
+		}))
+		registry.Register(NewCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+		rec := httptest.NewRecorder()
+		NewHealthHandler(registry).Readiness(rec, req, nil)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		var report Report
+		json.NewDecoder(rec.Body).Decode(&report)
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, StatusUp, report.Checks[0].Status)
+		assert.Equal(t, "movies table is missing", report.Checks[1].Error)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[2].Error)
+	})
+}
@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// IChecker is implemented by every dependency the server needs before it can take traffic.
+type IChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func NewCheck(name string, check func(ctx context.Context) error) IChecker {
+	return &checkFunc{name: name, check: check}
+}
+
+func (c *checkFunc) Name() string {
+	return c.name
+}
+
+func (c *checkFunc) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+type CheckResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type Registry struct {
+	mu       sync.RWMutex
+	checkers []IChecker
+	timeout  time.Duration
+}
+
+// NewRegistry returns an empty registry. Every check is cancelled after timeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+func (r *Registry) Register(checker IChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, checker)
+}
+
+// Run executes all checks concurrently. The report is up only if every check is up.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := append([]IChecker(nil), r.checkers...)
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make([]CheckResult, len(checkers))}
+
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker IChecker) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, checker IChecker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+
+	result := CheckResult{
+		Name:    checker.Name(),
+		Status:  StatusUp,
+		Latency: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,225 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/julienschmidt/httprouter"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	KeyHeader      = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+
+	// maxBodySize bounds the request bodies, which are read whole to be hashed.
+	maxBodySize = 1 << 20
+
+	// storeTimeout bounds the Save and Release that run once the request is done.
+	storeTimeout = 5 * time.Second
+)
+
+// skippedHeaders are left out of a stored response, they belong to the request that
+// produced it rather than to the response.
+var skippedHeaders = []string{"Date", "X-Request-Id", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"}
+
+// contentHeaders describe the body, they are left out with it.
+var contentHeaders = []string{"Content-Type", "Content-Length", "Content-Encoding"}
+
+type Config struct {
+	// Window is how long a key is remembered after its first use.
+	Window time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{Window: 24 * time.Hour}
+}
+
+type Idempotency struct {
+	store  IStore
+	config Config
+	logger *slog.Logger
+}
+
+func New(store IStore, config Config, logger *slog.Logger) *Idempotency {
+	return &Idempotency{store: store, config: config, logger: logger}
+}
+
+// Handle makes next idempotent for the requests carrying an Idempotency-Key header.
+// The first request with a key runs next and its response is stored, the retries
+// within the window get that response again. Reusing a key for a different request is
+// answered with 422 and retrying while the first request still runs with 409. Keys
+// are scoped to the client, like the rate limits.
+//
+// Server errors are not stored, so the request can be retried with the same key. The
+// request is only stored as a hash, and the body of a response sent with Cache-Control:
+// no-store, such as a secret shown once, is not stored either: its retries get the
+// status and the headers alone.
+func (i *Idempotency) Handle(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		key := r.Header.Get(KeyHeader)
+		if key == "" {
+			next(w, r, ps)
+			return
+		}
+		if len(key) > maxKeyLength {
+			problem.Write(w, r, http.StatusBadRequest, "Idempotency-Key cannot be longer than 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				problem.Write(w, r, http.StatusRequestEntityTooLarge, "request body cannot be larger than 1 MiB")
+				return
+			}
+			problem.Write(w, r, http.StatusBadRequest, "error when reading the request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		key = clientKey(r) + ":" + key
+		hash := requestHash(r, body)
+		record, reserved, err := i.store.Reserve(r.Context(), key, hash, i.config.Window)
+		if err != nil {
+			// Prefer serving the request over failing it when the store is unavailable.
+			i.logger.ErrorContext(r.Context(), "idempotency store", "error", err)
+			next(w, r, ps)
+			return
+		}
+
+		if !reserved {
+			i.replay(w, r, record, hash)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		saved := false
+		defer func() {
+			// Runs on panics as well, the recovery middleware answers those with 500.
+			if !saved {
+				ctx, cancel := detached(r)
+				defer cancel()
+				if err := i.store.Release(ctx, key); err != nil {
+					i.logger.ErrorContext(ctx, "releasing idempotency key", "error", err)
+				}
+			}
+		}()
+
+		next(rec, r, ps)
+
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+		ctx, cancel := detached(r)
+		defer cancel()
+		if err := i.store.Save(ctx, key, rec.response()); err != nil {
+			i.logger.ErrorContext(ctx, "saving idempotent response", "error", err)
+			return
+		}
+		saved = true
+	}
+}
+
+// detached keeps the store writes going when the client gave up on the request, e.g.
+// timed out and disconnected: the response of a request that ran must still be saved
+// for its retry, and a key must not stay in progress for the whole window.
+func detached(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(r.Context()), storeTimeout)
+}
+
+func (i *Idempotency) replay(w http.ResponseWriter, r *http.Request, record Record, hash string) {
+	if record.RequestHash != hash {
+		problem.Write(w, r, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+		return
+	}
+	if record.Response == nil {
+		w.Header().Set("Retry-After", "1")
+		problem.Write(w, r, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+		return
+	}
+
+	for name, values := range record.Response.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(record.Response.Status)
+	w.Write(record.Response.Body)
+}
+
+// requestHash fingerprints the method, target and body of a request.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// clientKey identifies the caller by API key when Authorize accepted it, otherwise by
+// remote IP. API keys are hashed so that they are not written to the store.
+func clientKey(r *http.Request) string {
+	_, authorized := auth.CallerFrom(r.Context())
+	if key := auth.APIKey(r); authorized && key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// responseRecorder writes through to the client and keeps a copy of the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.header == nil {
+		rr.status = status
+		rr.header = rr.Header().Clone()
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.header == nil {
+		rr.WriteHeader(http.StatusOK)
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) response() Response {
+	header := rr.header
+	if header == nil {
+		header = rr.Header().Clone()
+	}
+	for _, name := range skippedHeaders {
+		header.Del(name)
+	}
+	if strings.Contains(header.Get("Cache-Control"), "no-store") {
+		for _, name := range contentHeaders {
+			header.Del(name)
+		}
+		return Response{Status: rr.status, Header: header}
+	}
+	return Response{Status: rr.status, Header: header, Body: rr.body.Bytes()}
+}
@@ -0,0 +1,296 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestHandler answers 201 with a running count of the handled requests, or status
+// when it is set.
+func newTestHandler(store IStore, calls *int, status *int) httprouter.Handle {
+	idem := New(store, Config{Window: time.Hour}, logging.NewNop())
+
+	return idem.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		*calls++
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Location", "/movies/1")
+		if status != nil && *status != 0 {
+			w.WriteHeader(*status)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(strings.Repeat("+", *calls) + string(body)))
+	})
+}
+
+func serve(h httprouter.Handle, key, body, remoteAddr string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/movies", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	if key != "" {
+		req.Header.Set(KeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, req, nil)
+	return rec
+}
+
+func TestIdempotency_Handle(t *testing.T) {
+	t.Run("Without a key - every request runs", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(NewInMemoryStore(), &calls, nil)
+
+		serve(h, "", "{}", "10.0.0.1:1234")
+		serve(h, "", "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("Retry - original response is replayed", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(NewInMemoryStore(), &calls, nil)
+
+		first := serve(h, "abc", "{}", "10.0.0.1:1234")
+		retry := serve(h, "abc", "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "+{}", retry.Body.String())
+		assert.Equal(t, "/movies/1", retry.Header().Get("Location"))
+		assert.Equal(t, "true", retry.Header().Get(ReplayedHeader))
+		assert.Empty(t, first.Header().Get(ReplayedHeader))
+	})
+	t.Run("Reused key with another body - UnprocessableEntity", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(NewInMemoryStore(), &calls, nil)
+
+		serve(h, "abc", `{"title": "A"}`, "10.0.0.1:1234")
+		rec := serve(h, "abc", `{"title": "B"}`, "10.0.0.1:1234")
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+	t.Run("Keys are scoped to the client", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(NewInMemoryStore(), &calls, nil)
+
+		serve(h, "abc", "{}", "10.0.0.1:1234")
+		rec := serve(h, "abc", "{}", "10.0.0.2:1234")
+
+		assert.Equal(t, 2, calls)
+		assert.Empty(t, rec.Header().Get(ReplayedHeader))
+	})
+	t.Run("Keys are scoped to the api key only once it is authorized", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(NewInMemoryStore(), &calls, nil)
+		withAPIKey := func(h httprouter.Handle, authorized bool) httprouter.Handle {
+			return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+				r.Header.Set(auth.APIKeyHeader, "some-key")
+				if authorized {
+					r = r.WithContext(auth.WithCaller(r.Context(), auth.Caller{Role: auth.RoleEditor}))
+				}
+				h(w, r, ps)
+			}
+		}
+
+		serve(withAPIKey(h, false), "abc", "{}", "10.0.0.1:1234")
+		serve(withAPIKey(h, false), "abc", "{}", "10.0.0.2:1234")
+		assert.Equal(t, 2, calls)
+
+		serve(withAPIKey(h, true), "abc", "{}", "10.0.0.1:1234")
+		rec := serve(withAPIKey(h, true), "abc", "{}", "10.0.0.2:1234")
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, "true", rec.Header().Get(ReplayedHeader))
+	})
+	t.Run("Server error - key is released", func(t *testing.T) {
+		calls, status := 0, http.StatusInternalServerError
+		h := newTestHandler(NewInMemoryStore(), &calls, &status)
+
+		assert.Equal(t, http.StatusInternalServerError, serve(h, "abc", "{}", "10.0.0.1:1234").Code)
+		status = 0
+		assert.Equal(t, http.StatusCreated, serve(h, "abc", "{}", "10.0.0.1:1234").Code)
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("Client error - response is replayed", func(t *testing.T) {
+		calls, status := 0, http.StatusBadRequest
+		h := newTestHandler(NewInMemoryStore(), &calls, &status)
+
+		serve(h, "abc", "{}", "10.0.0.1:1234")
+		status = 0
+		rec := serve(h, "abc", "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("Request in progress - Conflict", func(t *testing.T) {
+		store := NewInMemoryStore()
+		var inner *httptest.ResponseRecorder
+		idem := New(store, Config{Window: time.Hour}, logging.NewNop())
+		var h httprouter.Handle
+		h = idem.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			inner = serve(h, "abc", "{}", "10.0.0.1:1234")
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		serve(h, "abc", "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, http.StatusConflict, inner.Code)
+		assert.Equal(t, "1", inner.Header().Get("Retry-After"))
+	})
+	t.Run("Panic - key is released", func(t *testing.T) {
+		store := NewInMemoryStore()
+		idem := New(store, Config{Window: time.Hour}, logging.NewNop())
+		h := idem.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			panic("oops!")
+		})
+
+		assert.Panics(t, func() { serve(h, "abc", "{}", "10.0.0.1:1234") })
+
+		_, reserved, _ := store.Reserve(context.Background(), "ip:10.0.0.1:abc", "", time.Hour)
+		assert.True(t, reserved)
+	})
+	t.Run("Too long key - BadRequest", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(NewInMemoryStore(), &calls, nil)
+
+		rec := serve(h, strings.Repeat("k", 256), "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 0, calls)
+	})
+	t.Run("Too large body - RequestEntityTooLarge", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(NewInMemoryStore(), &calls, nil)
+
+		rec := serve(h, "abc", strings.Repeat("a", maxBodySize+1), "10.0.0.1:1234")
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, 0, calls)
+	})
+	t.Run("No-store response - its body is not stored", func(t *testing.T) {
+		store := NewInMemoryStore()
+		calls := 0
+		h := New(store, Config{Window: time.Hour}, logging.NewNop()).
+			Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				calls++
+				w.Header().Set("Location", "/webhooks/1")
+				w.Header().Set("Cache-Control", "no-store")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"secret":"s3cr3t"}`))
+			})
+
+		first := serve(h, "abc", "{}", "10.0.0.1:1234")
+		retry := serve(h, "abc", "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, `{"secret":"s3cr3t"}`, first.Body.String())
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "/webhooks/1", retry.Header().Get("Location"))
+		assert.Empty(t, retry.Header().Get("Content-Type"))
+		assert.Empty(t, retry.Body.String())
+
+		record, _, _ := store.Reserve(context.Background(), "ip:10.0.0.1:abc", "", time.Hour)
+		assert.NotNil(t, record.Response)
+		assert.Empty(t, record.Response.Body)
+	})
+	t.Run("Failing store - request runs anyway", func(t *testing.T) {
+		calls := 0
+		h := newTestHandler(failingStore{}, &calls, nil)
+
+		rec := serve(h, "abc", "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("Client disconnects - response is still saved", func(t *testing.T) {
+		idem := New(cancellableStore{NewInMemoryStore()}, Config{Window: time.Hour}, logging.NewNop())
+		calls := 0
+		ctx, cancel := context.WithCancel(context.Background())
+		h := idem.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			calls++
+			// The client times out once the movie is created.
+			cancel()
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("created"))
+		})
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/movies", strings.NewReader("{}"))
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(KeyHeader, "abc")
+		h(httptest.NewRecorder(), req, nil)
+
+		retry := serve(h, "abc", "{}", "10.0.0.1:1234")
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "created", retry.Body.String())
+	})
+}
+
+// cancellableStore fails the writes on a cancelled context, like the PostgreSQL store.
+type cancellableStore struct {
+	IStore
+}
+
+func (s cancellableStore) Save(ctx context.Context, key string, response Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.IStore.Save(ctx, key, response)
+}
+
+func (s cancellableStore) Release(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.IStore.Release(ctx, key)
+}
+
+type failingStore struct{}
+
+func (failingStore) Reserve(ctx context.Context, key string, requestHash string, ttl time.Duration) (Record, bool, error) {
+	return Record{}, false, errors.New("oops!")
+}
+
+func (failingStore) Save(ctx context.Context, key string, response Response) error {
+	return errors.New("oops!")
+}
+
+func (failingStore) Release(ctx context.Context, key string) error {
+	return errors.New("oops!")
+}
+
+func TestInMemoryStore_Window(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewInMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, reserved, _ := store.Reserve(ctx, "abc", "hash", time.Hour)
+	assert.True(t, reserved)
+	store.Save(ctx, "abc", Response{Status: http.StatusCreated})
+
+	now = now.Add(59 * time.Minute)
+	record, reserved, _ := store.Reserve(ctx, "abc", "other", time.Hour)
+	assert.False(t, reserved)
+	assert.Equal(t, "hash", record.RequestHash)
+	assert.Equal(t, http.StatusCreated, record.Response.Status)
+
+	now = now.Add(time.Minute)
+	_, reserved, _ = store.Reserve(ctx, "abc", "other", time.Hour)
+	assert.True(t, reserved)
+
+	store.Release(ctx, "abc")
+	assert.Empty(t, store.entries)
+}
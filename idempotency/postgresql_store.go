@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// postgresqlStore keeps the keys in the idempotency_keys table of the movie database,
+// created by the repository migrations, so that every server instance shares them.
+type postgresqlStore struct {
+	connectionPool *sql.DB
+}
+
+func NewPostgreSQLStore(connectionPool *sql.DB) *postgresqlStore {
+	return &postgresqlStore{connectionPool: connectionPool}
+}
+
+// reserveKey inserts the key, or takes over an expired one. Nothing is returned when
+// the key is in use.
+const reserveKey = `INSERT INTO idempotency_keys (key, request_hash, expires_at)
+VALUES ($1, $2, now() + $3 * interval '1 millisecond')
+ON CONFLICT (key) DO UPDATE SET
+    request_hash = EXCLUDED.request_hash,
+    status       = NULL,
+    header       = NULL,
+    body         = NULL,
+    created_at   = now(),
+    expires_at   = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= now()
+RETURNING key`
+
+func (p *postgresqlStore) Reserve(ctx context.Context, key string, requestHash string, ttl time.Duration) (Record, bool, error) {
+	var reserved string
+	err := p.connectionPool.QueryRowContext(ctx, reserveKey, key, requestHash, ttl.Milliseconds()).Scan(&reserved)
+	if err == nil {
+		return Record{}, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return Record{}, false, err
+	}
+
+	var record Record
+	var status sql.NullInt64
+	var header []byte
+	var body []byte
+	err = p.connectionPool.QueryRowContext(ctx,
+		"SELECT request_hash, status, header, body FROM idempotency_keys WHERE key = $1", key).
+		Scan(&record.RequestHash, &status, &header, &body)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released in the meantime, answered like a request in progress so that the
+		// client tries again.
+		return Record{RequestHash: requestHash}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	if status.Valid {
+		response := Response{Status: int(status.Int64), Header: http.Header{}, Body: body}
+		if err := json.Unmarshal(header, &response.Header); err != nil {
+			return Record{}, false, err
+		}
+		record.Response = &response
+	}
+	return record, false, nil
+}
+
+func (p *postgresqlStore) Save(ctx context.Context, key string, response Response) error {
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.connectionPool.ExecContext(ctx,
+		"UPDATE idempotency_keys SET status = $2, header = $3, body = $4 WHERE key = $1",
+		key, response.Status, header, response.Body)
+	return err
+}
+
+func (p *postgresqlStore) Release(ctx context.Context, key string) error {
+	_, err := p.connectionPool.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND status IS NULL", key)
+	return err
+}
+
+// DeleteExpired purges the keys whose window has passed, Reserve only takes over the
+// expired keys that are used again.
+func (p *postgresqlStore) DeleteExpired(ctx context.Context) error {
+	_, err := p.connectionPool.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= now()")
+	return err
+}
@@ -0,0 +1,107 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is what a request answered, replayed to the retries with the same key.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// Record is the stored state of a key. Response is nil while the first request with
+// the key is still running.
+type Record struct {
+	RequestHash string
+	Response    *Response
+}
+
+// IStore keeps the idempotency keys. Implementations shared by several server
+// instances must apply Reserve atomically so that only one request runs per key.
+type IStore interface {
+	// Reserve stores key for the request hashed to requestHash until ttl passes. When
+	// key is already stored it returns its record and false instead.
+	Reserve(ctx context.Context, key string, requestHash string, ttl time.Duration) (Record, bool, error)
+	// Save stores the response of the request that reserved key.
+	Save(ctx context.Context, key string, response Response) error
+	// Release forgets a key whose request did not complete, so that it can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+type entry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+type inmemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+const sweepInterval = time.Minute
+
+func NewInMemoryStore() *inmemoryStore {
+	return &inmemoryStore{
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+func (s *inmemoryStore) Reserve(ctx context.Context, key string, requestHash string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		return e.record, false, nil
+	}
+
+	s.entries[key] = &entry{
+		record:    Record{RequestHash: requestHash},
+		expiresAt: now.Add(ttl),
+	}
+	return Record{}, true, nil
+}
+
+func (s *inmemoryStore) Save(ctx context.Context, key string, response Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.record.Response = &response
+	}
+	return nil
+}
+
+func (s *inmemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.record.Response == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep drops the expired keys so that the map does not grow with every key ever used.
+func (s *inmemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/dilaragorum/movie-go/middleware"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// InstrumentRoute records count and latency of next under the route pattern it was
+// registered with (e.g. "/movies/:id"), so that IDs do not end up in label values.
+func (m *Metrics) InstrumentRoute(route string, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		start := time.Now()
+		sr := middleware.NewStatusRecorder(w)
+
+		next(sr, r, ps)
+
+		status := strconv.Itoa(sr.Status)
+		m.httpRequests.WithLabelValues(r.Method, route, status).Inc()
+		m.httpDuration.WithLabelValues(r.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+const namespace = "movie"
+
+// Metrics owns its own registry so tests can create as many instances as they need.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpPanics   prometheus.Counter
+
+	serviceDuration *prometheus.HistogramVec
+	serviceErrors   *prometheus.CounterVec
+
+	repositoryDuration *prometheus.HistogramVec
+	repositoryErrors   *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		httpPanics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "panics_total",
+			Help:      "Panics recovered while serving HTTP requests.",
+		}),
+		serviceDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "service",
+			Name:      "operation_duration_seconds",
+			Help:      "Service call latency by service and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "method"}),
+		serviceErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "service",
+			Name:      "errors_total",
+			Help:      "Service calls that returned an error, by service and method.",
+		}, []string{"service", "method"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Repository call latency by repository and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"repository", "method"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "errors_total",
+			Help:      "Repository calls that returned an error, by repository and method.",
+		}, []string{"repository", "method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.httpPanics,
+		m.serviceDuration,
+		m.serviceErrors,
+		m.repositoryDuration,
+		m.repositoryErrors,
+	)
+
+	return m
+}
+
+// RegisterDBStats exposes the connection pool statistics (open, in use, idle, waits) of db.
+func (m *Metrics) RegisterDBStats(db *sql.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// curl localhost:8080/metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) IncPanics() {
+	m.httpPanics.Inc()
+}
+
+// observeService records a call of method on service, e.g. "movie" for the
+// IMovieService, so that the methods of different services are told apart.
+func (m *Metrics) observeService(service string, method string, start time.Time, err error) {
+	m.serviceDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.serviceErrors.WithLabelValues(service, method).Inc()
+	}
+}
+
+// observeRepository is observeService for a call of method on repository.
+func (m *Metrics) observeRepository(repository string, method string, start time.Time, err error) {
+	m.repositoryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.repositoryErrors.WithLabelValues(repository, method).Inc()
+	}
+}
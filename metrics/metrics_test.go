@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics_InstrumentRoute(t *testing.T) {
+	m := New()
+	router := httprouter.New()
+	router.GET("/movies/:id", m.InstrumentRoute("/movies/:id", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if ps.ByName("id") == "404" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+
+	for _, id := range []string{"1", "2", "404"} {
+		req, _ := http.NewRequest(http.MethodGet, "/movies/"+id, http.NoBody)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/movies/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/movies/:id", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+}
+
+func TestMetrics_Decorators(t *testing.T) {
+	m := New()
+
+	mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+	mockRepository.EXPECT().GetMovies(gomock.Any(), gomock.Any()).Return([]model.Movie{}, nil).Times(1)
+	mockRepository.EXPECT().DeleteMovie(gomock.Any(), 3).Return(repository.ErrMovieNotFound).Times(1)
+
+	ms := NewMovieService(service.NewDefaultMovieService(NewMovieRepository(mockRepository, m),
+		repository.NewInMemoryTransactor(), repository.NewInMemoryOutboxRepository(logging.NewNop()),
+		events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer), logging.NewNop()), m)
+	ms.GetMovies(context.Background(), model.MovieFilter{})
+	ms.DeleteMovie(context.Background(), 3)
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.repositoryErrors.WithLabelValues("movie", "GetMovies")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.repositoryErrors.WithLabelValues("movie", "DeleteMovie")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.serviceErrors.WithLabelValues("movie", "DeleteMovie")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.repositoryDuration))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.serviceDuration))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.repositoryErrors.WithLabelValues("movie", "GetMovie").Add(2)
+	m.repositoryErrors.WithLabelValues("rating", "GetMovie").Inc()
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", http.NoBody)
+	m.Handler().ServeHTTP(rec, req)
+
+	body, _ := io.ReadAll(rec.Body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(string(body), "go_goroutines"))
+	assert.Nil(t, testutil.GatherAndCompare(m.registry, strings.NewReader(`
+# HELP movie_repository_errors_total Repository calls that returned an error, by repository and method.
+# TYPE movie_repository_errors_total counter
+movie_repository_errors_total{method="GetMovie",repository="movie"} 2
+movie_repository_errors_total{method="GetMovie",repository="rating"} 1
+`), "movie_repository_errors_total"))
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+type instrumentedMovieRepository struct {
+	next    repository.IMovieRepository
+	metrics *Metrics
+}
+
+// NewMovieRepository decorates next with per-method latency and error metrics.
+func NewMovieRepository(next repository.IMovieRepository, m *Metrics) *instrumentedMovieRepository {
+	return &instrumentedMovieRepository{next: next, metrics: m}
+}
+
+func (i *instrumentedMovieRepository) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+	start := time.Now()
+	movies, err := i.next.GetMovies(ctx, filter)
+	i.metrics.observeRepository("movie", "GetMovies", start, err)
+	return movies, err
+}
+
+func (i *instrumentedMovieRepository) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error {
+	start := time.Now()
+	err := i.next.StreamMovies(ctx, filter, fn)
+	i.metrics.observeRepository("movie", "StreamMovies", start, err)
+	return err
+}
+
+func (i *instrumentedMovieRepository) GetMovie(ctx context.Context, id int) (model.Movie, error) {
+	start := time.Now()
+	movie, err := i.next.GetMovie(ctx, id)
+	i.metrics.observeRepository("movie", "GetMovie", start, err)
+	return movie, err
+}
+
+func (i *instrumentedMovieRepository) GetMoviesByID(ctx context.Context, ids []int) ([]model.Movie, error) {
+	start := time.Now()
+	movies, err := i.next.GetMoviesByID(ctx, ids)
+	i.metrics.observeRepository("movie", "GetMoviesByID", start, err)
+	return movies, err
+}
+
+func (i *instrumentedMovieRepository) GetBestScoredMovies(ctx context.Context, limit int, excludedIDs []int) ([]model.Movie, error) {
+	start := time.Now()
+	movies, err := i.next.GetBestScoredMovies(ctx, limit, excludedIDs)
+	i.metrics.observeRepository("movie", "GetBestScoredMovies", start, err)
+	return movies, err
+}
+
+func (i *instrumentedMovieRepository) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	start := time.Now()
+	stats, err := i.next.GetMovieStats(ctx, filter, topN)
+	i.metrics.observeRepository("movie", "GetMovieStats", start, err)
+	return stats, err
+}
+
+func (i *instrumentedMovieRepository) LockMovieTitle(ctx context.Context, titleKey string, releaseYear int) error {
+	start := time.Now()
+	err := i.next.LockMovieTitle(ctx, titleKey, releaseYear)
+	i.metrics.observeRepository("movie", "LockMovieTitle", start, err)
+	return err
+}
+
+func (i *instrumentedMovieRepository) CreateMovie(ctx context.Context, movie model.Movie) (int, error) {
+	start := time.Now()
+	id, err := i.next.CreateMovie(ctx, movie)
+	i.metrics.observeRepository("movie", "CreateMovie", start, err)
+	return id, err
+}
+
+func (i *instrumentedMovieRepository) DeleteMovie(ctx context.Context, id int) error {
+	start := time.Now()
+	err := i.next.DeleteMovie(ctx, id)
+	i.metrics.observeRepository("movie", "DeleteMovie", start, err)
+	return err
+}
+
+func (i *instrumentedMovieRepository) DeleteAllMovies(ctx context.Context) ([]int, error) {
+	start := time.Now()
+	ids, err := i.next.DeleteAllMovies(ctx)
+	i.metrics.observeRepository("movie", "DeleteAllMovies", start, err)
+	return ids, err
+}
+
+func (i *instrumentedMovieRepository) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
+	start := time.Now()
+	err := i.next.UpdateMovie(ctx, id, movie)
+	i.metrics.observeRepository("movie", "UpdateMovie", start, err)
+	return err
+}
+
+func (i *instrumentedMovieRepository) MergeMovies(ctx context.Context, targetID int, sourceID int) error {
+	start := time.Now()
+	err := i.next.MergeMovies(ctx, targetID, sourceID)
+	i.metrics.observeRepository("movie", "MergeMovies", start, err)
+	return err
+}
+
+func (i *instrumentedMovieRepository) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := i.next.Ping(ctx)
+	i.metrics.observeRepository("movie", "Ping", start, err)
+	return err
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"time"
+)
+
+type instrumentedMovieService struct {
+	next    service.IMovieService
+	metrics *Metrics
+}
+
+// NewMovieService decorates next with per-method latency and error metrics.
+func NewMovieService(next service.IMovieService, m *Metrics) *instrumentedMovieService {
+	return &instrumentedMovieService{next: next, metrics: m}
+}
+
+func (i *instrumentedMovieService) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+	start := time.Now()
+	movies, err := i.next.GetMovies(ctx, filter)
+	i.metrics.observeService("movie", "GetMovies", start, err)
+	return movies, err
+}
+
+func (i *instrumentedMovieService) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error {
+	start := time.Now()
+	err := i.next.StreamMovies(ctx, filter, fn)
+	i.metrics.observeService("movie", "StreamMovies", start, err)
+	return err
+}
+
+func (i *instrumentedMovieService) GetMovie(ctx context.Context, id int) (model.Movie, error) {
+	start := time.Now()
+	movie, err := i.next.GetMovie(ctx, id)
+	i.metrics.observeService("movie", "GetMovie", start, err)
+	return movie, err
+}
+
+func (i *instrumentedMovieService) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	start := time.Now()
+	stats, err := i.next.GetMovieStats(ctx, filter, topN)
+	i.metrics.observeService("movie", "GetMovieStats", start, err)
+	return stats, err
+}
+
+func (i *instrumentedMovieService) CreateMovie(ctx context.Context, movie model.Movie) ([]model.Duplicate, error) {
+	start := time.Now()
+	duplicates, err := i.next.CreateMovie(ctx, movie)
+	i.metrics.observeService("movie", "CreateMovie", start, err)
+	return duplicates, err
+}
+
+func (i *instrumentedMovieService) DeleteMovie(ctx context.Context, id int) error {
+	start := time.Now()
+	err := i.next.DeleteMovie(ctx, id)
+	i.metrics.observeService("movie", "DeleteMovie", start, err)
+	return err
+}
+
+func (i *instrumentedMovieService) DeleteAllMovie(ctx context.Context) error {
+	start := time.Now()
+	err := i.next.DeleteAllMovie(ctx)
+	i.metrics.observeService("movie", "DeleteAllMovie", start, err)
+	return err
+}
+
+func (i *instrumentedMovieService) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
+	start := time.Now()
+	err := i.next.UpdateMovie(ctx, id, movie)
+	i.metrics.observeService("movie", "UpdateMovie", start, err)
+	return err
+}
+
+func (i *instrumentedMovieService) MergeMovies(ctx context.Context, targetID int, sourceID int) (model.Movie, error) {
+	start := time.Now()
+	movie, err := i.next.MergeMovies(ctx, targetID, sourceID)
+	i.metrics.observeService("movie", "MergeMovies", start, err)
+	return movie, err
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+type instrumentedOutboxRepository struct {
+	next    repository.IOutboxRepository
+	metrics *Metrics
+}
+
+// NewOutboxRepository decorates next with per-method latency and error metrics.
+func NewOutboxRepository(next repository.IOutboxRepository, m *Metrics) *instrumentedOutboxRepository {
+	return &instrumentedOutboxRepository{next: next, metrics: m}
+}
+
+func (i *instrumentedOutboxRepository) AppendEvents(ctx context.Context, events []model.Event) error {
+	start := time.Now()
+	err := i.next.AppendEvents(ctx, events)
+	i.metrics.observeRepository("outbox", "AppendEvents", start, err)
+	return err
+}
+
+func (i *instrumentedOutboxRepository) ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	start := time.Now()
+	events, err := i.next.ClaimPendingEvents(ctx, limit, lease)
+	i.metrics.observeRepository("outbox", "ClaimPendingEvents", start, err)
+	return events, err
+}
+
+func (i *instrumentedOutboxRepository) ReleaseEvents(ctx context.Context, ids []int64) error {
+	start := time.Now()
+	err := i.next.ReleaseEvents(ctx, ids)
+	i.metrics.observeRepository("outbox", "ReleaseEvents", start, err)
+	return err
+}
+
+func (i *instrumentedOutboxRepository) DeleteEvents(ctx context.Context, ids []int64) error {
+	start := time.Now()
+	err := i.next.DeleteEvents(ctx, ids)
+	i.metrics.observeRepository("outbox", "DeleteEvents", start, err)
+	return err
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+type instrumentedPersonRepository struct {
+	next    repository.IPersonRepository
+	metrics *Metrics
+}
+
+// NewPersonRepository decorates next with per-method latency and error metrics.
+func NewPersonRepository(next repository.IPersonRepository, m *Metrics) *instrumentedPersonRepository {
+	return &instrumentedPersonRepository{next: next, metrics: m}
+}
+
+func (i *instrumentedPersonRepository) GetPeople(ctx context.Context) ([]model.Person, error) {
+	start := time.Now()
+	people, err := i.next.GetPeople(ctx)
+	i.metrics.observeRepository("person", "GetPeople", start, err)
+	return people, err
+}
+
+func (i *instrumentedPersonRepository) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	start := time.Now()
+	person, err := i.next.GetPerson(ctx, id)
+	i.metrics.observeRepository("person", "GetPerson", start, err)
+	return person, err
+}
+
+func (i *instrumentedPersonRepository) CreatePerson(ctx context.Context, person model.Person) error {
+	start := time.Now()
+	err := i.next.CreatePerson(ctx, person)
+	i.metrics.observeRepository("person", "CreatePerson", start, err)
+	return err
+}
+
+func (i *instrumentedPersonRepository) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	start := time.Now()
+	err := i.next.UpdatePerson(ctx, id, person)
+	i.metrics.observeRepository("person", "UpdatePerson", start, err)
+	return err
+}
+
+func (i *instrumentedPersonRepository) DeletePerson(ctx context.Context, id int) error {
+	start := time.Now()
+	err := i.next.DeletePerson(ctx, id)
+	i.metrics.observeRepository("person", "DeletePerson", start, err)
+	return err
+}
+
+func (i *instrumentedPersonRepository) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	start := time.Now()
+	credits, err := i.next.GetMovieCredits(ctx, movieID)
+	i.metrics.observeRepository("person", "GetMovieCredits", start, err)
+	return credits, err
+}
+
+func (i *instrumentedPersonRepository) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	start := time.Now()
+	credits, err := i.next.GetCreditsByMovies(ctx, movieIDs)
+	i.metrics.observeRepository("person", "GetCreditsByMovies", start, err)
+	return credits, err
+}
+
+func (i *instrumentedPersonRepository) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	start := time.Now()
+	movies, err := i.next.GetPersonMovies(ctx, personID, role)
+	i.metrics.observeRepository("person", "GetPersonMovies", start, err)
+	return movies, err
+}
+
+func (i *instrumentedPersonRepository) AddCredit(ctx context.Context, credit model.Credit) error {
+	start := time.Now()
+	err := i.next.AddCredit(ctx, credit)
+	i.metrics.observeRepository("person", "AddCredit", start, err)
+	return err
+}
+
+func (i *instrumentedPersonRepository) DeleteCredit(ctx context.Context, movieID int, creditID int) error {
+	start := time.Now()
+	err := i.next.DeleteCredit(ctx, movieID, creditID)
+	i.metrics.observeRepository("person", "DeleteCredit", start, err)
+	return err
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"time"
+)
+
+type instrumentedPersonService struct {
+	next    service.IPersonService
+	metrics *Metrics
+}
+
+// NewPersonService decorates next with per-method latency and error metrics.
+func NewPersonService(next service.IPersonService, m *Metrics) *instrumentedPersonService {
+	return &instrumentedPersonService{next: next, metrics: m}
+}
+
+func (i *instrumentedPersonService) GetPeople(ctx context.Context) ([]model.Person, error) {
+	start := time.Now()
+	people, err := i.next.GetPeople(ctx)
+	i.metrics.observeService("person", "GetPeople", start, err)
+	return people, err
+}
+
+func (i *instrumentedPersonService) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	start := time.Now()
+	person, err := i.next.GetPerson(ctx, id)
+	i.metrics.observeService("person", "GetPerson", start, err)
+	return person, err
+}
+
+func (i *instrumentedPersonService) CreatePerson(ctx context.Context, person model.Person) error {
+	start := time.Now()
+	err := i.next.CreatePerson(ctx, person)
+	i.metrics.observeService("person", "CreatePerson", start, err)
+	return err
+}
+
+func (i *instrumentedPersonService) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	start := time.Now()
+	err := i.next.UpdatePerson(ctx, id, person)
+	i.metrics.observeService("person", "UpdatePerson", start, err)
+	return err
+}
+
+func (i *instrumentedPersonService) DeletePerson(ctx context.Context, id int) error {
+	start := time.Now()
+	err := i.next.DeletePerson(ctx, id)
+	i.metrics.observeService("person", "DeletePerson", start, err)
+	return err
+}
+
+func (i *instrumentedPersonService) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	start := time.Now()
+	credits, err := i.next.GetMovieCredits(ctx, movieID)
+	i.metrics.observeService("person", "GetMovieCredits", start, err)
+	return credits, err
+}
+
+func (i *instrumentedPersonService) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	start := time.Now()
+	credits, err := i.next.GetCreditsByMovies(ctx, movieIDs)
+	i.metrics.observeService("person", "GetCreditsByMovies", start, err)
+	return credits, err
+}
+
+func (i *instrumentedPersonService) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	start := time.Now()
+	movies, err := i.next.GetPersonMovies(ctx, personID, role)
+	i.metrics.observeService("person", "GetPersonMovies", start, err)
+	return movies, err
+}
+
+func (i *instrumentedPersonService) AddCredit(ctx context.Context, credit model.Credit) error {
+	start := time.Now()
+	err := i.next.AddCredit(ctx, credit)
+	i.metrics.observeService("person", "AddCredit", start, err)
+	return err
+}
+
+func (i *instrumentedPersonService) DeleteCredit(ctx context.Context, movieID int, creditID int) error {
+	start := time.Now()
+	err := i.next.DeleteCredit(ctx, movieID, creditID)
+	i.metrics.observeService("person", "DeleteCredit", start, err)
+	return err
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+type instrumentedRatingRepository struct {
+	next    repository.IRatingRepository
+	metrics *Metrics
+}
+
+// NewRatingRepository decorates next with per-method latency and error metrics.
+func NewRatingRepository(next repository.IRatingRepository, m *Metrics) *instrumentedRatingRepository {
+	return &instrumentedRatingRepository{next: next, metrics: m}
+}
+
+func (i *instrumentedRatingRepository) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	start := time.Now()
+	ratings, err := i.next.GetRatings(ctx, movieID)
+	i.metrics.observeRepository("rating", "GetRatings", start, err)
+	return ratings, err
+}
+
+func (i *instrumentedRatingRepository) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	start := time.Now()
+	summary, err := i.next.RateMovie(ctx, rating)
+	i.metrics.observeRepository("rating", "RateMovie", start, err)
+	return summary, err
+}
+
+func (i *instrumentedRatingRepository) DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error) {
+	start := time.Now()
+	summary, err := i.next.DeleteRating(ctx, movieID, userID)
+	i.metrics.observeRepository("rating", "DeleteRating", start, err)
+	return summary, err
+}
+
+func (i *instrumentedRatingRepository) GetUserRatings(ctx context.Context, userID int) ([]model.Rating, error) {
+	start := time.Now()
+	ratings, err := i.next.GetUserRatings(ctx, userID)
+	i.metrics.observeRepository("rating", "GetUserRatings", start, err)
+	return ratings, err
+}
+
+func (i *instrumentedRatingRepository) StreamRatings(ctx context.Context, fn func(rating model.Rating) error) error {
+	start := time.Now()
+	err := i.next.StreamRatings(ctx, fn)
+	i.metrics.observeRepository("rating", "StreamRatings", start, err)
+	return err
+}
+
+func (i *instrumentedRatingRepository) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	start := time.Now()
+	reviews, err := i.next.GetReviews(ctx, movieID)
+	i.metrics.observeRepository("rating", "GetReviews", start, err)
+	return reviews, err
+}
+
+func (i *instrumentedRatingRepository) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	start := time.Now()
+	reviews, err := i.next.GetReviewsByMovies(ctx, movieIDs)
+	i.metrics.observeRepository("rating", "GetReviewsByMovies", start, err)
+	return reviews, err
+}
+
+func (i *instrumentedRatingRepository) AddReview(ctx context.Context, review model.Review) error {
+	start := time.Now()
+	err := i.next.AddReview(ctx, review)
+	i.metrics.observeRepository("rating", "AddReview", start, err)
+	return err
+}
+
+func (i *instrumentedRatingRepository) DeleteReview(ctx context.Context, movieID int, reviewID int) error {
+	start := time.Now()
+	err := i.next.DeleteReview(ctx, movieID, reviewID)
+	i.metrics.observeRepository("rating", "DeleteReview", start, err)
+	return err
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"time"
+)
+
+type instrumentedRatingService struct {
+	next    service.IRatingService
+	metrics *Metrics
+}
+
+// NewRatingService decorates next with per-method latency and error metrics.
+func NewRatingService(next service.IRatingService, m *Metrics) *instrumentedRatingService {
+	return &instrumentedRatingService{next: next, metrics: m}
+}
+
+func (i *instrumentedRatingService) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	start := time.Now()
+	ratings, err := i.next.GetRatings(ctx, movieID)
+	i.metrics.observeService("rating", "GetRatings", start, err)
+	return ratings, err
+}
+
+func (i *instrumentedRatingService) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	start := time.Now()
+	summary, err := i.next.RateMovie(ctx, rating)
+	i.metrics.observeService("rating", "RateMovie", start, err)
+	return summary, err
+}
+
+func (i *instrumentedRatingService) DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error) {
+	start := time.Now()
+	summary, err := i.next.DeleteRating(ctx, movieID, userID)
+	i.metrics.observeService("rating", "DeleteRating", start, err)
+	return summary, err
+}
+
+func (i *instrumentedRatingService) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	start := time.Now()
+	reviews, err := i.next.GetReviews(ctx, movieID)
+	i.metrics.observeService("rating", "GetReviews", start, err)
+	return reviews, err
+}
+
+func (i *instrumentedRatingService) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	start := time.Now()
+	reviews, err := i.next.GetReviewsByMovies(ctx, movieIDs)
+	i.metrics.observeService("rating", "GetReviewsByMovies", start, err)
+	return reviews, err
+}
+
+func (i *instrumentedRatingService) AddReview(ctx context.Context, review model.Review) error {
+	start := time.Now()
+	err := i.next.AddReview(ctx, review)
+	i.metrics.observeService("rating", "AddReview", start, err)
+	return err
+}
+
+func (i *instrumentedRatingService) DeleteReview(ctx context.Context, movieID int, reviewID int) error {
+	start := time.Now()
+	err := i.next.DeleteReview(ctx, movieID, reviewID)
+	i.metrics.observeService("rating", "DeleteReview", start, err)
+	return err
+}
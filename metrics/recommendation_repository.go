@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+type instrumentedRecommendationRepository struct {
+	next    repository.IRecommendationRepository
+	metrics *Metrics
+}
+
+// NewRecommendationRepository decorates next with per-method latency and error metrics.
+func NewRecommendationRepository(next repository.IRecommendationRepository, m *Metrics) *instrumentedRecommendationRepository {
+	return &instrumentedRecommendationRepository{next: next, metrics: m}
+}
+
+func (i *instrumentedRecommendationRepository) GetNeighbours(ctx context.Context, movieID int, limit int) ([]model.Neighbour, bool, error) {
+	start := time.Now()
+	neighbours, computed, err := i.next.GetNeighbours(ctx, movieID, limit)
+	i.metrics.observeRepository("recommendation", "GetNeighbours", start, err)
+	return neighbours, computed, err
+}
+
+func (i *instrumentedRecommendationRepository) ReplaceNeighbours(ctx context.Context, movieIDs []int, neighbours []model.Neighbour) error {
+	start := time.Now()
+	err := i.next.ReplaceNeighbours(ctx, movieIDs, neighbours)
+	i.metrics.observeRepository("recommendation", "ReplaceNeighbours", start, err)
+	return err
+}
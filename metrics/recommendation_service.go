@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"time"
+)
+
+type instrumentedRecommendationService struct {
+	next    service.IRecommendationService
+	metrics *Metrics
+}
+
+// NewRecommendationService decorates next with per-method latency and error metrics.
+func NewRecommendationService(next service.IRecommendationService, m *Metrics) *instrumentedRecommendationService {
+	return &instrumentedRecommendationService{next: next, metrics: m}
+}
+
+func (i *instrumentedRecommendationService) GetSimilarMovies(ctx context.Context, movieID int, limit int) ([]model.SimilarMovie, error) {
+	start := time.Now()
+	similar, err := i.next.GetSimilarMovies(ctx, movieID, limit)
+	i.metrics.observeService("recommendation", "GetSimilarMovies", start, err)
+	return similar, err
+}
+
+func (i *instrumentedRecommendationService) GetRecommendations(ctx context.Context, userID int, limit int) ([]model.Recommendation, error) {
+	start := time.Now()
+	recommendations, err := i.next.GetRecommendations(ctx, userID, limit)
+	i.metrics.observeService("recommendation", "GetRecommendations", start, err)
+	return recommendations, err
+}
+
+func (i *instrumentedRecommendationService) RefreshNeighbours(ctx context.Context) error {
+	start := time.Now()
+	err := i.next.RefreshNeighbours(ctx)
+	i.metrics.observeService("recommendation", "RefreshNeighbours", start, err)
+	return err
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+type instrumentedWatchlistRepository struct {
+	next    repository.IWatchlistRepository
+	metrics *Metrics
+}
+
+// NewWatchlistRepository decorates next with per-method latency and error metrics.
+func NewWatchlistRepository(next repository.IWatchlistRepository, m *Metrics) *instrumentedWatchlistRepository {
+	return &instrumentedWatchlistRepository{next: next, metrics: m}
+}
+
+func (i *instrumentedWatchlistRepository) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	start := time.Now()
+	items, err := i.next.GetWatchlist(ctx, userID)
+	i.metrics.observeRepository("watchlist", "GetWatchlist", start, err)
+	return items, err
+}
+
+func (i *instrumentedWatchlistRepository) AddToWatchlist(ctx context.Context, userID int, movieID int) error {
+	start := time.Now()
+	err := i.next.AddToWatchlist(ctx, userID, movieID)
+	i.metrics.observeRepository("watchlist", "AddToWatchlist", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistRepository) RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error {
+	start := time.Now()
+	err := i.next.RemoveFromWatchlist(ctx, userID, movieID)
+	i.metrics.observeRepository("watchlist", "RemoveFromWatchlist", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistRepository) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	start := time.Now()
+	err := i.next.ReorderWatchlist(ctx, userID, movieIDs)
+	i.metrics.observeRepository("watchlist", "ReorderWatchlist", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistRepository) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	start := time.Now()
+	entries, err := i.next.GetWatched(ctx, userID)
+	i.metrics.observeRepository("watchlist", "GetWatched", start, err)
+	return entries, err
+}
+
+func (i *instrumentedWatchlistRepository) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	start := time.Now()
+	err := i.next.AddWatched(ctx, entry)
+	i.metrics.observeRepository("watchlist", "AddWatched", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistRepository) DeleteWatched(ctx context.Context, userID int, entryID int) error {
+	start := time.Now()
+	err := i.next.DeleteWatched(ctx, userID, entryID)
+	i.metrics.observeRepository("watchlist", "DeleteWatched", start, err)
+	return err
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"time"
+)
+
+type instrumentedWatchlistService struct {
+	next    service.IWatchlistService
+	metrics *Metrics
+}
+
+// NewWatchlistService decorates next with per-method latency and error metrics.
+func NewWatchlistService(next service.IWatchlistService, m *Metrics) *instrumentedWatchlistService {
+	return &instrumentedWatchlistService{next: next, metrics: m}
+}
+
+func (i *instrumentedWatchlistService) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	start := time.Now()
+	items, err := i.next.GetWatchlist(ctx, userID)
+	i.metrics.observeService("watchlist", "GetWatchlist", start, err)
+	return items, err
+}
+
+func (i *instrumentedWatchlistService) AddToWatchlist(ctx context.Context, userID int, movieID int) error {
+	start := time.Now()
+	err := i.next.AddToWatchlist(ctx, userID, movieID)
+	i.metrics.observeService("watchlist", "AddToWatchlist", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistService) RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error {
+	start := time.Now()
+	err := i.next.RemoveFromWatchlist(ctx, userID, movieID)
+	i.metrics.observeService("watchlist", "RemoveFromWatchlist", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistService) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	start := time.Now()
+	err := i.next.ReorderWatchlist(ctx, userID, movieIDs)
+	i.metrics.observeService("watchlist", "ReorderWatchlist", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistService) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	start := time.Now()
+	entries, err := i.next.GetWatched(ctx, userID)
+	i.metrics.observeService("watchlist", "GetWatched", start, err)
+	return entries, err
+}
+
+func (i *instrumentedWatchlistService) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	start := time.Now()
+	err := i.next.AddWatched(ctx, entry)
+	i.metrics.observeService("watchlist", "AddWatched", start, err)
+	return err
+}
+
+func (i *instrumentedWatchlistService) DeleteWatched(ctx context.Context, userID int, entryID int) error {
+	start := time.Now()
+	err := i.next.DeleteWatched(ctx, userID, entryID)
+	i.metrics.observeService("watchlist", "DeleteWatched", start, err)
+	return err
+}
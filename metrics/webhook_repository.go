@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"time"
+)
+
+type instrumentedWebhookRepository struct {
+	next    repository.IWebhookRepository
+	metrics *Metrics
+}
+
+// NewWebhookRepository decorates next with per-method latency and error metrics.
+func NewWebhookRepository(next repository.IWebhookRepository, m *Metrics) *instrumentedWebhookRepository {
+	return &instrumentedWebhookRepository{next: next, metrics: m}
+}
+
+func (i *instrumentedWebhookRepository) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	start := time.Now()
+	webhooks, err := i.next.GetWebhooks(ctx)
+	i.metrics.observeRepository("webhook", "GetWebhooks", start, err)
+	return webhooks, err
+}
+
+func (i *instrumentedWebhookRepository) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	start := time.Now()
+	webhook, err := i.next.GetWebhook(ctx, id)
+	i.metrics.observeRepository("webhook", "GetWebhook", start, err)
+	return webhook, err
+}
+
+func (i *instrumentedWebhookRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	start := time.Now()
+	created, err := i.next.CreateWebhook(ctx, webhook)
+	i.metrics.observeRepository("webhook", "CreateWebhook", start, err)
+	return created, err
+}
+
+func (i *instrumentedWebhookRepository) UpdateWebhook(ctx context.Context, id int, webhook model.Webhook) error {
+	start := time.Now()
+	err := i.next.UpdateWebhook(ctx, id, webhook)
+	i.metrics.observeRepository("webhook", "UpdateWebhook", start, err)
+	return err
+}
+
+func (i *instrumentedWebhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	start := time.Now()
+	err := i.next.DeleteWebhook(ctx, id)
+	i.metrics.observeRepository("webhook", "DeleteWebhook", start, err)
+	return err
+}
+
+func (i *instrumentedWebhookRepository) EnqueueDeliveries(ctx context.Context, event model.Event, payload []byte, now time.Time) (int, error) {
+	start := time.Now()
+	enqueued, err := i.next.EnqueueDeliveries(ctx, event, payload, now)
+	i.metrics.observeRepository("webhook", "EnqueueDeliveries", start, err)
+	return enqueued, err
+}
+
+func (i *instrumentedWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.DueDelivery, error) {
+	start := time.Now()
+	claimed, err := i.next.ClaimDueDeliveries(ctx, now, lease, limit)
+	i.metrics.observeRepository("webhook", "ClaimDueDeliveries", start, err)
+	return claimed, err
+}
+
+func (i *instrumentedWebhookRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	start := time.Now()
+	err := i.next.UpdateDelivery(ctx, delivery)
+	i.metrics.observeRepository("webhook", "UpdateDelivery", start, err)
+	return err
+}
+
+func (i *instrumentedWebhookRepository) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	start := time.Now()
+	deliveries, err := i.next.GetDeliveries(ctx, webhookID, status, limit)
+	i.metrics.observeRepository("webhook", "GetDeliveries", start, err)
+	return deliveries, err
+}
+
+func (i *instrumentedWebhookRepository) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64, now time.Time) error {
+	start := time.Now()
+	err := i.next.RedeliverDelivery(ctx, webhookID, deliveryID, now)
+	i.metrics.observeRepository("webhook", "RedeliverDelivery", start, err)
+	return err
+}
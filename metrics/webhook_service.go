@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/service"
+	"time"
+)
+
+type instrumentedWebhookService struct {
+	next    service.IWebhookService
+	metrics *Metrics
+}
+
+// NewWebhookService decorates next with per-method latency and error metrics.
+func NewWebhookService(next service.IWebhookService, m *Metrics) *instrumentedWebhookService {
+	return &instrumentedWebhookService{next: next, metrics: m}
+}
+
+func (i *instrumentedWebhookService) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	start := time.Now()
+	webhooks, err := i.next.GetWebhooks(ctx)
+	i.metrics.observeService("webhook", "GetWebhooks", start, err)
+	return webhooks, err
+}
+
+func (i *instrumentedWebhookService) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	start := time.Now()
+	webhook, err := i.next.GetWebhook(ctx, id)
+	i.metrics.observeService("webhook", "GetWebhook", start, err)
+	return webhook, err
+}
+
+func (i *instrumentedWebhookService) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	start := time.Now()
+	created, err := i.next.CreateWebhook(ctx, webhook)
+	i.metrics.observeService("webhook", "CreateWebhook", start, err)
+	return created, err
+}
+
+func (i *instrumentedWebhookService) UpdateWebhook(ctx context.Context, id int, patch model.WebhookPatch) error {
+	start := time.Now()
+	err := i.next.UpdateWebhook(ctx, id, patch)
+	i.metrics.observeService("webhook", "UpdateWebhook", start, err)
+	return err
+}
+
+func (i *instrumentedWebhookService) DeleteWebhook(ctx context.Context, id int) error {
+	start := time.Now()
+	err := i.next.DeleteWebhook(ctx, id)
+	i.metrics.observeService("webhook", "DeleteWebhook", start, err)
+	return err
+}
+
+func (i *instrumentedWebhookService) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	start := time.Now()
+	deliveries, err := i.next.GetDeliveries(ctx, webhookID, status, limit)
+	i.metrics.observeService("webhook", "GetDeliveries", start, err)
+	return deliveries, err
+}
+
+func (i *instrumentedWebhookService) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64) error {
+	start := time.Now()
+	err := i.next.RedeliverDelivery(ctx, webhookID, deliveryID)
+	i.metrics.observeService("webhook", "RedeliverDelivery", start, err)
+	return err
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/requestid"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	newServer := func(logs *bytes.Buffer) http.Handler {
+		logger := logging.New(logs, slog.LevelInfo)
+		return requestid.Middleware(AccessLog(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger.WarnContext(r.Context(), "movie not found")
+			problem.Write(w, r, http.StatusNotFound, "the movie cannot be found")
+		})))
+	}
+
+	t.Run("Propagates X-Request-ID to logs and error response", func(t *testing.T) {
+		var logs bytes.Buffer
+		req, _ := http.NewRequest(http.MethodGet, "/movies/9", http.NoBody)
+		req.Header.Set(requestid.Header, "abc-123")
+		rec := httptest.NewRecorder()
+
+		newServer(&logs).ServeHTTP(rec, req)
+
+		assert.Equal(t, "abc-123", rec.Header().Get(requestid.Header))
+
+		var body problem.Problem
+		json.NewDecoder(rec.Body).Decode(&body)
+		assert.Equal(t, "abc-123", body.RequestID)
+		assert.Equal(t, http.StatusNotFound, body.Status)
+
+		decoder := json.NewDecoder(&logs)
+		for _, msg := range []string{"movie not found", "http request"} {
+			var line map[string]interface{}
+			assert.Nil(t, decoder.Decode(&line))
+			assert.Equal(t, msg, line["msg"])
+			assert.Equal(t, "abc-123", line["request_id"])
+		}
+	})
+	t.Run("Generates an ID for missing or malformed headers", func(t *testing.T) {
+		var logs bytes.Buffer
+		req, _ := http.NewRequest(http.MethodGet, "/movies/9", http.NoBody)
+		req.Header.Set(requestid.Header, "contains spaces\n")
+		rec := httptest.NewRecorder()
+
+		newServer(&logs).ServeHTTP(rec, req)
+
+		id := rec.Header().Get(requestid.Header)
+		assert.Len(t, id, 32)
+
+		var line map[string]interface{}
+		json.NewDecoder(&logs).Decode(&line)
+		assert.Equal(t, id, line["request_id"])
+	})
+	t.Run("Access log line records the response", func(t *testing.T) {
+		var logs bytes.Buffer
+		req, _ := http.NewRequest(http.MethodGet, "/movies/9", http.NoBody)
+		rec := httptest.NewRecorder()
+
+		newServer(&logs).ServeHTTP(rec, req)
+
+		decoder := json.NewDecoder(&logs)
+		var line map[string]interface{}
+		decoder.Decode(&line)
+		decoder.Decode(&line)
+		assert.Equal(t, "INFO", line["level"])
+		assert.Equal(t, "GET", line["method"])
+		assert.Equal(t, "/movies/9", line["path"])
+		assert.Equal(t, float64(http.StatusNotFound), line["status"])
+		assert.Equal(t, float64(rec.Body.Len()), line["bytes"])
+	})
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/dilaragorum/movie-go/negotiation"
+	"github.com/julienschmidt/httprouter"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type resettableWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// encodings lists the supported content codings in order of preference.
+var encodings = []string{"br", "zstd", "gzip"}
+
+var encoderPools = map[string]*sync.Pool{
+	"br": {New: func() interface{} { return brotli.NewWriterLevel(nil, 5) }},
+	"zstd": {New: func() interface{} {
+		w, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return w
+	}},
+	"gzip": {New: func() interface{} { return gzip.NewWriter(nil) }},
+}
+
+// Compress encodes response bodies with the best coding the client accepts among br,
+// zstd and gzip. Responses without a body and already encoded responses are left alone.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding, ok := negotiation.Encoding(r.Header.Get("Accept-Encoding"), encodings)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, pool: encoderPools[encoding]}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// CompressHandle is Compress for a single httprouter route.
+func CompressHandle(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next(w, r, ps)
+		})).ServeHTTP(w, r)
+	}
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	pool        *sync.Pool
+	writer      resettableWriter
+	wroteHeader bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+
+	h := cw.Header()
+	hasBody := status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+	if hasBody && h.Get("Content-Encoding") == "" {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		// The encoded bytes differ from the identity ones, so a strong validator
+		// computed on the identity body only holds semantically.
+		if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		cw.writer = cw.pool.Get().(resettableWriter)
+		cw.writer.Reset(cw.ResponseWriter)
+	}
+
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if cw.writer == nil {
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.writer.Write(b)
+}
+
+func (cw *compressWriter) Flush() {
+	if flusher, ok := cw.writer.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressWriter) Close() error {
+	if cw.writer == nil {
+		return nil
+	}
+
+	err := cw.writer.Close()
+	cw.writer.Reset(nil)
+	cw.pool.Put(cw.writer)
+	cw.writer = nil
+	return err
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	body := strings.Repeat(`{"id":1,"title":"The Godfather"},`, 100)
+	h := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		if r.URL.Path == "/not-modified" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+
+	serve := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, http.NoBody)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	decoders := map[string]func(r io.Reader) io.Reader{
+		"gzip": func(r io.Reader) io.Reader { zr, _ := gzip.NewReader(r); return zr },
+		"br":   func(r io.Reader) io.Reader { return brotli.NewReader(r) },
+		"zstd": func(r io.Reader) io.Reader { zr, _ := zstd.NewReader(r); return zr },
+	}
+
+	for encoding, decode := range decoders {
+		t.Run(encoding, func(t *testing.T) {
+			rec := serve("/movies", encoding)
+
+			assert.Equal(t, encoding, rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			assert.Equal(t, `W/"abc"`, rec.Header().Get("ETag"))
+			assert.Less(t, rec.Body.Len(), len(body))
+
+			decoded, err := io.ReadAll(decode(bytes.NewReader(rec.Body.Bytes())))
+			assert.Nil(t, err)
+			assert.Equal(t, body, string(decoded))
+		})
+	}
+
+	t.Run("identity", func(t *testing.T) {
+		rec := serve("/movies", "")
+
+		assert.Equal(t, "", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, `"abc"`, rec.Header().Get("ETag"))
+		assert.Equal(t, body, rec.Body.String())
+	})
+	t.Run("no body - not encoded", func(t *testing.T) {
+		rec := serve("/not-modified", "gzip")
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, "", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, 0, rec.Body.Len())
+	})
+}
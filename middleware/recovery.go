@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/dilaragorum/movie-go/problem"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+type Recoverer struct {
+	logger  *slog.Logger
+	onPanic func()
+}
+
+// NewRecoverer returns a Recoverer that logs panics to logger and calls onPanic for
+// each of them, e.g. to increment a metric.
+func NewRecoverer(logger *slog.Logger, onPanic func()) *Recoverer {
+	return &Recoverer{logger: logger, onPanic: onPanic}
+}
+
+// PanicHandler has the signature of httprouter.Router.PanicHandler.
+func (rc *Recoverer) PanicHandler(w http.ResponseWriter, r *http.Request, v interface{}) {
+	rc.logger.ErrorContext(r.Context(), "panic recovered",
+		"panic", fmt.Sprint(v),
+		"method", r.Method,
+		"path", r.URL.Path,
+		"stack", string(debug.Stack()),
+	)
+	rc.onPanic()
+
+	problem.Write(w, r, http.StatusInternalServerError, "internal server error")
+}
+
+// Middleware recovers panics raised outside of the router, e.g. by other middlewares.
+func (rc *Recoverer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				// net/http uses ErrAbortHandler to abort a response on purpose.
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				rc.PanicHandler(w, r, v)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
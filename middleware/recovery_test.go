@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/handler"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/problem"
+	"github.com/dilaragorum/movie-go/requestid"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverer(t *testing.T) {
+	t.Run("Router PanicHandler - panicking service", func(t *testing.T) {
+		var logs bytes.Buffer
+		panics := 0
+		logger := logging.New(&logs, slog.LevelInfo)
+		recoverer := NewRecoverer(logger, func() { panics++ })
+
+		mockService := service.NewMockIMovieService(gomock.NewController(t))
+		mockService.
+			EXPECT().
+			GetMovie(gomock.Any(), 1).
+			DoAndReturn(func(ctx context.Context, id int) (model.Movie, error) {
+				panic("implement me")
+			}).
+			Times(1)
+
+		router := httprouter.New()
+		router.PanicHandler = recoverer.PanicHandler
+		router.GET("/movies/:id", handler.NewMovieHandler(mockService, logger).GetMovie)
+
+		req, _ := http.NewRequest(http.MethodGet, "/movies/1", http.NoBody)
+		req.Header.Set(requestid.Header, "req-1")
+		rec := httptest.NewRecorder()
+		requestid.Middleware(router).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+		assert.Equal(t, 1, panics)
+
+		var body problem.Problem
+		json.NewDecoder(rec.Body).Decode(&body)
+		assert.Equal(t, "req-1", body.RequestID)
+
+		var line map[string]interface{}
+		json.NewDecoder(&logs).Decode(&line)
+		assert.Equal(t, "panic recovered", line["msg"])
+		assert.Equal(t, "implement me", line["panic"])
+		assert.Equal(t, "req-1", line["request_id"])
+		assert.True(t, strings.Contains(line["stack"].(string), "runtime/debug.Stack"))
+	})
+	t.Run("Middleware - panic outside the router", func(t *testing.T) {
+		panics := 0
+		recoverer := NewRecoverer(logging.NewNop(), func() { panics++ })
+		h := recoverer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("oops")
+		}))
+
+		req, _ := http.NewRequest(http.MethodGet, "/movies", http.NoBody)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, 1, panics)
+	})
+	t.Run("Middleware - ErrAbortHandler is not swallowed", func(t *testing.T) {
+		recoverer := NewRecoverer(logging.NewNop(), func() {})
+		h := recoverer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		req, _ := http.NewRequest(http.MethodGet, "/movies", http.NoBody)
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		})
+	})
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type EventType string
+
+const (
+	EventMovieCreated EventType = "movie.created"
+	EventMovieUpdated EventType = "movie.updated"
+	EventMovieDeleted EventType = "movie.deleted"
+)
+
+// Event is a change of the catalog. It is written to the outbox along with the change
+// and published afterwards, at least once: consumers may see an event again and can
+// recognize it by its ID.
+type Event struct {
+	ID      int64     `json:"id"`
+	Type    EventType `json:"type"`
+	MovieID int       `json:"movie_id"`
+	// Movie is the movie after the change, nil for deletions.
+	Movie      *Movie    `json:"movie,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func NewMovieEvent(eventType EventType, movie Movie) Event {
+	return Event{Type: eventType, MovieID: movie.ID, Movie: &movie, OccurredAt: time.Now().UTC()}
+}
+
+func NewMovieDeletedEvent(movieID int) Event {
+	return Event{Type: EventMovieDeleted, MovieID: movieID, OccurredAt: time.Now().UTC()}
+}
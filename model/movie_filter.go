@@ -0,0 +1,67 @@
+package model
+
+import "strings"
+
+// MovieFilter narrows a movie list down. Zero fields do not filter, so the zero
+// MovieFilter matches every movie.
+type MovieFilter struct {
+	Genre      string
+	Language   string
+	Country    string
+	AgeRating  string
+	YearFrom   int
+	YearTo     int
+	MinRuntime int
+	MaxRuntime int
+}
+
+// Normalized returns f in the form the movies are stored in: the genre trimmed and in
+// lower case like the language, the country and age rating in upper case.
+func (f MovieFilter) Normalized() MovieFilter {
+	f.Genre = strings.ToLower(strings.TrimSpace(f.Genre))
+	f.Language = strings.ToLower(f.Language)
+	f.Country = strings.ToUpper(f.Country)
+	f.AgeRating = strings.ToUpper(f.AgeRating)
+	return f
+}
+
+func (f MovieFilter) IsZero() bool {
+	return f == MovieFilter{}
+}
+
+func (f MovieFilter) Match(movie Movie) bool {
+	if f.Genre != "" && !hasGenre(movie, f.Genre) {
+		return false
+	}
+	if f.Language != "" && movie.OriginalLanguage != f.Language {
+		return false
+	}
+	if f.Country != "" && movie.Country != f.Country {
+		return false
+	}
+	if f.AgeRating != "" && movie.AgeRating != f.AgeRating {
+		return false
+	}
+	if f.YearFrom != 0 && movie.ReleaseYear < f.YearFrom {
+		return false
+	}
+	if f.YearTo != 0 && movie.ReleaseYear > f.YearTo {
+		return false
+	}
+	if f.MinRuntime != 0 && movie.RuntimeMinutes < f.MinRuntime {
+		return false
+	}
+	if f.MaxRuntime != 0 && movie.RuntimeMinutes > f.MaxRuntime {
+		return false
+	}
+	return true
+}
+
+func hasGenre(movie Movie, genre string) bool {
+	for _, g := range movie.Genres {
+		if g == genre {
+			return true
+		}
+	}
+	return false
+}
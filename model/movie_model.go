@@ -1,8 +1,102 @@
 package model
 
+import "time"
+
+// Movie.Score is the weighted score of its RatingSummary once the movie has ratings,
+// otherwise it is the editorial score the movie was created or last updated with.
+// The repositories keep the editorial score apart, so that it comes back once the
+// last rating is deleted.
 type Movie struct {
-	ID          int     `json:"id"`
-	Title       string  `json:"title"`
-	ReleaseYear int     `json:"release_year"`
-	Score       float64 `json:"score"`
+	ID               int       `json:"id" xml:"id"`
+	Title            string    `json:"title" xml:"title"`
+	ReleaseYear      int       `json:"release_year" xml:"release_year"`
+	Score            float64   `json:"score" xml:"score"`
+	RatingCount      int       `json:"rating_count,omitempty" xml:"rating_count,omitempty"`
+	RatingMean       float64   `json:"rating_mean,omitempty" xml:"rating_mean,omitempty"`
+	Genres           []string  `json:"genres,omitempty" xml:"genres>genre,omitempty"`
+	RuntimeMinutes   int       `json:"runtime_minutes,omitempty" xml:"runtime_minutes,omitempty"`
+	Synopsis         string    `json:"synopsis,omitempty" xml:"synopsis,omitempty"`
+	OriginalLanguage string    `json:"original_language,omitempty" xml:"original_language,omitempty"`
+	Country          string    `json:"country,omitempty" xml:"country,omitempty"`
+	AgeRating        string    `json:"age_rating,omitempty" xml:"age_rating,omitempty"`
+	PosterURL        string    `json:"poster_url,omitempty" xml:"poster_url,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+// Patch copies the fields set in p onto m. The title is always copied, the other
+// fields only when they are not zero, and genres when p carries a list, even an
+// empty one. The score is left alone once it is derived from ratings.
+func (m *Movie) Patch(p Movie) {
+	m.Title = p.Title
+	if p.ReleaseYear != 0 {
+		m.ReleaseYear = p.ReleaseYear
+	}
+	if p.Score != 0 && m.RatingCount == 0 {
+		m.Score = p.Score
+	}
+	if p.Genres != nil {
+		m.Genres = p.Genres
+	}
+	if p.RuntimeMinutes != 0 {
+		m.RuntimeMinutes = p.RuntimeMinutes
+	}
+	if p.Synopsis != "" {
+		m.Synopsis = p.Synopsis
+	}
+	if p.OriginalLanguage != "" {
+		m.OriginalLanguage = p.OriginalLanguage
+	}
+	if p.Country != "" {
+		m.Country = p.Country
+	}
+	if p.AgeRating != "" {
+		m.AgeRating = p.AgeRating
+	}
+	if p.PosterURL != "" {
+		m.PosterURL = p.PosterURL
+	}
+}
+
+// Merge fills the fields m leaves blank from d, a duplicate of m, and adds the genres
+// of d. Fields set on m win. The rating aggregate is left to the ratings, which are
+// recalculated once those of d are moved over.
+func (m *Movie) Merge(d Movie) {
+	if m.ReleaseYear == 0 {
+		m.ReleaseYear = d.ReleaseYear
+	}
+	if m.Score == 0 && m.RatingCount == 0 {
+		m.Score = d.Score
+	}
+	if m.RuntimeMinutes == 0 {
+		m.RuntimeMinutes = d.RuntimeMinutes
+	}
+	if m.Synopsis == "" {
+		m.Synopsis = d.Synopsis
+	}
+	if m.OriginalLanguage == "" {
+		m.OriginalLanguage = d.OriginalLanguage
+	}
+	if m.Country == "" {
+		m.Country = d.Country
+	}
+	if m.AgeRating == "" {
+		m.AgeRating = d.AgeRating
+	}
+	if m.PosterURL == "" {
+		m.PosterURL = d.PosterURL
+	}
+
+	for _, genre := range d.Genres {
+		if !hasGenre(*m, genre) {
+			m.Genres = append(m.Genres[:len(m.Genres):len(m.Genres)], genre)
+		}
+	}
+}
+
+// Duplicate is an existing movie resembling a new one. Exact duplicates share the
+// normalized title and release year, the others have a similar title.
+type Duplicate struct {
+	Movie      Movie   `json:"movie" xml:"movie"`
+	Similarity float64 `json:"similarity" xml:"similarity"`
+	Exact      bool    `json:"exact" xml:"exact"`
 }
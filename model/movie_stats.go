@@ -0,0 +1,166 @@
+package model
+
+import "sort"
+
+// ScoreBuckets is the number of buckets of the score histogram, one per point from 0
+// to 10. A perfect 10 counts in the last bucket.
+const ScoreBuckets = 10
+
+// MovieStats summarizes a catalog: counts and average scores overall, per genre, per
+// release year and per decade, the score histogram and the best scored movies
+// overall and per decade.
+type MovieStats struct {
+	Count          int           `json:"count"`
+	AverageScore   float64       `json:"average_score"`
+	ByGenre        []GenreStats  `json:"by_genre"`
+	ByYear         []PeriodStats `json:"by_year"`
+	ByDecade       []PeriodStats `json:"by_decade"`
+	ScoreHistogram []ScoreBucket `json:"score_histogram"`
+	Top            []Movie       `json:"top"`
+	TopByDecade    []DecadeTop   `json:"top_by_decade"`
+}
+
+type GenreStats struct {
+	Genre        string  `json:"genre"`
+	Count        int     `json:"count"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// PeriodStats covers the movies released in Year, or in the decade starting in Year.
+type PeriodStats struct {
+	Year         int     `json:"year"`
+	Count        int     `json:"count"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// ScoreBucket counts the movies scored from From up to, but excluding, To.
+type ScoreBucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+type DecadeTop struct {
+	Decade int     `json:"decade"`
+	Movies []Movie `json:"movies"`
+}
+
+// Decade returns the first year of the decade of year.
+func Decade(year int) int {
+	return year / 10 * 10
+}
+
+// ScoreBucketOf returns the histogram bucket of score.
+func ScoreBucketOf(score float64) int {
+	bucket := int(score)
+	if bucket < 0 {
+		return 0
+	}
+	if bucket >= ScoreBuckets {
+		return ScoreBuckets - 1
+	}
+	return bucket
+}
+
+// NewScoreHistogram lays out the bucket counts, empty buckets included.
+func NewScoreHistogram(counts [ScoreBuckets]int) []ScoreBucket {
+	histogram := make([]ScoreBucket, ScoreBuckets)
+	for k := range histogram {
+		histogram[k] = ScoreBucket{From: float64(k), To: float64(k + 1), Count: counts[k]}
+	}
+	return histogram
+}
+
+// NewMovieStats computes the statistics of movies with top lists of at most topN
+// movies. Averages are rounded to two decimals, ties on score rank the lower id first.
+func NewMovieStats(movies []Movie, topN int) MovieStats {
+	type total struct {
+		count int
+		score float64
+	}
+
+	var all total
+	genres := make(map[string]*total)
+	years := make(map[int]*total)
+	decades := make(map[int]*total)
+	var histogram [ScoreBuckets]int
+
+	add := func(totals map[int]*total, key int, score float64) {
+		if totals[key] == nil {
+			totals[key] = &total{}
+		}
+		totals[key].count++
+		totals[key].score += score
+	}
+
+	for _, movie := range movies {
+		all.count++
+		all.score += movie.Score
+		for _, genre := range movie.Genres {
+			if genres[genre] == nil {
+				genres[genre] = &total{}
+			}
+			genres[genre].count++
+			genres[genre].score += movie.Score
+		}
+		add(years, movie.ReleaseYear, movie.Score)
+		add(decades, Decade(movie.ReleaseYear), movie.Score)
+		histogram[ScoreBucketOf(movie.Score)]++
+	}
+
+	stats := MovieStats{
+		Count:          all.count,
+		ByGenre:        make([]GenreStats, 0, len(genres)),
+		ScoreHistogram: NewScoreHistogram(histogram),
+	}
+	if all.count > 0 {
+		stats.AverageScore = round2(all.score / float64(all.count))
+	}
+
+	for genre, t := range genres {
+		stats.ByGenre = append(stats.ByGenre, GenreStats{Genre: genre, Count: t.count, AverageScore: round2(t.score / float64(t.count))})
+	}
+	sort.Slice(stats.ByGenre, func(a, b int) bool { return stats.ByGenre[a].Genre < stats.ByGenre[b].Genre })
+
+	periods := func(totals map[int]*total) []PeriodStats {
+		list := make([]PeriodStats, 0, len(totals))
+		for year, t := range totals {
+			list = append(list, PeriodStats{Year: year, Count: t.count, AverageScore: round2(t.score / float64(t.count))})
+		}
+		sort.Slice(list, func(a, b int) bool { return list[a].Year < list[b].Year })
+		return list
+	}
+	stats.ByYear = periods(years)
+	stats.ByDecade = periods(decades)
+
+	ranked := append([]Movie{}, movies...)
+	sort.SliceStable(ranked, func(a, b int) bool {
+		if ranked[a].Score != ranked[b].Score {
+			return ranked[a].Score > ranked[b].Score
+		}
+		return ranked[a].ID < ranked[b].ID
+	})
+
+	stats.Top = make([]Movie, 0, topN)
+	stats.TopByDecade = make([]DecadeTop, 0, len(decades))
+	byDecade := make(map[int]int, len(decades))
+	for _, movie := range ranked {
+		if len(stats.Top) < topN {
+			stats.Top = append(stats.Top, movie)
+		}
+
+		decade := Decade(movie.ReleaseYear)
+		k, ok := byDecade[decade]
+		if !ok {
+			k = len(stats.TopByDecade)
+			byDecade[decade] = k
+			stats.TopByDecade = append(stats.TopByDecade, DecadeTop{Decade: decade, Movies: make([]Movie, 0, topN)})
+		}
+		if len(stats.TopByDecade[k].Movies) < topN {
+			stats.TopByDecade[k].Movies = append(stats.TopByDecade[k].Movies, movie)
+		}
+	}
+	sort.Slice(stats.TopByDecade, func(a, b int) bool { return stats.TopByDecade[a].Decade < stats.TopByDecade[b].Decade })
+
+	return stats
+}
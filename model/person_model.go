@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type Person struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	BirthYear int       `json:"birth_year,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Credit roles, a person can hold several of them on the same movie.
+const (
+	RoleDirector = "director"
+	RoleWriter   = "writer"
+	RoleProducer = "producer"
+	RoleActor    = "actor"
+	RoleComposer = "composer"
+	RoleCrew     = "crew"
+)
+
+// Credit links a person to a movie. Character is only set for actors, and
+// BillingOrder sorts the credits of a movie, lowest first.
+type Credit struct {
+	ID           int    `json:"id"`
+	MovieID      int    `json:"movie_id"`
+	PersonID     int    `json:"person_id"`
+	PersonName   string `json:"person_name,omitempty"`
+	Role         string `json:"role"`
+	Character    string `json:"character,omitempty"`
+	BillingOrder int    `json:"billing_order"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	MinRatingValue = 1
+	MaxRatingValue = 10
+
+	// ScorePriorWeight is how many ratings the prior mean counts as in WeightedScore,
+	// so that a movie needs about this many ratings before its own mean dominates.
+	ScorePriorWeight = 10
+	// ScorePrior is the mean a movie's score is pulled towards, the middle of the
+	// rating scale. It is fixed rather than the mean of all ratings, so that rating
+	// one movie never leaves the scores of the others stale.
+	ScorePrior = (MinRatingValue + MaxRatingValue) / 2.0
+)
+
+// Rating is the 1-10 rating of one user, a user has at most one rating per movie.
+type Rating struct {
+	MovieID   int       `json:"movie_id"`
+	UserID    int       `json:"user_id"`
+	Value     int       `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Review struct {
+	ID        int       `json:"id"`
+	MovieID   int       `json:"movie_id"`
+	UserID    int       `json:"user_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RatingSummary is the aggregate kept on a movie: its rating count and mean, and the
+// Score derived from them.
+type RatingSummary struct {
+	MovieID int     `json:"movie_id"`
+	Count   int     `json:"count"`
+	Mean    float64 `json:"mean"`
+	Score   float64 `json:"score"`
+}
+
+// NewRatingSummary computes the Bayesian weighted score of a movie rated count times
+// with the given mean:
+//
+//	score = (count*mean + ScorePriorWeight*ScorePrior) / (count + ScorePriorWeight)
+//
+// Mean and score are rounded to two decimals.
+func NewRatingSummary(movieID int, count int, mean float64) RatingSummary {
+	score := (float64(count)*mean + ScorePriorWeight*ScorePrior) / float64(count+ScorePriorWeight)
+	return RatingSummary{
+		MovieID: movieID,
+		Count:   count,
+		Mean:    round2(mean),
+		Score:   round2(score),
+	}
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,29 @@
+package model
+
+// Neighbour is a precomputed similarity between two movies, NeighbourID being one of
+// the movies most similar to MovieID.
+type Neighbour struct {
+	MovieID     int     `json:"movie_id"`
+	NeighbourID int     `json:"neighbour_id"`
+	Similarity  float64 `json:"similarity"`
+}
+
+type SimilarMovie struct {
+	Movie      Movie   `json:"movie"`
+	Similarity float64 `json:"similarity"`
+}
+
+const (
+	// ReasonSimilar recommends a movie similar to the ones the user rated, Score is
+	// the rating the user is predicted to give it.
+	ReasonSimilar = "similar"
+	// ReasonPopular recommends a well scored movie to a user who did not rate enough
+	// movies yet, Score is the score of the movie.
+	ReasonPopular = "popular"
+)
+
+type Recommendation struct {
+	Movie  Movie   `json:"movie"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
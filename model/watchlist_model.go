@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// WatchlistItem is a movie a user wants to watch. Items are listed by Position,
+// which the user controls by reordering the watchlist.
+type WatchlistItem struct {
+	UserID   int       `json:"user_id"`
+	MovieID  int       `json:"movie_id"`
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"added_at"`
+	Movie    *Movie    `json:"movie,omitempty"`
+}
+
+// WatchedEntry records one viewing, a movie watched twice has two entries.
+type WatchedEntry struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	MovieID   int       `json:"movie_id"`
+	WatchedAt time.Time `json:"watched_at"`
+	Movie     *Movie    `json:"movie,omitempty"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook subscribes a URL to the catalog events. The payloads are signed with its
+// Secret, which is only shown when the webhook is created.
+type Webhook struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+	// Events lists the event types sent to the URL, empty means all of them.
+	Events    []EventType `json:"events"`
+	Secret    string      `json:"secret,omitempty"`
+	Active    bool        `json:"active"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Subscribed reports whether events of eventType are sent to the webhook.
+func (w Webhook) Subscribed(eventType EventType) bool {
+	if !w.Active {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range w.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPatch holds the fields of a webhook to update, nil ones are left unchanged.
+type WebhookPatch struct {
+	URL    *string      `json:"url"`
+	Events *[]EventType `json:"events"`
+	Active *bool        `json:"active"`
+}
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries wait for their next attempt, the first one or a retry.
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead deliveries failed every attempt, they are kept as dead letters until
+	// they are redelivered by hand.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is the log of sending one event to one webhook.
+type WebhookDelivery struct {
+	ID        int64     `json:"id"`
+	WebhookID int       `json:"webhook_id"`
+	EventID   int64     `json:"event_id"`
+	EventType EventType `json:"event_type"`
+	// Payload is the body sent to the webhook, the JSON encoded event.
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// DueDelivery is a delivery claimed for its next attempt, with where to send it and
+// the secret to sign it with.
+type DueDelivery struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
@@ -0,0 +1,85 @@
+package negotiation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseQuality splits an element of an Accept or Accept-Encoding header such as
+// "text/csv;q=0.5" into its value and quality.
+func ParseQuality(element string) (string, float64) {
+	params := strings.Split(element, ";")
+	value := strings.ToLower(strings.TrimSpace(params[0]))
+
+	q := 1.0
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+		if strings.HasPrefix(param, "q=") {
+			parsed, err := strconv.ParseFloat(param[2:], 64)
+			if err == nil {
+				q = parsed
+			}
+		}
+	}
+	return value, q
+}
+
+// MediaType returns the offer with the highest quality in the Accept header, earlier
+// offers winning ties. An empty header accepts the first offer.
+func MediaType(accept string, offers []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := mediaTypeQuality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best, best != ""
+}
+
+// mediaTypeQuality returns the q value of the most specific media range matching offer.
+func mediaTypeQuality(accept, offer string) float64 {
+	q, specificity := 0.0, -1
+	for _, element := range strings.Split(accept, ",") {
+		mediaRange, rangeQ := ParseQuality(element)
+
+		s := -1
+		switch {
+		case mediaRange == offer:
+			s = 2
+		case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaRange, "*")):
+			s = 1
+		case mediaRange == "*/*":
+			s = 0
+		}
+
+		if s > specificity {
+			q, specificity = rangeQ, s
+		}
+	}
+	return q
+}
+
+// Encoding returns the first offer the Accept-Encoding header allows, or false when
+// the response should not be encoded.
+func Encoding(acceptEncoding string, offers []string) (string, bool) {
+	accepted := make(map[string]float64)
+	for _, element := range strings.Split(acceptEncoding, ",") {
+		coding, q := ParseQuality(element)
+		accepted[coding] = q
+	}
+
+	for _, offer := range offers {
+		q, listed := accepted[offer]
+		if !listed {
+			q, listed = accepted["*"]
+		}
+		if listed && q > 0 {
+			return offer, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,55 @@
+package negotiation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMediaType(t *testing.T) {
+	offers := []string{"application/json", "application/xml", "text/csv"}
+
+	testCases := []struct {
+		accept   string
+		expected string
+		ok       bool
+	}{
+		{accept: "", expected: "application/json", ok: true},
+		{accept: "*/*", expected: "application/json", ok: true},
+		{accept: "text/csv", expected: "text/csv", ok: true},
+		{accept: "text/*", expected: "text/csv", ok: true},
+		{accept: "application/xml;q=0.5, text/csv;q=0.9", expected: "text/csv", ok: true},
+		{accept: "application/*;q=0.8, application/xml", expected: "application/xml", ok: true},
+		{accept: "*/*;q=0.1, application/json;q=0", expected: "application/xml", ok: true},
+		{accept: "image/png", ok: false},
+	}
+
+	for _, test := range testCases {
+		mediaType, ok := MediaType(test.accept, offers)
+		assert.Equal(t, test.ok, ok, test.accept)
+		assert.Equal(t, test.expected, mediaType, test.accept)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	offers := []string{"br", "zstd", "gzip"}
+
+	testCases := []struct {
+		acceptEncoding string
+		expected       string
+		ok             bool
+	}{
+		{acceptEncoding: "", ok: false},
+		{acceptEncoding: "gzip, deflate", expected: "gzip", ok: true},
+		{acceptEncoding: "gzip, br", expected: "br", ok: true},
+		{acceptEncoding: "br;q=0, zstd", expected: "zstd", ok: true},
+		{acceptEncoding: "*", expected: "br", ok: true},
+		{acceptEncoding: "*, br;q=0", expected: "zstd", ok: true},
+		{acceptEncoding: "identity", ok: false},
+	}
+
+	for _, test := range testCases {
+		encoding, ok := Encoding(test.acceptEncoding, offers)
+		assert.Equal(t, test.ok, ok, test.acceptEncoding)
+		assert.Equal(t, test.expected, encoding, test.acceptEncoding)
+	}
+}
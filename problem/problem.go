@@ -0,0 +1,41 @@
+package problem
+
+import (
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/requestid"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body, extended with the request ID so that a
+// client report can be matched with the server logs.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func New(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestid.FromContext(r.Context()),
+	}
+}
+
+// Write replies to r with a problem body. It replaces http.Error across the API.
+func Write(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	jsonStr, _ := json.Marshal(New(r, status, detail))
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(jsonStr)
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/problem"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	Read  Limit
+	Write Limit
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Read:  Limit{Rate: 20, Burst: 40},
+		Write: Limit{Rate: 5, Burst: 10},
+	}
+}
+
+// unlimited are the paths of the probes and of the metrics scraper, which must not
+// be turned away because a client at the same address used up its bucket.
+var unlimited = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+type Limiter struct {
+	store      IStore
+	config     Config
+	authorizer *auth.Authorizer
+	logger     *slog.Logger
+}
+
+// NewLimiter limits the clients with the buckets of store, authorizer tells the API
+// keys that identify a client from the made up ones.
+func NewLimiter(store IStore, config Config, authorizer *auth.Authorizer, logger *slog.Logger) *Limiter {
+	return &Limiter{store: store, config: config, authorizer: authorizer, logger: logger}
+}
+
+// Middleware limits every request of next but the probes and the metrics. Reads and
+// writes are counted in separate buckets per client, so a client that floods writes
+// can still read.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unlimited[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result, err := l.Take(isRead(r.Method), auth.APIKey(r), r.RemoteAddr)
+		if err != nil {
+			// Prefer serving the request over failing it when the store is unavailable.
+			l.logger.ErrorContext(r.Context(), "rate limit store", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			problem.Write(w, r, http.StatusTooManyRequests, "too many requests")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Take counts a read or a write of the client presenting key from remoteAddr, for
+// the callers that are not HTTP requests, such as the gRPC calls.
+func (l *Limiter) Take(read bool, key, remoteAddr string) (Result, error) {
+	limit, class := l.config.Write, "write"
+	if read {
+		limit, class = l.config.Read, "read"
+	}
+	return l.store.Take(class+":"+l.clientKey(key, remoteAddr), limit)
+}
+
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// clientKey identifies the caller by API key when the policy knows it, otherwise by
+// remote IP, so that a client cannot get a fresh bucket by making up a key.
+func (l *Limiter) clientKey(key, remoteAddr string) string {
+	if key != "" {
+		if _, err := l.authorizer.RoleOfKey(key); err == nil {
+			return "key:" + key
+		}
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"github.com/dilaragorum/movie-go/auth"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(now *time.Time) http.Handler {
+	store := NewInMemoryStore()
+	store.now = func() time.Time { return *now }
+
+	policy := auth.DefaultPolicy()
+	policy.APIKeys["some-key"] = auth.RoleEditor
+	limiter := NewLimiter(store, Config{
+		Read:  Limit{Rate: 1, Burst: 2},
+		Write: Limit{Rate: 1, Burst: 1},
+	}, auth.NewAuthorizer(policy), logging.NewNop())
+
+	return limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func serve(h http.Handler, method, remoteAddr, apiKey string) *httptest.ResponseRecorder {
+	return servePath(h, method, "/movies", remoteAddr, apiKey)
+}
+
+func servePath(h http.Handler, method, path, remoteAddr, apiKey string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, http.NoBody)
+	req.RemoteAddr = remoteAddr
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	t.Run("Exhausted bucket - TooManyRequests with headers", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		h := newTestLimiter(&now)
+
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodGet, "10.0.0.1:1234", "").Code)
+		rec := serve(h, http.MethodGet, "10.0.0.1:1234", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+		rec = serve(h, http.MethodGet, "10.0.0.1:1234", "")
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Reset"))
+	})
+	t.Run("Bucket refills over time", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		h := newTestLimiter(&now)
+
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodPost, "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(h, http.MethodPost, "10.0.0.1:1234", "").Code)
+
+		now = now.Add(time.Second)
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodPost, "10.0.0.1:1234", "").Code)
+	})
+	t.Run("Reads and writes are limited separately", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		h := newTestLimiter(&now)
+
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodDelete, "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(h, http.MethodPatch, "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodGet, "10.0.0.1:1234", "").Code)
+	})
+	t.Run("Clients are keyed by api key, then by ip", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		h := newTestLimiter(&now)
+
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodPost, "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodPost, "10.0.0.2:1234", "").Code)
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodPost, "10.0.0.1:1234", "some-key").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(h, http.MethodPost, "10.0.0.3:1234", "some-key").Code)
+	})
+	t.Run("Unknown api keys are keyed by ip", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		h := newTestLimiter(&now)
+
+		assert.Equal(t, http.StatusOK, serve(h, http.MethodPost, "10.0.0.1:1234", "made-up-1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(h, http.MethodPost, "10.0.0.1:1234", "made-up-2").Code)
+	})
+	t.Run("Probes and metrics are not limited", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		h := newTestLimiter(&now)
+
+		for _, path := range []string{"/healthz", "/readyz", "/metrics"} {
+			for i := 0; i < 3; i++ {
+				rec := servePath(h, http.MethodGet, path, "10.0.0.1:1234", "")
+				assert.Equal(t, http.StatusOK, rec.Code, path)
+				assert.Empty(t, rec.Header().Get("RateLimit-Limit"), path)
+			}
+		}
+	})
+}
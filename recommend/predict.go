@@ -0,0 +1,69 @@
+package recommend
+
+import (
+	"github.com/dilaragorum/movie-go/model"
+	"math"
+	"sort"
+)
+
+// predictionPriorWeight is how many neighbours worth of similarity the mean rating of
+// the user counts for, so that a single weak neighbour does not predict its rating.
+const predictionPriorWeight = 1.0
+
+// Prediction is the rating a user is predicted to give a movie they did not rate.
+type Prediction struct {
+	MovieID int
+	Rating  float64
+	// Weight is the total similarity the prediction is based on.
+	Weight float64
+}
+
+// Predict predicts the ratings of the neighbours of the movies the user rated, from
+// the ratings of the user weighted by similarity. neighbours holds the neighbours
+// of every rated movie. The predictions are returned best first.
+func Predict(ratings []model.Rating, neighbours map[int][]model.Neighbour) []Prediction {
+	if len(ratings) == 0 {
+		return []Prediction{}
+	}
+
+	rated := make(map[int]bool, len(ratings))
+	total := 0.0
+	for _, rating := range ratings {
+		rated[rating.MovieID] = true
+		total += float64(rating.Value)
+	}
+	mean := total / float64(len(ratings))
+
+	weighted := make(map[int]float64)
+	weights := make(map[int]float64)
+	for _, rating := range ratings {
+		for _, neighbour := range neighbours[rating.MovieID] {
+			if rated[neighbour.NeighbourID] || neighbour.Similarity <= 0 {
+				continue
+			}
+			weighted[neighbour.NeighbourID] += neighbour.Similarity * float64(rating.Value)
+			weights[neighbour.NeighbourID] += neighbour.Similarity
+		}
+	}
+
+	predictions := make([]Prediction, 0, len(weights))
+	for movieID, weight := range weights {
+		rating := (weighted[movieID] + predictionPriorWeight*mean) / (weight + predictionPriorWeight)
+		predictions = append(predictions, Prediction{
+			MovieID: movieID,
+			Rating:  math.Round(rating*100) / 100,
+			Weight:  weight,
+		})
+	}
+
+	sort.Slice(predictions, func(a, b int) bool {
+		if predictions[a].Rating != predictions[b].Rating {
+			return predictions[a].Rating > predictions[b].Rating
+		}
+		if predictions[a].Weight != predictions[b].Weight {
+			return predictions[a].Weight > predictions[b].Weight
+		}
+		return predictions[a].MovieID < predictions[b].MovieID
+	})
+	return predictions
+}
@@ -0,0 +1,211 @@
+// Package recommend scores how similar movies are and predicts how users would rate
+// the movies they did not rate yet.
+//
+// Two movies are similar when their metadata is (genres, release year and score) and
+// when the users who rated both rated them alike (item-based collaborative filtering).
+// The ratings weigh more the more users rated both movies, so that the metadata
+// carries movies nobody rated yet.
+package recommend
+
+import (
+	"github.com/dilaragorum/movie-go/model"
+	"math"
+	"sort"
+)
+
+const (
+	genreWeight = 0.6
+	yearWeight  = 0.25
+	scoreWeight = 0.15
+
+	// yearScale is the release year difference that halves the year similarity.
+	yearScale = 10.0
+
+	// coRatingShrinkage is the number of users who rated both movies at which the
+	// ratings weigh as much as the metadata.
+	coRatingShrinkage = 5.0
+
+	// DefaultNeighbours is the number of neighbours kept per movie.
+	DefaultNeighbours = 20
+)
+
+// Model holds the catalog and the ratings the similarities are computed from.
+type Model struct {
+	movies map[int]model.Movie
+	ids    []int
+
+	// ratings holds the ratings per movie and user, centred on the mean rating of
+	// the user, and moviesOf the movies each user rated.
+	ratings  map[int]map[int]float64
+	moviesOf map[int][]int
+}
+
+// NewModel indexes movies and ratings. Ratings of movies missing from movies are
+// ignored.
+func NewModel(movies []model.Movie, ratings []model.Rating) *Model {
+	m := &Model{
+		movies:   make(map[int]model.Movie, len(movies)),
+		ids:      make([]int, 0, len(movies)),
+		ratings:  make(map[int]map[int]float64),
+		moviesOf: make(map[int][]int),
+	}
+	for _, movie := range movies {
+		m.movies[movie.ID] = movie
+		m.ids = append(m.ids, movie.ID)
+	}
+	sort.Ints(m.ids)
+
+	totals := make(map[int]float64)
+	counts := make(map[int]int)
+	for _, rating := range ratings {
+		if _, ok := m.movies[rating.MovieID]; !ok {
+			continue
+		}
+		totals[rating.UserID] += float64(rating.Value)
+		counts[rating.UserID]++
+	}
+
+	for _, rating := range ratings {
+		if _, ok := m.movies[rating.MovieID]; !ok {
+			continue
+		}
+		if m.ratings[rating.MovieID] == nil {
+			m.ratings[rating.MovieID] = make(map[int]float64)
+		}
+		mean := totals[rating.UserID] / float64(counts[rating.UserID])
+		m.ratings[rating.MovieID][rating.UserID] = float64(rating.Value) - mean
+		m.moviesOf[rating.UserID] = append(m.moviesOf[rating.UserID], rating.MovieID)
+	}
+
+	return m
+}
+
+// Neighbours returns at most k movies most similar to the movie, most similar first.
+// Movies that are not similar at all are left out.
+func (m *Model) Neighbours(movieID int, k int) []model.Neighbour {
+	movie, ok := m.movies[movieID]
+	if !ok {
+		return []model.Neighbour{}
+	}
+
+	coRatings := m.coRatings(movieID)
+
+	neighbours := make([]model.Neighbour, 0)
+	for _, id := range m.ids {
+		if id == movieID {
+			continue
+		}
+
+		similarity := blend(metadataSimilarity(movie, m.movies[id]), coRatings[id])
+		if similarity <= 0 {
+			continue
+		}
+		neighbours = append(neighbours, model.Neighbour{
+			MovieID:     movieID,
+			NeighbourID: id,
+			Similarity:  math.Round(similarity*10000) / 10000,
+		})
+	}
+
+	sort.Slice(neighbours, func(a, b int) bool {
+		if neighbours[a].Similarity != neighbours[b].Similarity {
+			return neighbours[a].Similarity > neighbours[b].Similarity
+		}
+		return neighbours[a].NeighbourID < neighbours[b].NeighbourID
+	})
+	if len(neighbours) > k {
+		neighbours = neighbours[:k]
+	}
+	return neighbours
+}
+
+// AllNeighbours returns the neighbours of every movie, as Neighbours does.
+func (m *Model) AllNeighbours(k int) []model.Neighbour {
+	all := make([]model.Neighbour, 0, len(m.ids)*k)
+	for _, id := range m.ids {
+		all = append(all, m.Neighbours(id, k)...)
+	}
+	return all
+}
+
+// coRating accumulates the adjusted cosine similarity of two movies over the users
+// who rated both.
+type coRating struct {
+	users             int
+	dot, normA, normB float64
+}
+
+func (c *coRating) similarity() float64 {
+	if c.normA == 0 || c.normB == 0 {
+		return 0
+	}
+	return c.dot / math.Sqrt(c.normA*c.normB)
+}
+
+// coRatings walks the users who rated the movie and the other movies they rated.
+func (m *Model) coRatings(movieID int) map[int]*coRating {
+	coRatings := make(map[int]*coRating)
+	for userID, a := range m.ratings[movieID] {
+		for _, id := range m.moviesOf[userID] {
+			if id == movieID {
+				continue
+			}
+			b := m.ratings[id][userID]
+
+			c := coRatings[id]
+			if c == nil {
+				c = &coRating{}
+				coRatings[id] = c
+			}
+			c.users++
+			c.dot += a * b
+			c.normA += a * a
+			c.normB += b * b
+		}
+	}
+	return coRatings
+}
+
+// blend weighs the rating similarity by the number of users it is based on.
+func blend(metadata float64, c *coRating) float64 {
+	if c == nil || c.users == 0 {
+		return metadata
+	}
+	weight := float64(c.users) / (float64(c.users) + coRatingShrinkage)
+	return (1-weight)*metadata + weight*c.similarity()
+}
+
+// metadataSimilarity is between 0 and 1.
+func metadataSimilarity(a, b model.Movie) float64 {
+	similarity := genreWeight * jaccard(a.Genres, b.Genres)
+
+	if a.ReleaseYear != 0 && b.ReleaseYear != 0 {
+		years := math.Abs(float64(a.ReleaseYear - b.ReleaseYear))
+		similarity += yearWeight / (1 + years/yearScale)
+	}
+
+	if a.Score > 0 && b.Score > 0 {
+		similarity += scoreWeight * (1 - math.Abs(a.Score-b.Score)/10)
+	}
+
+	return similarity
+}
+
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(a))
+	for _, genre := range a {
+		set[genre] = true
+	}
+
+	shared := 0
+	for _, genre := range b {
+		if set[genre] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,81 @@
+package recommend
+
+import (
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testMovies = []model.Movie{
+	{ID: 1, ReleaseYear: 1994, Score: 9.3, Genres: []string{"drama"}},
+	{ID: 2, ReleaseYear: 1972, Score: 9.2, Genres: []string{"crime", "drama"}},
+	{ID: 3, ReleaseYear: 2008, Score: 9.0, Genres: []string{"action", "crime", "drama"}},
+	{ID: 4, ReleaseYear: 2020, Score: 5.0, Genres: []string{"comedy"}},
+}
+
+func neighbourIDs(neighbours []model.Neighbour) []int {
+	ids := make([]int, 0, len(neighbours))
+	for _, neighbour := range neighbours {
+		ids = append(ids, neighbour.NeighbourID)
+	}
+	return ids
+}
+
+func TestModel_Neighbours(t *testing.T) {
+	t.Run("Metadata only", func(t *testing.T) {
+		m := NewModel(testMovies, nil)
+
+		neighbours := m.Neighbours(2, DefaultNeighbours)
+
+		assert.Equal(t, []int{3, 1, 4}, neighbourIDs(neighbours))
+		assert.Equal(t, 2, neighbours[0].MovieID)
+		assert.Equal(t, []int{3}, neighbourIDs(m.Neighbours(2, 1)))
+		assert.Empty(t, m.Neighbours(9, DefaultNeighbours))
+	})
+	t.Run("Ratings outweigh metadata with enough co-raters", func(t *testing.T) {
+		var ratings []model.Rating
+		for userID := 1; userID <= 20; userID++ {
+			// Who loves 1 loves 4 and dislikes 3, and the other way around.
+			high, low := 10, 2
+			if userID%2 == 0 {
+				high, low = low, high
+			}
+			ratings = append(ratings,
+				model.Rating{MovieID: 1, UserID: userID, Value: high},
+				model.Rating{MovieID: 4, UserID: userID, Value: high},
+				model.Rating{MovieID: 3, UserID: userID, Value: low},
+			)
+		}
+
+		m := NewModel(testMovies, ratings)
+
+		assert.Equal(t, []int{4, 2}, neighbourIDs(m.Neighbours(1, DefaultNeighbours)))
+	})
+	t.Run("AllNeighbours", func(t *testing.T) {
+		m := NewModel(testMovies, []model.Rating{{MovieID: 9, UserID: 1, Value: 5}})
+
+		all := m.AllNeighbours(1)
+
+		assert.Equal(t, []int{2, 3, 2, 3}, neighbourIDs(all))
+		for k, neighbour := range all {
+			assert.Equal(t, testMovies[k].ID, neighbour.MovieID)
+		}
+	})
+}
+
+func TestPredict(t *testing.T) {
+	ratings := []model.Rating{{MovieID: 1, UserID: 1, Value: 10}, {MovieID: 4, UserID: 1, Value: 2}}
+	neighbours := map[int][]model.Neighbour{
+		1: {{MovieID: 1, NeighbourID: 2, Similarity: 0.8}},
+		4: {{MovieID: 4, NeighbourID: 3, Similarity: 0.5}, {MovieID: 4, NeighbourID: 2, Similarity: 0.2}, {MovieID: 4, NeighbourID: 1, Similarity: 0.1}},
+	}
+
+	predictions := Predict(ratings, neighbours)
+
+	// Both predictions are pulled towards the mean rating of the user, 6.
+	assert.Equal(t, []Prediction{
+		{MovieID: 2, Rating: 7.2, Weight: 1.0},
+		{MovieID: 3, Rating: 4.67, Weight: 0.5},
+	}, predictions)
+	assert.Empty(t, Predict(nil, neighbours))
+}
@@ -1,8 +1,13 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
 )
 
 var (
@@ -10,26 +15,104 @@ var (
 )
 
 type inmemoryMovieRepository struct {
+	mu     sync.RWMutex
 	Movies []model.Movie
+	logger *slog.Logger
+	// nextID only grows, so that the ID of a deleted or merged movie is not reused.
+	nextID int
+
+	// editorialScores keeps the score each movie was created or last updated with,
+	// the counterpart of the editorial_score column.
+	editorialScores map[int]float64
+
+	// onDelete holds the cleanups of the other in-memory repositories, the
+	// counterpart of the ON DELETE CASCADE foreign keys in PostgreSQL, and onMerge
+	// the moves of their records from a merged movie to the one it is merged into.
+	onDelete []func(movieIDs []int)
+	onMerge  []func(targetID int, sourceID int)
 }
 
-func NewInMemoryMovieRepository() *inmemoryMovieRepository {
+func NewInMemoryMovieRepository(logger *slog.Logger) *inmemoryMovieRepository {
+	seededAt := time.Now().UTC()
 	var movies = []model.Movie{
-		{ID: 1, Title: "The Shawshank Redemption", ReleaseYear: 1994, Score: 9.3},
-		{ID: 2, Title: "The Godfather", ReleaseYear: 1972, Score: 9.2},
-		{ID: 3, Title: "The Dark Knight", ReleaseYear: 2008, Score: 9.0},
+		{
+			ID: 1, Title: "The Shawshank Redemption", ReleaseYear: 1994, Score: 9.3,
+			Genres: []string{"drama"}, RuntimeMinutes: 142, OriginalLanguage: "en", Country: "US", AgeRating: "R",
+			UpdatedAt: seededAt,
+		},
+		{
+			ID: 2, Title: "The Godfather", ReleaseYear: 1972, Score: 9.2,
+			Genres: []string{"crime", "drama"}, RuntimeMinutes: 175, OriginalLanguage: "en", Country: "US", AgeRating: "R",
+			UpdatedAt: seededAt,
+		},
+		{
+			ID: 3, Title: "The Dark Knight", ReleaseYear: 2008, Score: 9.0,
+			Genres: []string{"action", "crime", "drama"}, RuntimeMinutes: 152, OriginalLanguage: "en", Country: "US", AgeRating: "PG-13",
+			UpdatedAt: seededAt,
+		},
+	}
+
+	logger.Debug("in-memory movie repository seeded", "movies", len(movies))
+
+	editorialScores := make(map[int]float64, len(movies))
+	for _, movie := range movies {
+		editorialScores[movie.ID] = movie.Score
 	}
 
 	return &inmemoryMovieRepository{
-		Movies: movies,
+		Movies:          movies,
+		nextID:          len(movies) + 1,
+		logger:          logger,
+		editorialScores: editorialScores,
 	}
 }
 
-func (i *inmemoryMovieRepository) GetMovies() ([]model.Movie, error) {
-	return i.Movies, nil
+func (i *inmemoryMovieRepository) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if filter.IsZero() {
+		return append([]model.Movie{}, i.Movies...), nil
+	}
+
+	movies := make([]model.Movie, 0)
+	for _, movie := range i.Movies {
+		if filter.Match(movie) {
+			movies = append(movies, movie)
+		}
+	}
+	return movies, nil
 }
 
-func (i *inmemoryMovieRepository) GetMovie(id int) (model.Movie, error) {
+// StreamMovies iterates over a copy, so fn may take its time without blocking writes.
+func (i *inmemoryMovieRepository) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error {
+	i.mu.RLock()
+	movies := append([]model.Movie{}, i.Movies...)
+	i.mu.RUnlock()
+
+	for _, movie := range movies {
+		if !filter.Match(movie) {
+			continue
+		}
+		if err := fn(movie); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *inmemoryMovieRepository) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	movies, err := i.GetMovies(ctx, filter)
+	if err != nil {
+		return model.MovieStats{}, err
+	}
+	return model.NewMovieStats(movies, topN), nil
+}
+
+func (i *inmemoryMovieRepository) GetMovie(ctx context.Context, id int) (model.Movie, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
 	for _, movie := range i.Movies {
 		if movie.ID == id {
 			return movie, nil
@@ -38,14 +121,78 @@ func (i *inmemoryMovieRepository) GetMovie(id int) (model.Movie, error) {
 	return model.Movie{}, ErrMovieNotFound
 }
 
-func (i *inmemoryMovieRepository) CreateMovie(movie model.Movie) error {
-	movie.ID = len(i.Movies) + 1
+func (i *inmemoryMovieRepository) GetMoviesByID(ctx context.Context, ids []int) ([]model.Movie, error) {
+	wanted := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	movies := make([]model.Movie, 0, len(ids))
+	for _, movie := range i.Movies {
+		if wanted[movie.ID] {
+			movies = append(movies, movie)
+		}
+	}
+	return movies, nil
+}
+
+func (i *inmemoryMovieRepository) GetBestScoredMovies(ctx context.Context, limit int, excludedIDs []int) ([]model.Movie, error) {
+	excluded := make(map[int]bool, len(excludedIDs))
+	for _, id := range excludedIDs {
+		excluded[id] = true
+	}
+
+	i.mu.RLock()
+	movies := make([]model.Movie, 0, len(i.Movies))
+	for _, movie := range i.Movies {
+		if !excluded[movie.ID] {
+			movies = append(movies, movie)
+		}
+	}
+	i.mu.RUnlock()
+
+	sort.SliceStable(movies, func(a, b int) bool { return movies[a].Score > movies[b].Score })
+	if len(movies) > limit {
+		movies = movies[:limit]
+	}
+	return movies, nil
+}
+
+// LockMovieTitle has nothing to do: the in-memory transactor runs the transactions
+// one at a time.
+func (i *inmemoryMovieRepository) LockMovieTitle(ctx context.Context, titleKey string, releaseYear int) error {
+	return nil
+}
+
+func (i *inmemoryMovieRepository) CreateMovie(ctx context.Context, movie model.Movie) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	movie.ID = i.nextID
+	i.nextID++
+	movie.UpdatedAt = time.Now().UTC()
 	i.Movies = append(i.Movies, movie)
+	i.editorialScores[movie.ID] = movie.Score
+
+	return movie.ID, nil
+}
 
+func (i *inmemoryMovieRepository) DeleteMovie(ctx context.Context, id int) error {
+	if err := i.deleteMovie(id); err != nil {
+		return err
+	}
+
+	i.cascade([]int{id})
 	return nil
 }
 
-func (i *inmemoryMovieRepository) DeleteMovie(id int) error {
+func (i *inmemoryMovieRepository) deleteMovie(id int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
 	movieExist := false
 
 	var newMovieList []model.Movie
@@ -62,22 +209,136 @@ func (i *inmemoryMovieRepository) DeleteMovie(id int) error {
 	}
 
 	i.Movies = newMovieList
+	delete(i.editorialScores, id)
 
 	return nil
 }
 
-func (i *inmemoryMovieRepository) DeleteAllMovies() error {
+func (i *inmemoryMovieRepository) DeleteAllMovies(ctx context.Context) ([]int, error) {
+	i.mu.Lock()
+	ids := make([]int, 0, len(i.Movies))
+	for _, movie := range i.Movies {
+		ids = append(ids, movie.ID)
+	}
 	i.Movies = nil
+	i.editorialScores = make(map[int]float64)
+	i.mu.Unlock()
+
+	i.cascade(ids)
+	return ids, nil
+}
+
+// cascade runs the onDelete cleanups once the movies are gone, without holding the
+// lock, so that the cleanups are free to read the remaining movies.
+func (i *inmemoryMovieRepository) cascade(movieIDs []int) {
+	i.mu.RLock()
+	onDelete := append([]func(movieIDs []int){}, i.onDelete...)
+	i.mu.RUnlock()
+
+	for _, fn := range onDelete {
+		fn(movieIDs)
+	}
+}
+
+func (i *inmemoryMovieRepository) registerOnDelete(fn func(movieIDs []int)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.onDelete = append(i.onDelete, fn)
+}
+
+// MergeMovies folds the source movie into the target one and removes it. The onMerge
+// moves run afterwards, like the cascade of DeleteMovie.
+func (i *inmemoryMovieRepository) MergeMovies(ctx context.Context, targetID int, sourceID int) error {
+	if err := i.mergeMovies(targetID, sourceID); err != nil {
+		return err
+	}
+
+	i.mu.RLock()
+	onMerge := append([]func(targetID int, sourceID int){}, i.onMerge...)
+	i.mu.RUnlock()
+
+	for _, fn := range onMerge {
+		fn(targetID, sourceID)
+	}
+	return nil
+}
+
+func (i *inmemoryMovieRepository) mergeMovies(targetID int, sourceID int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	target, source := -1, -1
+	for k, movie := range i.Movies {
+		switch movie.ID {
+		case targetID:
+			target = k
+		case sourceID:
+			source = k
+		}
+	}
+	if target < 0 || source < 0 {
+		return ErrMovieNotFound
+	}
+
+	i.Movies[target].Merge(i.Movies[source])
+	i.Movies[target].UpdatedAt = time.Now().UTC()
+	if i.editorialScores[targetID] == 0 {
+		i.editorialScores[targetID] = i.editorialScores[sourceID]
+	}
+	delete(i.editorialScores, sourceID)
+	i.Movies = append(i.Movies[:source:source], i.Movies[source+1:]...)
 	return nil
 }
 
-func (i *inmemoryMovieRepository) UpdateMovie(id int, movie model.Movie) error {
+func (i *inmemoryMovieRepository) registerOnMerge(fn func(targetID int, sourceID int)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.onMerge = append(i.onMerge, fn)
+}
+
+func (i *inmemoryMovieRepository) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
 	for k := 0; k < len(i.Movies); k++ {
 		if i.Movies[k].ID == id {
-			i.Movies[k].Title = movie.Title
+			i.Movies[k].Patch(movie)
+			i.Movies[k].UpdatedAt = time.Now().UTC()
+			if movie.Score != 0 {
+				i.editorialScores[id] = movie.Score
+			}
 			return nil
 		}
 	}
 
 	return ErrMovieNotFound
 }
+
+// setRatingSummary stores the rating aggregate of a movie, the in-memory counterpart
+// of the UPDATE the PostgreSQL rating repository runs in its transaction.
+func (i *inmemoryMovieRepository) setRatingSummary(summary model.RatingSummary) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for k := range i.Movies {
+		if i.Movies[k].ID == summary.MovieID {
+			i.Movies[k].RatingCount = summary.Count
+			i.Movies[k].RatingMean = summary.Mean
+			i.Movies[k].Score = i.editorialScores[summary.MovieID]
+			if summary.Count > 0 {
+				i.Movies[k].Score = summary.Score
+			}
+			i.Movies[k].UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+
+	return ErrMovieNotFound
+}
+
+// Ping always succeeds: the in-memory repository is ready as soon as it is created.
+func (i *inmemoryMovieRepository) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type inmemoryOutboxRepository struct {
+	mu     sync.Mutex
+	events []model.Event
+	nextID int64
+	// claimedUntil holds the leases of the claimed events.
+	claimedUntil map[int64]time.Time
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+func NewInMemoryOutboxRepository(logger *slog.Logger) *inmemoryOutboxRepository {
+	return &inmemoryOutboxRepository{nextID: 1, claimedUntil: make(map[int64]time.Time), logger: logger, now: time.Now}
+}
+
+func (i *inmemoryOutboxRepository) AppendEvents(ctx context.Context, events []model.Event) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for k := range events {
+		events[k].ID = i.nextID
+		i.nextID++
+		i.events = append(i.events, events[k])
+	}
+	return nil
+}
+
+func (i *inmemoryOutboxRepository) ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	events := make([]model.Event, 0, limit)
+	for _, event := range i.events {
+		if len(events) == limit {
+			break
+		}
+		if until, ok := i.claimedUntil[event.ID]; ok && !until.Before(now) {
+			continue
+		}
+		i.claimedUntil[event.ID] = now.Add(lease)
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (i *inmemoryOutboxRepository) ReleaseEvents(ctx context.Context, ids []int64) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, id := range ids {
+		delete(i.claimedUntil, id)
+	}
+	return nil
+}
+
+func (i *inmemoryOutboxRepository) DeleteEvents(ctx context.Context, ids []int64) error {
+	deleted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	events := i.events[:0:0]
+	for _, event := range i.events {
+		if !deleted[event.ID] {
+			events = append(events, event)
+		} else {
+			delete(i.claimedUntil, event.ID)
+		}
+	}
+	i.events = events
+	return nil
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPersonNotFound = errors.New("FromRepository - person not found")
+	ErrCreditNotFound = errors.New("FromRepository - credit not found")
+)
+
+type inmemoryPersonRepository struct {
+	mu           sync.RWMutex
+	people       []model.Person
+	credits      []model.Credit
+	nextPersonID int
+	nextCreditID int
+	movies       *inmemoryMovieRepository
+	logger       *slog.Logger
+}
+
+// NewInMemoryPersonRepository keeps people and credits in memory and reads the
+// credited movies from movies. Deleting a movie from movies deletes its credits.
+func NewInMemoryPersonRepository(movies *inmemoryMovieRepository, logger *slog.Logger) *inmemoryPersonRepository {
+	seededAt := time.Now().UTC()
+	people := []model.Person{
+		{ID: 1, Name: "Frank Darabont", BirthYear: 1959, UpdatedAt: seededAt},
+		{ID: 2, Name: "Tim Robbins", BirthYear: 1958, UpdatedAt: seededAt},
+		{ID: 3, Name: "Morgan Freeman", BirthYear: 1937, UpdatedAt: seededAt},
+		{ID: 4, Name: "Francis Ford Coppola", BirthYear: 1939, UpdatedAt: seededAt},
+		{ID: 5, Name: "Marlon Brando", BirthYear: 1924, UpdatedAt: seededAt},
+		{ID: 6, Name: "Al Pacino", BirthYear: 1940, UpdatedAt: seededAt},
+		{ID: 7, Name: "Christopher Nolan", BirthYear: 1970, UpdatedAt: seededAt},
+		{ID: 8, Name: "Christian Bale", BirthYear: 1974, UpdatedAt: seededAt},
+		{ID: 9, Name: "Heath Ledger", BirthYear: 1979, UpdatedAt: seededAt},
+	}
+	credits := []model.Credit{
+		{ID: 1, MovieID: 1, PersonID: 1, Role: model.RoleDirector},
+		{ID: 2, MovieID: 1, PersonID: 1, Role: model.RoleWriter, BillingOrder: 1},
+		{ID: 3, MovieID: 1, PersonID: 2, Role: model.RoleActor, Character: "Andy Dufresne", BillingOrder: 2},
+		{ID: 4, MovieID: 1, PersonID: 3, Role: model.RoleActor, Character: "Ellis Boyd 'Red' Redding", BillingOrder: 3},
+		{ID: 5, MovieID: 2, PersonID: 4, Role: model.RoleDirector},
+		{ID: 6, MovieID: 2, PersonID: 4, Role: model.RoleWriter, BillingOrder: 1},
+		{ID: 7, MovieID: 2, PersonID: 5, Role: model.RoleActor, Character: "Don Vito Corleone", BillingOrder: 2},
+		{ID: 8, MovieID: 2, PersonID: 6, Role: model.RoleActor, Character: "Michael Corleone", BillingOrder: 3},
+		{ID: 9, MovieID: 3, PersonID: 7, Role: model.RoleDirector},
+		{ID: 10, MovieID: 3, PersonID: 7, Role: model.RoleWriter, BillingOrder: 1},
+		{ID: 11, MovieID: 3, PersonID: 8, Role: model.RoleActor, Character: "Bruce Wayne", BillingOrder: 2},
+		{ID: 12, MovieID: 3, PersonID: 9, Role: model.RoleActor, Character: "Joker", BillingOrder: 3},
+	}
+
+	logger.Debug("in-memory person repository seeded", "people", len(people), "credits", len(credits))
+
+	i := &inmemoryPersonRepository{
+		people:       people,
+		credits:      credits,
+		nextPersonID: len(people) + 1,
+		nextCreditID: len(credits) + 1,
+		movies:       movies,
+		logger:       logger,
+	}
+	movies.registerOnDelete(i.deleteMovieCredits)
+	movies.registerOnMerge(i.mergeMovieCredits)
+	return i
+}
+
+func (i *inmemoryPersonRepository) deleteMovieCredits(movieIDs []int) {
+	deleted := make(map[int]bool, len(movieIDs))
+	for _, id := range movieIDs {
+		deleted[id] = true
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	credits := i.credits[:0:0]
+	for _, credit := range i.credits {
+		if !deleted[credit.MovieID] {
+			credits = append(credits, credit)
+		}
+	}
+	i.credits = credits
+}
+
+// mergeMovieCredits moves the credits of the source movie to the target one, except
+// for the ones the target already has.
+func (i *inmemoryPersonRepository) mergeMovieCredits(targetID int, sourceID int) {
+	type creditKey struct {
+		personID  int
+		role      string
+		character string
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	existing := make(map[creditKey]bool)
+	for _, credit := range i.credits {
+		if credit.MovieID == targetID {
+			existing[creditKey{credit.PersonID, credit.Role, credit.Character}] = true
+		}
+	}
+
+	credits := i.credits[:0:0]
+	for _, credit := range i.credits {
+		if credit.MovieID == sourceID {
+			key := creditKey{credit.PersonID, credit.Role, credit.Character}
+			if existing[key] {
+				continue
+			}
+			existing[key] = true
+			credit.MovieID = targetID
+		}
+		credits = append(credits, credit)
+	}
+	i.credits = credits
+}
+
+func (i *inmemoryPersonRepository) GetPeople(ctx context.Context) ([]model.Person, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return append([]model.Person{}, i.people...), nil
+}
+
+func (i *inmemoryPersonRepository) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, person := range i.people {
+		if person.ID == id {
+			return person, nil
+		}
+	}
+	return model.Person{}, ErrPersonNotFound
+}
+
+func (i *inmemoryPersonRepository) CreatePerson(ctx context.Context, person model.Person) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	person.ID = i.nextPersonID
+	person.UpdatedAt = time.Now().UTC()
+	i.nextPersonID++
+	i.people = append(i.people, person)
+
+	return nil
+}
+
+func (i *inmemoryPersonRepository) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for k := range i.people {
+		if i.people[k].ID == id {
+			i.people[k].Name = person.Name
+			if person.BirthYear != 0 {
+				i.people[k].BirthYear = person.BirthYear
+			}
+			i.people[k].UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+
+	return ErrPersonNotFound
+}
+
+// DeletePerson removes the person together with their credits.
+func (i *inmemoryPersonRepository) DeletePerson(ctx context.Context, id int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for k, person := range i.people {
+		if person.ID != id {
+			continue
+		}
+
+		i.people = append(i.people[:k:k], i.people[k+1:]...)
+
+		credits := i.credits[:0:0]
+		for _, credit := range i.credits {
+			if credit.PersonID != id {
+				credits = append(credits, credit)
+			}
+		}
+		i.credits = credits
+		return nil
+	}
+
+	return ErrPersonNotFound
+}
+
+func (i *inmemoryPersonRepository) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	names := make(map[int]string, len(i.people))
+	for _, person := range i.people {
+		names[person.ID] = person.Name
+	}
+
+	credits := make([]model.Credit, 0)
+	for _, credit := range i.credits {
+		if credit.MovieID == movieID {
+			credit.PersonName = names[credit.PersonID]
+			credits = append(credits, credit)
+		}
+	}
+
+	sort.SliceStable(credits, func(a, b int) bool {
+		return credits[a].BillingOrder < credits[b].BillingOrder
+	})
+	return credits, nil
+}
+
+func (i *inmemoryPersonRepository) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	wanted := make(map[int]bool, len(movieIDs))
+	for _, movieID := range movieIDs {
+		wanted[movieID] = true
+	}
+	names := make(map[int]string, len(i.people))
+	for _, person := range i.people {
+		names[person.ID] = person.Name
+	}
+
+	credits := make(map[int][]model.Credit)
+	for _, credit := range i.credits {
+		if wanted[credit.MovieID] {
+			credit.PersonName = names[credit.PersonID]
+			credits[credit.MovieID] = append(credits[credit.MovieID], credit)
+		}
+	}
+
+	for _, movieCredits := range credits {
+		sort.SliceStable(movieCredits, func(a, b int) bool {
+			return movieCredits[a].BillingOrder < movieCredits[b].BillingOrder
+		})
+	}
+	return credits, nil
+}
+
+func (i *inmemoryPersonRepository) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	i.mu.RLock()
+	var movieIDs []int
+	seen := make(map[int]bool)
+	for _, credit := range i.credits {
+		if credit.PersonID != personID || (role != "" && credit.Role != role) || seen[credit.MovieID] {
+			continue
+		}
+		seen[credit.MovieID] = true
+		movieIDs = append(movieIDs, credit.MovieID)
+	}
+	i.mu.RUnlock()
+
+	movies := make([]model.Movie, 0, len(movieIDs))
+	for _, movieID := range movieIDs {
+		movie, err := i.movies.GetMovie(ctx, movieID)
+		if errors.Is(err, ErrMovieNotFound) {
+			// Deleted after the credits were read, its cleanup is on the way.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+
+	sort.SliceStable(movies, func(a, b int) bool {
+		if movies[a].ReleaseYear != movies[b].ReleaseYear {
+			return movies[a].ReleaseYear < movies[b].ReleaseYear
+		}
+		return movies[a].ID < movies[b].ID
+	})
+	return movies, nil
+}
+
+func (i *inmemoryPersonRepository) AddCredit(ctx context.Context, credit model.Credit) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	credit.ID = i.nextCreditID
+	credit.PersonName = ""
+	i.nextCreditID++
+	i.credits = append(i.credits, credit)
+
+	return nil
+}
+
+func (i *inmemoryPersonRepository) DeleteCredit(ctx context.Context, movieID int, creditID int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for k, credit := range i.credits {
+		if credit.ID == creditID && credit.MovieID == movieID {
+			i.credits = append(i.credits[:k:k], i.credits[k+1:]...)
+			return nil
+		}
+	}
+
+	return ErrCreditNotFound
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRatingNotFound = errors.New("FromRepository - rating not found")
+	ErrReviewNotFound = errors.New("FromRepository - review not found")
+)
+
+type ratingKey struct {
+	movieID int
+	userID  int
+}
+
+type inmemoryRatingRepository struct {
+	mu           sync.Mutex
+	ratings      map[ratingKey]model.Rating
+	reviews      []model.Review
+	nextReviewID int
+	movies       *inmemoryMovieRepository
+	logger       *slog.Logger
+}
+
+// NewInMemoryRatingRepository keeps the rating aggregates on the movies of movies.
+// Deleting a movie from movies deletes its ratings and reviews.
+func NewInMemoryRatingRepository(movies *inmemoryMovieRepository, logger *slog.Logger) *inmemoryRatingRepository {
+	i := &inmemoryRatingRepository{
+		ratings:      make(map[ratingKey]model.Rating),
+		nextReviewID: 1,
+		movies:       movies,
+		logger:       logger,
+	}
+	movies.registerOnDelete(i.deleteMovieRatings)
+	movies.registerOnMerge(i.mergeMovieRatings)
+	return i
+}
+
+func (i *inmemoryRatingRepository) deleteMovieRatings(movieIDs []int) {
+	deleted := make(map[int]bool, len(movieIDs))
+	for _, id := range movieIDs {
+		deleted[id] = true
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for key := range i.ratings {
+		if deleted[key.movieID] {
+			delete(i.ratings, key)
+		}
+	}
+
+	reviews := i.reviews[:0:0]
+	for _, review := range i.reviews {
+		if !deleted[review.MovieID] {
+			reviews = append(reviews, review)
+		}
+	}
+	i.reviews = reviews
+}
+
+// mergeMovieRatings moves the ratings and reviews of the source movie to the target
+// one, the rating of a user who rated both is the one of the target. The aggregate of
+// the target is recalculated.
+func (i *inmemoryRatingRepository) mergeMovieRatings(targetID int, sourceID int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for key, rating := range i.ratings {
+		if key.movieID != sourceID {
+			continue
+		}
+		delete(i.ratings, key)
+
+		targetKey := ratingKey{movieID: targetID, userID: key.userID}
+		if _, ok := i.ratings[targetKey]; !ok {
+			rating.MovieID = targetID
+			i.ratings[targetKey] = rating
+		}
+	}
+
+	for k := range i.reviews {
+		if i.reviews[k].MovieID == sourceID {
+			i.reviews[k].MovieID = targetID
+		}
+	}
+
+	if _, err := i.recalculate(context.Background(), targetID); err != nil {
+		i.logger.Warn("recalculating merged ratings", "movie_id", targetID, "error", err)
+	}
+}
+
+func (i *inmemoryRatingRepository) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ratings := make([]model.Rating, 0)
+	for key, rating := range i.ratings {
+		if key.movieID == movieID {
+			ratings = append(ratings, rating)
+		}
+	}
+
+	sort.Slice(ratings, func(a, b int) bool { return ratings[a].UserID < ratings[b].UserID })
+	return ratings, nil
+}
+
+func (i *inmemoryRatingRepository) GetUserRatings(ctx context.Context, userID int) ([]model.Rating, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ratings := make([]model.Rating, 0)
+	for key, rating := range i.ratings {
+		if key.userID == userID {
+			ratings = append(ratings, rating)
+		}
+	}
+
+	sort.Slice(ratings, func(a, b int) bool {
+		if !ratings[a].UpdatedAt.Equal(ratings[b].UpdatedAt) {
+			return ratings[a].UpdatedAt.After(ratings[b].UpdatedAt)
+		}
+		return ratings[a].MovieID < ratings[b].MovieID
+	})
+	return ratings, nil
+}
+
+// StreamRatings iterates over a copy, so fn may take its time without blocking writes.
+func (i *inmemoryRatingRepository) StreamRatings(ctx context.Context, fn func(rating model.Rating) error) error {
+	i.mu.Lock()
+	ratings := make([]model.Rating, 0, len(i.ratings))
+	for _, rating := range i.ratings {
+		ratings = append(ratings, rating)
+	}
+	i.mu.Unlock()
+
+	sort.Slice(ratings, func(a, b int) bool {
+		if ratings[a].MovieID != ratings[b].MovieID {
+			return ratings[a].MovieID < ratings[b].MovieID
+		}
+		return ratings[a].UserID < ratings[b].UserID
+	})
+
+	for _, rating := range ratings {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(rating); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *inmemoryRatingRepository) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, err := i.movies.GetMovie(ctx, rating.MovieID); err != nil {
+		return model.RatingSummary{}, err
+	}
+
+	rating.UpdatedAt = time.Now().UTC()
+	i.ratings[ratingKey{movieID: rating.MovieID, userID: rating.UserID}] = rating
+
+	return i.recalculate(ctx, rating.MovieID)
+}
+
+func (i *inmemoryRatingRepository) DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	key := ratingKey{movieID: movieID, userID: userID}
+	if _, ok := i.ratings[key]; !ok {
+		return model.RatingSummary{}, ErrRatingNotFound
+	}
+	delete(i.ratings, key)
+
+	return i.recalculate(ctx, movieID)
+}
+
+// recalculate must be called with i.mu held, which keeps the ratings still while the
+// movie is updated.
+func (i *inmemoryRatingRepository) recalculate(ctx context.Context, movieID int) (model.RatingSummary, error) {
+	var count, total int
+	for key, rating := range i.ratings {
+		if key.movieID == movieID {
+			count++
+			total += rating.Value
+		}
+	}
+
+	var summary model.RatingSummary
+	if count == 0 {
+		summary = model.RatingSummary{MovieID: movieID}
+	} else {
+		summary = model.NewRatingSummary(movieID, count, float64(total)/float64(count))
+	}
+
+	if err := i.movies.setRatingSummary(summary); err != nil {
+		return model.RatingSummary{}, err
+	}
+
+	movie, err := i.movies.GetMovie(ctx, movieID)
+	if err != nil {
+		return model.RatingSummary{}, err
+	}
+	summary.Score = movie.Score
+	return summary, nil
+}
+
+func (i *inmemoryRatingRepository) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	reviews := make([]model.Review, 0)
+	for _, review := range i.reviews {
+		if review.MovieID == movieID {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (i *inmemoryRatingRepository) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	wanted := make(map[int]bool, len(movieIDs))
+	for _, movieID := range movieIDs {
+		wanted[movieID] = true
+	}
+
+	reviews := make(map[int][]model.Review)
+	for _, review := range i.reviews {
+		if wanted[review.MovieID] {
+			reviews[review.MovieID] = append(reviews[review.MovieID], review)
+		}
+	}
+	return reviews, nil
+}
+
+func (i *inmemoryRatingRepository) AddReview(ctx context.Context, review model.Review) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, err := i.movies.GetMovie(ctx, review.MovieID); err != nil {
+		return err
+	}
+
+	review.ID = i.nextReviewID
+	review.CreatedAt = time.Now().UTC()
+	i.nextReviewID++
+	i.reviews = append(i.reviews, review)
+
+	return nil
+}
+
+func (i *inmemoryRatingRepository) DeleteReview(ctx context.Context, movieID int, reviewID int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for k, review := range i.reviews {
+		if review.ID == reviewID && review.MovieID == movieID {
+			i.reviews = append(i.reviews[:k:k], i.reviews[k+1:]...)
+			return nil
+		}
+	}
+
+	return ErrReviewNotFound
+}
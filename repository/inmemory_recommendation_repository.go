@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+type inmemoryRecommendationRepository struct {
+	mu         sync.RWMutex
+	neighbours map[int][]model.Neighbour
+	// computed holds the movies the last refresh computed the neighbours of.
+	computed map[int]bool
+	logger   *slog.Logger
+}
+
+// NewInMemoryRecommendationRepository keeps the precomputed neighbours in memory.
+// Deleting a movie from movies deletes the neighbours it is part of.
+func NewInMemoryRecommendationRepository(movies *inmemoryMovieRepository, logger *slog.Logger) *inmemoryRecommendationRepository {
+	i := &inmemoryRecommendationRepository{
+		neighbours: make(map[int][]model.Neighbour),
+		computed:   make(map[int]bool),
+		logger:     logger,
+	}
+	movies.registerOnDelete(i.deleteMovieNeighbours)
+	// The neighbours of a merged movie are recomputed by the next refresh, until then
+	// they are dropped like the ones of a deleted movie.
+	movies.registerOnMerge(func(targetID int, sourceID int) {
+		i.deleteMovieNeighbours([]int{sourceID})
+	})
+	return i
+}
+
+func (i *inmemoryRecommendationRepository) deleteMovieNeighbours(movieIDs []int) {
+	deleted := make(map[int]bool, len(movieIDs))
+	for _, id := range movieIDs {
+		deleted[id] = true
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for movieID, neighbours := range i.neighbours {
+		if deleted[movieID] {
+			delete(i.neighbours, movieID)
+			delete(i.computed, movieID)
+			continue
+		}
+
+		kept := neighbours[:0:0]
+		for _, neighbour := range neighbours {
+			if !deleted[neighbour.NeighbourID] {
+				kept = append(kept, neighbour)
+			}
+		}
+		i.neighbours[movieID] = kept
+	}
+}
+
+func (i *inmemoryRecommendationRepository) GetNeighbours(ctx context.Context, movieID int, limit int) ([]model.Neighbour, bool, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	neighbours := i.neighbours[movieID]
+	if len(neighbours) > limit {
+		neighbours = neighbours[:limit]
+	}
+	return append([]model.Neighbour{}, neighbours...), i.computed[movieID], nil
+}
+
+func (i *inmemoryRecommendationRepository) ReplaceNeighbours(ctx context.Context, movieIDs []int, neighbours []model.Neighbour) error {
+	computed := make(map[int]bool, len(movieIDs))
+	for _, id := range movieIDs {
+		computed[id] = true
+	}
+
+	byMovie := make(map[int][]model.Neighbour)
+	for _, neighbour := range neighbours {
+		byMovie[neighbour.MovieID] = append(byMovie[neighbour.MovieID], neighbour)
+	}
+	for _, list := range byMovie {
+		sortNeighbours(list)
+	}
+
+	i.mu.Lock()
+	i.neighbours = byMovie
+	i.computed = computed
+	i.mu.Unlock()
+
+	i.logger.Debug("neighbours replaced", "movies", len(byMovie), "neighbours", len(neighbours))
+	return nil
+}
+
+// sortNeighbours orders neighbours like the PostgreSQL repository does, most similar
+// first and by id among equals.
+func sortNeighbours(neighbours []model.Neighbour) {
+	sort.Slice(neighbours, func(a, b int) bool {
+		if neighbours[a].Similarity != neighbours[b].Similarity {
+			return neighbours[a].Similarity > neighbours[b].Similarity
+		}
+		return neighbours[a].NeighbourID < neighbours[b].NeighbourID
+	})
+}
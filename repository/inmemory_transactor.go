@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+type inmemoryTxKey struct{}
+
+type inmemoryTransactor struct {
+	mu sync.Mutex
+}
+
+// NewInMemoryTransactor runs one transaction at a time, so that a transaction sees no
+// writes of another one between its reads and its writes. The in-memory repositories
+// apply every write at once, so a failing fn does not roll back the writes before
+// the failure.
+func NewInMemoryTransactor() *inmemoryTransactor {
+	return &inmemoryTransactor{}
+}
+
+// InTx joins the transaction of ctx, if any, like the PostgreSQL transactor does.
+func (i *inmemoryTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(inmemoryTxKey{}) != nil {
+		return fn(ctx)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return fn(context.WithValue(ctx, inmemoryTxKey{}, i))
+}
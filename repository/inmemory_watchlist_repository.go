@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrWatchlistItemNotFound = errors.New("FromRepository - movie is not on the watchlist")
+	ErrWatchlistOrderInvalid = errors.New("FromRepository - order does not match the watchlist")
+	ErrWatchedEntryNotFound  = errors.New("FromRepository - watched entry not found")
+)
+
+type inmemoryWatchlistRepository struct {
+	mu          sync.Mutex
+	watchlists  map[int][]model.WatchlistItem
+	watched     []model.WatchedEntry
+	nextEntryID int
+	movies      *inmemoryMovieRepository
+	logger      *slog.Logger
+}
+
+// NewInMemoryWatchlistRepository reads the listed movies from movies. Deleting a movie
+// from movies takes it off every watchlist and watched history.
+func NewInMemoryWatchlistRepository(movies *inmemoryMovieRepository, logger *slog.Logger) *inmemoryWatchlistRepository {
+	i := &inmemoryWatchlistRepository{
+		watchlists:  make(map[int][]model.WatchlistItem),
+		nextEntryID: 1,
+		movies:      movies,
+		logger:      logger,
+	}
+	movies.registerOnDelete(i.deleteMovies)
+	movies.registerOnMerge(i.mergeMovies)
+	return i
+}
+
+func (i *inmemoryWatchlistRepository) deleteMovies(movieIDs []int) {
+	deleted := make(map[int]bool, len(movieIDs))
+	for _, id := range movieIDs {
+		deleted[id] = true
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for userID, items := range i.watchlists {
+		kept := items[:0:0]
+		for _, item := range items {
+			if !deleted[item.MovieID] {
+				kept = append(kept, item)
+			}
+		}
+		i.watchlists[userID] = kept
+	}
+
+	watched := i.watched[:0:0]
+	for _, entry := range i.watched {
+		if !deleted[entry.MovieID] {
+			watched = append(watched, entry)
+		}
+	}
+	i.watched = watched
+}
+
+// mergeMovies points the watchlists and watched histories at the target movie instead
+// of the source one. A watchlist holding both keeps the target.
+func (i *inmemoryWatchlistRepository) mergeMovies(targetID int, sourceID int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for userID, items := range i.watchlists {
+		listed := false
+		for _, item := range items {
+			if item.MovieID == targetID {
+				listed = true
+			}
+		}
+
+		if listed {
+			i.remove(userID, sourceID)
+			continue
+		}
+		for k := range items {
+			if items[k].MovieID == sourceID {
+				items[k].MovieID = targetID
+			}
+		}
+	}
+
+	for k := range i.watched {
+		if i.watched[k].MovieID == sourceID {
+			i.watched[k].MovieID = targetID
+		}
+	}
+}
+
+func (i *inmemoryWatchlistRepository) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	i.mu.Lock()
+	items := append([]model.WatchlistItem{}, i.watchlists[userID]...)
+	i.mu.Unlock()
+
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Position < items[b].Position })
+
+	withMovies := items[:0]
+	for _, item := range items {
+		movie, ok, err := i.movie(ctx, item.MovieID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			item.Movie = &movie
+			withMovies = append(withMovies, item)
+		}
+	}
+	return withMovies, nil
+}
+
+func (i *inmemoryWatchlistRepository) AddToWatchlist(ctx context.Context, userID int, movieID int) error {
+	if _, err := i.movies.GetMovie(ctx, movieID); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	position := 0
+	for _, item := range i.watchlists[userID] {
+		if item.MovieID == movieID {
+			return nil
+		}
+		if item.Position >= position {
+			position = item.Position + 1
+		}
+	}
+
+	i.watchlists[userID] = append(i.watchlists[userID], model.WatchlistItem{
+		UserID:   userID,
+		MovieID:  movieID,
+		Position: position,
+		AddedAt:  time.Now().UTC(),
+	})
+	return nil
+}
+
+func (i *inmemoryWatchlistRepository) RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.remove(userID, movieID) {
+		return ErrWatchlistItemNotFound
+	}
+	return nil
+}
+
+// remove must be called with i.mu held.
+func (i *inmemoryWatchlistRepository) remove(userID int, movieID int) bool {
+	items := i.watchlists[userID]
+	for k, item := range items {
+		if item.MovieID == movieID {
+			i.watchlists[userID] = append(items[:k:k], items[k+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (i *inmemoryWatchlistRepository) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	items := i.watchlists[userID]
+	if len(movieIDs) != len(items) {
+		return ErrWatchlistOrderInvalid
+	}
+
+	positions := make(map[int]int, len(movieIDs))
+	for position, movieID := range movieIDs {
+		positions[movieID] = position
+	}
+
+	reordered := make([]model.WatchlistItem, len(items))
+	for k, item := range items {
+		position, ok := positions[item.MovieID]
+		if !ok {
+			return ErrWatchlistOrderInvalid
+		}
+		item.Position = position
+		reordered[k] = item
+	}
+
+	i.watchlists[userID] = reordered
+	return nil
+}
+
+func (i *inmemoryWatchlistRepository) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	i.mu.Lock()
+	entries := make([]model.WatchedEntry, 0)
+	for _, entry := range i.watched {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	i.mu.Unlock()
+
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].WatchedAt.After(entries[b].WatchedAt) })
+
+	withMovies := entries[:0]
+	for _, entry := range entries {
+		movie, ok, err := i.movie(ctx, entry.MovieID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			entry.Movie = &movie
+			withMovies = append(withMovies, entry)
+		}
+	}
+	return withMovies, nil
+}
+
+func (i *inmemoryWatchlistRepository) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	if _, err := i.movies.GetMovie(ctx, entry.MovieID); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	entry.ID = i.nextEntryID
+	entry.Movie = nil
+	i.nextEntryID++
+	i.watched = append(i.watched, entry)
+	i.remove(entry.UserID, entry.MovieID)
+
+	return nil
+}
+
+func (i *inmemoryWatchlistRepository) DeleteWatched(ctx context.Context, userID int, entryID int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for k, entry := range i.watched {
+		if entry.ID == entryID && entry.UserID == userID {
+			i.watched = append(i.watched[:k:k], i.watched[k+1:]...)
+			return nil
+		}
+	}
+
+	return ErrWatchedEntryNotFound
+}
+
+// movie reads a listed movie, which is missing when it was deleted after the list
+// was read and before its cleanup ran.
+func (i *inmemoryWatchlistRepository) movie(ctx context.Context, movieID int) (model.Movie, bool, error) {
+	movie, err := i.movies.GetMovie(ctx, movieID)
+	if errors.Is(err, ErrMovieNotFound) {
+		return model.Movie{}, false, nil
+	}
+	return movie, err == nil, err
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("FromRepository - webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("FromRepository - webhook delivery not found")
+)
+
+type inmemoryWebhookRepository struct {
+	mu             sync.Mutex
+	webhooks       []model.Webhook
+	deliveries     []model.WebhookDelivery
+	nextID         int
+	nextDeliveryID int64
+	logger         *slog.Logger
+	now            func() time.Time
+}
+
+func NewInMemoryWebhookRepository(logger *slog.Logger) *inmemoryWebhookRepository {
+	return &inmemoryWebhookRepository{nextID: 1, nextDeliveryID: 1, logger: logger, now: time.Now}
+}
+
+func (i *inmemoryWebhookRepository) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return append([]model.Webhook{}, i.webhooks...), nil
+}
+
+func (i *inmemoryWebhookRepository) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	k := i.indexOf(id)
+	if k < 0 {
+		return model.Webhook{}, ErrWebhookNotFound
+	}
+	return i.webhooks[k], nil
+}
+
+func (i *inmemoryWebhookRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	webhook.ID = i.nextID
+	i.nextID++
+	webhook.CreatedAt = i.now().UTC()
+	webhook.UpdatedAt = webhook.CreatedAt
+	i.webhooks = append(i.webhooks, webhook)
+	return webhook, nil
+}
+
+func (i *inmemoryWebhookRepository) UpdateWebhook(ctx context.Context, id int, webhook model.Webhook) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	k := i.indexOf(id)
+	if k < 0 {
+		return ErrWebhookNotFound
+	}
+
+	stored := &i.webhooks[k]
+	stored.URL = webhook.URL
+	stored.Events = webhook.Events
+	stored.Active = webhook.Active
+	stored.UpdatedAt = i.now().UTC()
+	return nil
+}
+
+func (i *inmemoryWebhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	k := i.indexOf(id)
+	if k < 0 {
+		return ErrWebhookNotFound
+	}
+	i.webhooks = append(i.webhooks[:k], i.webhooks[k+1:]...)
+
+	deliveries := i.deliveries[:0:0]
+	for _, delivery := range i.deliveries {
+		if delivery.WebhookID != id {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	i.deliveries = deliveries
+	return nil
+}
+
+func (i *inmemoryWebhookRepository) EnqueueDeliveries(ctx context.Context, event model.Event, payload []byte, now time.Time) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	enqueued := 0
+	for _, webhook := range i.webhooks {
+		if !webhook.Subscribed(event.Type) || i.hasDelivery(webhook.ID, event.ID) {
+			continue
+		}
+
+		i.deliveries = append(i.deliveries, model.WebhookDelivery{
+			ID:            i.nextDeliveryID,
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       append([]byte{}, payload...),
+			Status:        model.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     i.now().UTC(),
+			UpdatedAt:     i.now().UTC(),
+		})
+		i.nextDeliveryID++
+		enqueued++
+	}
+	return enqueued, nil
+}
+
+func (i *inmemoryWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.DueDelivery, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	due := make([]*model.WebhookDelivery, 0)
+	for k := range i.deliveries {
+		delivery := &i.deliveries[k]
+		if delivery.Status == model.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.SliceStable(due, func(a, b int) bool {
+		return due[a].NextAttemptAt.Before(due[b].NextAttemptAt)
+	})
+	if limit < len(due) {
+		due = due[:limit]
+	}
+
+	claimed := make([]model.DueDelivery, 0, len(due))
+	for _, delivery := range due {
+		delivery.NextAttemptAt = now.Add(lease)
+		webhook := i.webhooks[i.indexOf(delivery.WebhookID)]
+		claimed = append(claimed, model.DueDelivery{Delivery: *delivery, URL: webhook.URL, Secret: webhook.Secret})
+	}
+	return claimed, nil
+}
+
+func (i *inmemoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	stored := i.delivery(delivery.WebhookID, delivery.ID)
+	if stored == nil {
+		return ErrWebhookDeliveryNotFound
+	}
+
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.LastStatusCode = delivery.LastStatusCode
+	stored.LastError = delivery.LastError
+	stored.UpdatedAt = i.now().UTC()
+	return nil
+}
+
+func (i *inmemoryWebhookRepository) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.indexOf(webhookID) < 0 {
+		return []model.WebhookDelivery{}, ErrWebhookNotFound
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0)
+	for k := len(i.deliveries) - 1; k >= 0 && len(deliveries) < limit; k-- {
+		delivery := i.deliveries[k]
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (i *inmemoryWebhookRepository) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64, now time.Time) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delivery := i.delivery(webhookID, deliveryID)
+	if delivery == nil {
+		return ErrWebhookDeliveryNotFound
+	}
+
+	delivery.Status = model.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = i.now().UTC()
+	return nil
+}
+
+func (i *inmemoryWebhookRepository) indexOf(id int) int {
+	for k, webhook := range i.webhooks {
+		if webhook.ID == id {
+			return k
+		}
+	}
+	return -1
+}
+
+func (i *inmemoryWebhookRepository) hasDelivery(webhookID int, eventID int64) bool {
+	for _, delivery := range i.deliveries {
+		if delivery.WebhookID == webhookID && delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *inmemoryWebhookRepository) delivery(webhookID int, deliveryID int64) *model.WebhookDelivery {
+	for k := range i.deliveries {
+		if i.deliveries[k].ID == deliveryID && i.deliveries[k].WebhookID == webhookID {
+			return &i.deliveries[k]
+		}
+	}
+	return nil
+}
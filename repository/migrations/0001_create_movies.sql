@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS movies (
+    id           SERIAL PRIMARY KEY,
+    title        TEXT             NOT NULL,
+    release_year INTEGER          NOT NULL DEFAULT 0,
+    score        DOUBLE PRECISION NOT NULL DEFAULT 0
+);
@@ -0,0 +1,9 @@
+ALTER TABLE movies
+    ADD COLUMN IF NOT EXISTS runtime_minutes   INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS synopsis          TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS original_language TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS country           TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS age_rating        TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS poster_url        TEXT    NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS movies_release_year_idx ON movies (release_year);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS people (
+    id         SERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    birth_year INTEGER     NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS credits (
+    id            SERIAL PRIMARY KEY,
+    movie_id      INTEGER NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    person_id     INTEGER NOT NULL REFERENCES people (id) ON DELETE CASCADE,
+    role          TEXT    NOT NULL,
+    character     TEXT    NOT NULL DEFAULT '',
+    billing_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS credits_movie_id_idx ON credits (movie_id, billing_order);
+CREATE INDEX IF NOT EXISTS credits_person_id_role_idx ON credits (person_id, role);
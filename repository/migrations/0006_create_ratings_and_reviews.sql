@@ -0,0 +1,28 @@
+-- Once a movie is rated, its score is the weighted score of the ratings, pulled
+-- towards model.ScorePrior (5.5) as if it had model.ScorePriorWeight (10) more
+-- ratings. The editorial score it was given is kept apart, so that it comes back when
+-- the last rating is deleted. No movie is rated yet, so it is the current score.
+ALTER TABLE movies
+    ADD COLUMN IF NOT EXISTS rating_count    INTEGER          NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_mean     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS editorial_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+UPDATE movies SET editorial_score = score;
+
+CREATE TABLE IF NOT EXISTS ratings (
+    movie_id   INTEGER     NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    user_id    INTEGER     NOT NULL,
+    value      INTEGER     NOT NULL CHECK (value BETWEEN 1 AND 10),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (movie_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id         SERIAL PRIMARY KEY,
+    movie_id   INTEGER     NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    user_id    INTEGER     NOT NULL,
+    body       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS reviews_movie_id_idx ON reviews (movie_id, created_at);
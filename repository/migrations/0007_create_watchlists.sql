@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS watchlist_items (
+    user_id  INTEGER     NOT NULL,
+    movie_id INTEGER     NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    position INTEGER     NOT NULL,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE TABLE IF NOT EXISTS watched_entries (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL,
+    movie_id   INTEGER     NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    watched_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS watchlist_items_movie_id_idx ON watchlist_items (movie_id);
+CREATE INDEX IF NOT EXISTS watched_entries_user_id_idx ON watched_entries (user_id, watched_at DESC);
+CREATE INDEX IF NOT EXISTS watched_entries_movie_id_idx ON watched_entries (movie_id);
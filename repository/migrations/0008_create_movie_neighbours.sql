@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS movie_neighbours (
+    movie_id     INTEGER          NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    neighbour_id INTEGER          NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    similarity   DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (movie_id, neighbour_id)
+);
+
+CREATE INDEX IF NOT EXISTS movie_neighbours_neighbour_id_idx ON movie_neighbours (neighbour_id);
+
+-- The movies the last refresh computed the neighbours of, so that a movie without any
+-- neighbours is told apart from one created since.
+CREATE TABLE IF NOT EXISTS movie_neighbours_computed (
+    movie_id INTEGER PRIMARY KEY REFERENCES movies (id) ON DELETE CASCADE
+);
+
+-- Serves the best scored movies recommended to users without enough ratings.
+CREATE INDEX IF NOT EXISTS movies_score_idx ON movies (score DESC, id);
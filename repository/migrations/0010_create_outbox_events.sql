@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id          BIGSERIAL PRIMARY KEY,
+    type        TEXT        NOT NULL,
+    movie_id    INTEGER     NOT NULL,
+    payload     JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- A relay publishing the event holds it until then, without a transaction.
+    claimed_until TIMESTAMPTZ
+);
@@ -5,6 +5,7 @@
 package repository
 
 import (
+	context "context"
 	reflect "reflect"
 
 	model "github.com/dilaragorum/movie-go/model"
@@ -35,87 +36,190 @@ func (m *MockIMovieRepository) EXPECT() *MockIMovieRepositoryMockRecorder {
 }
 
 // CreateMovie mocks base method.
-func (m *MockIMovieRepository) CreateMovie(movie model.Movie) error {
+func (m *MockIMovieRepository) CreateMovie(ctx context.Context, movie model.Movie) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMovie", movie)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateMovie", ctx, movie)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateMovie indicates an expected call of CreateMovie.
-func (mr *MockIMovieRepositoryMockRecorder) CreateMovie(movie interface{}) *gomock.Call {
+func (mr *MockIMovieRepositoryMockRecorder) CreateMovie(ctx, movie interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockIMovieRepository)(nil).CreateMovie), movie)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockIMovieRepository)(nil).CreateMovie), ctx, movie)
 }
 
 // DeleteAllMovies mocks base method.
-func (m *MockIMovieRepository) DeleteAllMovies() error {
+func (m *MockIMovieRepository) DeleteAllMovies(ctx context.Context) ([]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAllMovies")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteAllMovies", ctx)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteAllMovies indicates an expected call of DeleteAllMovies.
-func (mr *MockIMovieRepositoryMockRecorder) DeleteAllMovies() *gomock.Call {
+func (mr *MockIMovieRepositoryMockRecorder) DeleteAllMovies(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllMovies", reflect.TypeOf((*MockIMovieRepository)(nil).DeleteAllMovies))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllMovies", reflect.TypeOf((*MockIMovieRepository)(nil).DeleteAllMovies), ctx)
 }
 
 // DeleteMovie mocks base method.
-func (m *MockIMovieRepository) DeleteMovie(id int) error {
+func (m *MockIMovieRepository) DeleteMovie(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMovie", id)
+	ret := m.ctrl.Call(m, "DeleteMovie", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteMovie indicates an expected call of DeleteMovie.
-func (mr *MockIMovieRepositoryMockRecorder) DeleteMovie(id interface{}) *gomock.Call {
+func (mr *MockIMovieRepositoryMockRecorder) DeleteMovie(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockIMovieRepository)(nil).DeleteMovie), ctx, id)
+}
+
+// GetBestScoredMovies mocks base method.
+func (m *MockIMovieRepository) GetBestScoredMovies(ctx context.Context, limit int, excludedIDs []int) ([]model.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBestScoredMovies", ctx, limit, excludedIDs)
+	ret0, _ := ret[0].([]model.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBestScoredMovies indicates an expected call of GetBestScoredMovies.
+func (mr *MockIMovieRepositoryMockRecorder) GetBestScoredMovies(ctx, limit, excludedIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockIMovieRepository)(nil).DeleteMovie), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBestScoredMovies", reflect.TypeOf((*MockIMovieRepository)(nil).GetBestScoredMovies), ctx, limit, excludedIDs)
 }
 
 // GetMovie mocks base method.
-func (m *MockIMovieRepository) GetMovie(id int) (model.Movie, error) {
+func (m *MockIMovieRepository) GetMovie(ctx context.Context, id int) (model.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovie", id)
+	ret := m.ctrl.Call(m, "GetMovie", ctx, id)
 	ret0, _ := ret[0].(model.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovie indicates an expected call of GetMovie.
-func (mr *MockIMovieRepositoryMockRecorder) GetMovie(id interface{}) *gomock.Call {
+func (mr *MockIMovieRepositoryMockRecorder) GetMovie(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovie", reflect.TypeOf((*MockIMovieRepository)(nil).GetMovie), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovie", reflect.TypeOf((*MockIMovieRepository)(nil).GetMovie), ctx, id)
+}
+
+// GetMovieStats mocks base method.
+func (m *MockIMovieRepository) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovieStats", ctx, filter, topN)
+	ret0, _ := ret[0].(model.MovieStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovieStats indicates an expected call of GetMovieStats.
+func (mr *MockIMovieRepositoryMockRecorder) GetMovieStats(ctx, filter, topN interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieStats", reflect.TypeOf((*MockIMovieRepository)(nil).GetMovieStats), ctx, filter, topN)
 }
 
 // GetMovies mocks base method.
-func (m *MockIMovieRepository) GetMovies() ([]model.Movie, error) {
+func (m *MockIMovieRepository) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovies")
+	ret := m.ctrl.Call(m, "GetMovies", ctx, filter)
 	ret0, _ := ret[0].([]model.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovies indicates an expected call of GetMovies.
-func (mr *MockIMovieRepositoryMockRecorder) GetMovies() *gomock.Call {
+func (mr *MockIMovieRepositoryMockRecorder) GetMovies(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockIMovieRepository)(nil).GetMovies), ctx, filter)
+}
+
+// GetMoviesByID mocks base method.
+func (m *MockIMovieRepository) GetMoviesByID(ctx context.Context, ids []int) ([]model.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMoviesByID", ctx, ids)
+	ret0, _ := ret[0].([]model.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMoviesByID indicates an expected call of GetMoviesByID.
+func (mr *MockIMovieRepositoryMockRecorder) GetMoviesByID(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoviesByID", reflect.TypeOf((*MockIMovieRepository)(nil).GetMoviesByID), ctx, ids)
+}
+
+// LockMovieTitle mocks base method.
+func (m *MockIMovieRepository) LockMovieTitle(ctx context.Context, titleKey string, releaseYear int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockMovieTitle", ctx, titleKey, releaseYear)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockMovieTitle indicates an expected call of LockMovieTitle.
+func (mr *MockIMovieRepositoryMockRecorder) LockMovieTitle(ctx, titleKey, releaseYear interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockMovieTitle", reflect.TypeOf((*MockIMovieRepository)(nil).LockMovieTitle), ctx, titleKey, releaseYear)
+}
+
+// MergeMovies mocks base method.
+func (m *MockIMovieRepository) MergeMovies(ctx context.Context, targetID, sourceID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeMovies", ctx, targetID, sourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeMovies indicates an expected call of MergeMovies.
+func (mr *MockIMovieRepositoryMockRecorder) MergeMovies(ctx, targetID, sourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeMovies", reflect.TypeOf((*MockIMovieRepository)(nil).MergeMovies), ctx, targetID, sourceID)
+}
+
+// Ping mocks base method.
+func (m *MockIMovieRepository) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockIMovieRepositoryMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockIMovieRepository)(nil).Ping), ctx)
+}
+
+// StreamMovies mocks base method.
+func (m *MockIMovieRepository) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(model.Movie) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamMovies", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamMovies indicates an expected call of StreamMovies.
+func (mr *MockIMovieRepositoryMockRecorder) StreamMovies(ctx, filter, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockIMovieRepository)(nil).GetMovies))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamMovies", reflect.TypeOf((*MockIMovieRepository)(nil).StreamMovies), ctx, filter, fn)
 }
 
 // UpdateMovie mocks base method.
-func (m *MockIMovieRepository) UpdateMovie(id int, movie model.Movie) error {
+func (m *MockIMovieRepository) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateMovie", id, movie)
+	ret := m.ctrl.Call(m, "UpdateMovie", ctx, id, movie)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateMovie indicates an expected call of UpdateMovie.
-func (mr *MockIMovieRepositoryMockRecorder) UpdateMovie(id, movie interface{}) *gomock.Call {
+func (mr *MockIMovieRepositoryMockRecorder) UpdateMovie(ctx, id, movie interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockIMovieRepository)(nil).UpdateMovie), id, movie)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockIMovieRepository)(nil).UpdateMovie), ctx, id, movie)
 }
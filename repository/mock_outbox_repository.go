@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/outbox_repository_interface.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIOutboxRepository is a mock of IOutboxRepository interface.
+type MockIOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxRepositoryMockRecorder
+}
+
+// MockIOutboxRepositoryMockRecorder is the mock recorder for MockIOutboxRepository.
+type MockIOutboxRepositoryMockRecorder struct {
+	mock *MockIOutboxRepository
+}
+
+// NewMockIOutboxRepository creates a new mock instance.
+func NewMockIOutboxRepository(ctrl *gomock.Controller) *MockIOutboxRepository {
+	mock := &MockIOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockIOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxRepository) EXPECT() *MockIOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendEvents mocks base method.
+func (m *MockIOutboxRepository) AppendEvents(ctx context.Context, events []model.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendEvents", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendEvents indicates an expected call of AppendEvents.
+func (mr *MockIOutboxRepositoryMockRecorder) AppendEvents(ctx, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvents", reflect.TypeOf((*MockIOutboxRepository)(nil).AppendEvents), ctx, events)
+}
+
+// ClaimPendingEvents mocks base method.
+func (m *MockIOutboxRepository) ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingEvents", ctx, limit, lease)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPendingEvents indicates an expected call of ClaimPendingEvents.
+func (mr *MockIOutboxRepositoryMockRecorder) ClaimPendingEvents(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingEvents", reflect.TypeOf((*MockIOutboxRepository)(nil).ClaimPendingEvents), ctx, limit, lease)
+}
+
+// DeleteEvents mocks base method.
+func (m *MockIOutboxRepository) DeleteEvents(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvents", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvents indicates an expected call of DeleteEvents.
+func (mr *MockIOutboxRepositoryMockRecorder) DeleteEvents(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvents", reflect.TypeOf((*MockIOutboxRepository)(nil).DeleteEvents), ctx, ids)
+}
+
+// ReleaseEvents mocks base method.
+func (m *MockIOutboxRepository) ReleaseEvents(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseEvents", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseEvents indicates an expected call of ReleaseEvents.
+func (mr *MockIOutboxRepositoryMockRecorder) ReleaseEvents(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseEvents", reflect.TypeOf((*MockIOutboxRepository)(nil).ReleaseEvents), ctx, ids)
+}
@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/person_repository_interface.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIPersonRepository is a mock of IPersonRepository interface.
+type MockIPersonRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPersonRepositoryMockRecorder
+}
+
+// MockIPersonRepositoryMockRecorder is the mock recorder for MockIPersonRepository.
+type MockIPersonRepositoryMockRecorder struct {
+	mock *MockIPersonRepository
+}
+
+// NewMockIPersonRepository creates a new mock instance.
+func NewMockIPersonRepository(ctrl *gomock.Controller) *MockIPersonRepository {
+	mock := &MockIPersonRepository{ctrl: ctrl}
+	mock.recorder = &MockIPersonRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPersonRepository) EXPECT() *MockIPersonRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddCredit mocks base method.
+func (m *MockIPersonRepository) AddCredit(ctx context.Context, credit model.Credit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCredit", ctx, credit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCredit indicates an expected call of AddCredit.
+func (mr *MockIPersonRepositoryMockRecorder) AddCredit(ctx, credit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCredit", reflect.TypeOf((*MockIPersonRepository)(nil).AddCredit), ctx, credit)
+}
+
+// CreatePerson mocks base method.
+func (m *MockIPersonRepository) CreatePerson(ctx context.Context, person model.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePerson", ctx, person)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePerson indicates an expected call of CreatePerson.
+func (mr *MockIPersonRepositoryMockRecorder) CreatePerson(ctx, person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePerson", reflect.TypeOf((*MockIPersonRepository)(nil).CreatePerson), ctx, person)
+}
+
+// DeleteCredit mocks base method.
+func (m *MockIPersonRepository) DeleteCredit(ctx context.Context, movieID, creditID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCredit", ctx, movieID, creditID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCredit indicates an expected call of DeleteCredit.
+func (mr *MockIPersonRepositoryMockRecorder) DeleteCredit(ctx, movieID, creditID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCredit", reflect.TypeOf((*MockIPersonRepository)(nil).DeleteCredit), ctx, movieID, creditID)
+}
+
+// DeletePerson mocks base method.
+func (m *MockIPersonRepository) DeletePerson(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePerson", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePerson indicates an expected call of DeletePerson.
+func (mr *MockIPersonRepositoryMockRecorder) DeletePerson(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePerson", reflect.TypeOf((*MockIPersonRepository)(nil).DeletePerson), ctx, id)
+}
+
+// GetCreditsByMovies mocks base method.
+func (m *MockIPersonRepository) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreditsByMovies", ctx, movieIDs)
+	ret0, _ := ret[0].(map[int][]model.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreditsByMovies indicates an expected call of GetCreditsByMovies.
+func (mr *MockIPersonRepositoryMockRecorder) GetCreditsByMovies(ctx, movieIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditsByMovies", reflect.TypeOf((*MockIPersonRepository)(nil).GetCreditsByMovies), ctx, movieIDs)
+}
+
+// GetMovieCredits mocks base method.
+func (m *MockIPersonRepository) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovieCredits", ctx, movieID)
+	ret0, _ := ret[0].([]model.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovieCredits indicates an expected call of GetMovieCredits.
+func (mr *MockIPersonRepositoryMockRecorder) GetMovieCredits(ctx, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieCredits", reflect.TypeOf((*MockIPersonRepository)(nil).GetMovieCredits), ctx, movieID)
+}
+
+// GetPeople mocks base method.
+func (m *MockIPersonRepository) GetPeople(ctx context.Context) ([]model.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeople", ctx)
+	ret0, _ := ret[0].([]model.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeople indicates an expected call of GetPeople.
+func (mr *MockIPersonRepositoryMockRecorder) GetPeople(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeople", reflect.TypeOf((*MockIPersonRepository)(nil).GetPeople), ctx)
+}
+
+// GetPerson mocks base method.
+func (m *MockIPersonRepository) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPerson", ctx, id)
+	ret0, _ := ret[0].(model.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPerson indicates an expected call of GetPerson.
+func (mr *MockIPersonRepositoryMockRecorder) GetPerson(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPerson", reflect.TypeOf((*MockIPersonRepository)(nil).GetPerson), ctx, id)
+}
+
+// GetPersonMovies mocks base method.
+func (m *MockIPersonRepository) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonMovies", ctx, personID, role)
+	ret0, _ := ret[0].([]model.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonMovies indicates an expected call of GetPersonMovies.
+func (mr *MockIPersonRepositoryMockRecorder) GetPersonMovies(ctx, personID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonMovies", reflect.TypeOf((*MockIPersonRepository)(nil).GetPersonMovies), ctx, personID, role)
+}
+
+// UpdatePerson mocks base method.
+func (m *MockIPersonRepository) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePerson", ctx, id, person)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePerson indicates an expected call of UpdatePerson.
+func (mr *MockIPersonRepositoryMockRecorder) UpdatePerson(ctx, id, person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePerson", reflect.TypeOf((*MockIPersonRepository)(nil).UpdatePerson), ctx, id, person)
+}
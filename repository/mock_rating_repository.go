@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/rating_repository_interface.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRatingRepository is a mock of IRatingRepository interface.
+type MockIRatingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRatingRepositoryMockRecorder
+}
+
+// MockIRatingRepositoryMockRecorder is the mock recorder for MockIRatingRepository.
+type MockIRatingRepositoryMockRecorder struct {
+	mock *MockIRatingRepository
+}
+
+// NewMockIRatingRepository creates a new mock instance.
+func NewMockIRatingRepository(ctrl *gomock.Controller) *MockIRatingRepository {
+	mock := &MockIRatingRepository{ctrl: ctrl}
+	mock.recorder = &MockIRatingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRatingRepository) EXPECT() *MockIRatingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddReview mocks base method.
+func (m *MockIRatingRepository) AddReview(ctx context.Context, review model.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReview", ctx, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReview indicates an expected call of AddReview.
+func (mr *MockIRatingRepositoryMockRecorder) AddReview(ctx, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReview", reflect.TypeOf((*MockIRatingRepository)(nil).AddReview), ctx, review)
+}
+
+// DeleteRating mocks base method.
+func (m *MockIRatingRepository) DeleteRating(ctx context.Context, movieID, userID int) (model.RatingSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRating", ctx, movieID, userID)
+	ret0, _ := ret[0].(model.RatingSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRating indicates an expected call of DeleteRating.
+func (mr *MockIRatingRepositoryMockRecorder) DeleteRating(ctx, movieID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRating", reflect.TypeOf((*MockIRatingRepository)(nil).DeleteRating), ctx, movieID, userID)
+}
+
+// DeleteReview mocks base method.
+func (m *MockIRatingRepository) DeleteReview(ctx context.Context, movieID, reviewID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", ctx, movieID, reviewID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockIRatingRepositoryMockRecorder) DeleteReview(ctx, movieID, reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockIRatingRepository)(nil).DeleteReview), ctx, movieID, reviewID)
+}
+
+// GetRatings mocks base method.
+func (m *MockIRatingRepository) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatings", ctx, movieID)
+	ret0, _ := ret[0].([]model.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatings indicates an expected call of GetRatings.
+func (mr *MockIRatingRepositoryMockRecorder) GetRatings(ctx, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatings", reflect.TypeOf((*MockIRatingRepository)(nil).GetRatings), ctx, movieID)
+}
+
+// GetReviews mocks base method.
+func (m *MockIRatingRepository) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx, movieID)
+	ret0, _ := ret[0].([]model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockIRatingRepositoryMockRecorder) GetReviews(ctx, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockIRatingRepository)(nil).GetReviews), ctx, movieID)
+}
+
+// GetReviewsByMovies mocks base method.
+func (m *MockIRatingRepository) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByMovies", ctx, movieIDs)
+	ret0, _ := ret[0].(map[int][]model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByMovies indicates an expected call of GetReviewsByMovies.
+func (mr *MockIRatingRepositoryMockRecorder) GetReviewsByMovies(ctx, movieIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByMovies", reflect.TypeOf((*MockIRatingRepository)(nil).GetReviewsByMovies), ctx, movieIDs)
+}
+
+// GetUserRatings mocks base method.
+func (m *MockIRatingRepository) GetUserRatings(ctx context.Context, userID int) ([]model.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRatings", ctx, userID)
+	ret0, _ := ret[0].([]model.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRatings indicates an expected call of GetUserRatings.
+func (mr *MockIRatingRepositoryMockRecorder) GetUserRatings(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRatings", reflect.TypeOf((*MockIRatingRepository)(nil).GetUserRatings), ctx, userID)
+}
+
+// RateMovie mocks base method.
+func (m *MockIRatingRepository) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateMovie", ctx, rating)
+	ret0, _ := ret[0].(model.RatingSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RateMovie indicates an expected call of RateMovie.
+func (mr *MockIRatingRepositoryMockRecorder) RateMovie(ctx, rating interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateMovie", reflect.TypeOf((*MockIRatingRepository)(nil).RateMovie), ctx, rating)
+}
+
+// StreamRatings mocks base method.
+func (m *MockIRatingRepository) StreamRatings(ctx context.Context, fn func(model.Rating) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamRatings", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamRatings indicates an expected call of StreamRatings.
+func (mr *MockIRatingRepositoryMockRecorder) StreamRatings(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamRatings", reflect.TypeOf((*MockIRatingRepository)(nil).StreamRatings), ctx, fn)
+}
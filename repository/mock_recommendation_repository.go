@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/recommendation_repository_interface.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRecommendationRepository is a mock of IRecommendationRepository interface.
+type MockIRecommendationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRecommendationRepositoryMockRecorder
+}
+
+// MockIRecommendationRepositoryMockRecorder is the mock recorder for MockIRecommendationRepository.
+type MockIRecommendationRepositoryMockRecorder struct {
+	mock *MockIRecommendationRepository
+}
+
+// NewMockIRecommendationRepository creates a new mock instance.
+func NewMockIRecommendationRepository(ctrl *gomock.Controller) *MockIRecommendationRepository {
+	mock := &MockIRecommendationRepository{ctrl: ctrl}
+	mock.recorder = &MockIRecommendationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRecommendationRepository) EXPECT() *MockIRecommendationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetNeighbours mocks base method.
+func (m *MockIRecommendationRepository) GetNeighbours(ctx context.Context, movieID, limit int) ([]model.Neighbour, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNeighbours", ctx, movieID, limit)
+	ret0, _ := ret[0].([]model.Neighbour)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetNeighbours indicates an expected call of GetNeighbours.
+func (mr *MockIRecommendationRepositoryMockRecorder) GetNeighbours(ctx, movieID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNeighbours", reflect.TypeOf((*MockIRecommendationRepository)(nil).GetNeighbours), ctx, movieID, limit)
+}
+
+// ReplaceNeighbours mocks base method.
+func (m *MockIRecommendationRepository) ReplaceNeighbours(ctx context.Context, movieIDs []int, neighbours []model.Neighbour) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceNeighbours", ctx, movieIDs, neighbours)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceNeighbours indicates an expected call of ReplaceNeighbours.
+func (mr *MockIRecommendationRepositoryMockRecorder) ReplaceNeighbours(ctx, movieIDs, neighbours interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceNeighbours", reflect.TypeOf((*MockIRecommendationRepository)(nil).ReplaceNeighbours), ctx, movieIDs, neighbours)
+}
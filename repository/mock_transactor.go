@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/transactor_interface.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockITransactor is a mock of ITransactor interface.
+type MockITransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransactorMockRecorder
+}
+
+// MockITransactorMockRecorder is the mock recorder for MockITransactor.
+type MockITransactorMockRecorder struct {
+	mock *MockITransactor
+}
+
+// NewMockITransactor creates a new mock instance.
+func NewMockITransactor(ctrl *gomock.Controller) *MockITransactor {
+	mock := &MockITransactor{ctrl: ctrl}
+	mock.recorder = &MockITransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransactor) EXPECT() *MockITransactorMockRecorder {
+	return m.recorder
+}
+
+// InTx mocks base method.
+func (m *MockITransactor) InTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockITransactorMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockITransactor)(nil).InTx), ctx, fn)
+}
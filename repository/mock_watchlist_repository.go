@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/watchlist_repository_interface.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWatchlistRepository is a mock of IWatchlistRepository interface.
+type MockIWatchlistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWatchlistRepositoryMockRecorder
+}
+
+// MockIWatchlistRepositoryMockRecorder is the mock recorder for MockIWatchlistRepository.
+type MockIWatchlistRepositoryMockRecorder struct {
+	mock *MockIWatchlistRepository
+}
+
+// NewMockIWatchlistRepository creates a new mock instance.
+func NewMockIWatchlistRepository(ctrl *gomock.Controller) *MockIWatchlistRepository {
+	mock := &MockIWatchlistRepository{ctrl: ctrl}
+	mock.recorder = &MockIWatchlistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWatchlistRepository) EXPECT() *MockIWatchlistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToWatchlist mocks base method.
+func (m *MockIWatchlistRepository) AddToWatchlist(ctx context.Context, userID, movieID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToWatchlist", ctx, userID, movieID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToWatchlist indicates an expected call of AddToWatchlist.
+func (mr *MockIWatchlistRepositoryMockRecorder) AddToWatchlist(ctx, userID, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToWatchlist", reflect.TypeOf((*MockIWatchlistRepository)(nil).AddToWatchlist), ctx, userID, movieID)
+}
+
+// AddWatched mocks base method.
+func (m *MockIWatchlistRepository) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWatched", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWatched indicates an expected call of AddWatched.
+func (mr *MockIWatchlistRepositoryMockRecorder) AddWatched(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWatched", reflect.TypeOf((*MockIWatchlistRepository)(nil).AddWatched), ctx, entry)
+}
+
+// DeleteWatched mocks base method.
+func (m *MockIWatchlistRepository) DeleteWatched(ctx context.Context, userID, entryID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatched", ctx, userID, entryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatched indicates an expected call of DeleteWatched.
+func (mr *MockIWatchlistRepositoryMockRecorder) DeleteWatched(ctx, userID, entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatched", reflect.TypeOf((*MockIWatchlistRepository)(nil).DeleteWatched), ctx, userID, entryID)
+}
+
+// GetWatched mocks base method.
+func (m *MockIWatchlistRepository) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatched", ctx, userID)
+	ret0, _ := ret[0].([]model.WatchedEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatched indicates an expected call of GetWatched.
+func (mr *MockIWatchlistRepositoryMockRecorder) GetWatched(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatched", reflect.TypeOf((*MockIWatchlistRepository)(nil).GetWatched), ctx, userID)
+}
+
+// GetWatchlist mocks base method.
+func (m *MockIWatchlistRepository) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlist", ctx, userID)
+	ret0, _ := ret[0].([]model.WatchlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlist indicates an expected call of GetWatchlist.
+func (mr *MockIWatchlistRepositoryMockRecorder) GetWatchlist(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlist", reflect.TypeOf((*MockIWatchlistRepository)(nil).GetWatchlist), ctx, userID)
+}
+
+// RemoveFromWatchlist mocks base method.
+func (m *MockIWatchlistRepository) RemoveFromWatchlist(ctx context.Context, userID, movieID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromWatchlist", ctx, userID, movieID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromWatchlist indicates an expected call of RemoveFromWatchlist.
+func (mr *MockIWatchlistRepositoryMockRecorder) RemoveFromWatchlist(ctx, userID, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromWatchlist", reflect.TypeOf((*MockIWatchlistRepository)(nil).RemoveFromWatchlist), ctx, userID, movieID)
+}
+
+// ReorderWatchlist mocks base method.
+func (m *MockIWatchlistRepository) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderWatchlist", ctx, userID, movieIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderWatchlist indicates an expected call of ReorderWatchlist.
+func (mr *MockIWatchlistRepositoryMockRecorder) ReorderWatchlist(ctx, userID, movieIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderWatchlist", reflect.TypeOf((*MockIWatchlistRepository)(nil).ReorderWatchlist), ctx, userID, movieIDs)
+}
@@ -0,0 +1,183 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/webhook_repository_interface.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWebhookRepository is a mock of IWebhookRepository interface.
+type MockIWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookRepositoryMockRecorder
+}
+
+// MockIWebhookRepositoryMockRecorder is the mock recorder for MockIWebhookRepository.
+type MockIWebhookRepositoryMockRecorder struct {
+	mock *MockIWebhookRepository
+}
+
+// NewMockIWebhookRepository creates a new mock instance.
+func NewMockIWebhookRepository(ctrl *gomock.Controller) *MockIWebhookRepository {
+	mock := &MockIWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockIWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebhookRepository) EXPECT() *MockIWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockIWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.DueDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]model.DueDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockIWebhookRepositoryMockRecorder) ClaimDueDeliveries(ctx, now, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockIWebhookRepository)(nil).ClaimDueDeliveries), ctx, now, lease, limit)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockIWebhookRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockIWebhookRepositoryMockRecorder) CreateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIWebhookRepository)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockIWebhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockIWebhookRepositoryMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIWebhookRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockIWebhookRepository) EnqueueDeliveries(ctx context.Context, event model.Event, payload []byte, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, event, payload, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockIWebhookRepositoryMockRecorder) EnqueueDeliveries(ctx, event, payload, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockIWebhookRepository)(nil).EnqueueDeliveries), ctx, event, payload, now)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockIWebhookRepository) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookID, status, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockIWebhookRepositoryMockRecorder) GetDeliveries(ctx, webhookID, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockIWebhookRepository)(nil).GetDeliveries), ctx, webhookID, status, limit)
+}
+
+// GetWebhook mocks base method.
+func (m *MockIWebhookRepository) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockIWebhookRepositoryMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockIWebhookRepository)(nil).GetWebhook), ctx, id)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockIWebhookRepository) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockIWebhookRepositoryMockRecorder) GetWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockIWebhookRepository)(nil).GetWebhooks), ctx)
+}
+
+// RedeliverDelivery mocks base method.
+func (m *MockIWebhookRepository) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverDelivery", ctx, webhookID, deliveryID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverDelivery indicates an expected call of RedeliverDelivery.
+func (mr *MockIWebhookRepositoryMockRecorder) RedeliverDelivery(ctx, webhookID, deliveryID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverDelivery", reflect.TypeOf((*MockIWebhookRepository)(nil).RedeliverDelivery), ctx, webhookID, deliveryID, now)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockIWebhookRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockIWebhookRepositoryMockRecorder) UpdateDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockIWebhookRepository)(nil).UpdateDelivery), ctx, delivery)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockIWebhookRepository) UpdateWebhook(ctx context.Context, id int, webhook model.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, id, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockIWebhookRepositoryMockRecorder) UpdateWebhook(ctx, id, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockIWebhookRepository)(nil).UpdateWebhook), ctx, id, webhook)
+}
@@ -1,14 +1,38 @@
 package repository
 
 import (
+	"context"
 	"github.com/dilaragorum/movie-go/model"
 )
 
 type IMovieRepository interface {
-	GetMovies() ([]model.Movie, error)
-	GetMovie(id int) (model.Movie, error)
-	CreateMovie(movie model.Movie) error
-	DeleteMovie(id int) error
-	DeleteAllMovies() error
-	UpdateMovie(id int, movie model.Movie) error
+	GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error)
+	// StreamMovies calls fn for every movie matching filter without loading the whole
+	// catalog into memory. It stops at the first error returned by fn and returns it.
+	StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error
+	GetMovie(ctx context.Context, id int) (model.Movie, error)
+	// GetMoviesByID returns the movies of ids in one read, by id. Unknown ids are left
+	// out.
+	GetMoviesByID(ctx context.Context, ids []int) ([]model.Movie, error)
+	// GetBestScoredMovies returns at most limit movies, best scored first, leaving out
+	// the ones of excludedIDs.
+	GetBestScoredMovies(ctx context.Context, limit int, excludedIDs []int) ([]model.Movie, error)
+	// GetMovieStats aggregates the movies matching filter, see model.MovieStats. The
+	// top lists hold at most topN movies.
+	GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error)
+	// LockMovieTitle holds off the other callers locking the same title key and
+	// release year until the transaction of ctx ends, so that the check for an exact
+	// duplicate and the creation of a movie happen at once.
+	LockMovieTitle(ctx context.Context, titleKey string, releaseYear int) error
+	// CreateMovie returns the ID of the created movie.
+	CreateMovie(ctx context.Context, movie model.Movie) (int, error)
+	DeleteMovie(ctx context.Context, id int) error
+	// DeleteAllMovies returns the IDs of the deleted movies.
+	DeleteAllMovies(ctx context.Context) ([]int, error)
+	UpdateMovie(ctx context.Context, id int, movie model.Movie) error
+	// MergeMovies folds the source movie into the target one with model.Movie.Merge,
+	// moves its credits, ratings, reviews and watchlist entries over, skipping the ones
+	// the target already has, and deletes it, all at once.
+	MergeMovies(ctx context.Context, targetID int, sourceID int) error
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"time"
+)
+
+// mockgen -source repository/outbox_repository_interface.go -destination repository/mock_outbox_repository.go -package repository
+type IOutboxRepository interface {
+	// AppendEvents stores events until they are published, assigning their IDs. Called
+	// within ITransactor.InTx, they are stored only if the transaction commits.
+	AppendEvents(ctx context.Context, events []model.Event) error
+	// ClaimPendingEvents returns at most limit events, oldest first, and claims them for
+	// lease: concurrent callers skip them until it runs out, so that they are published
+	// without holding a transaction open. Events neither deleted nor released by then
+	// are claimed again.
+	ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error)
+	// ReleaseEvents gives up the claim on events that were not published, so that they
+	// are claimed again right away.
+	ReleaseEvents(ctx context.Context, ids []int64) error
+	// DeleteEvents removes the published events.
+	DeleteEvents(ctx context.Context, ids []int64) error
+}
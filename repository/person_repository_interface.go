@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source repository/person_repository_interface.go -destination repository/mock_person_repository.go -package repository
+type IPersonRepository interface {
+	GetPeople(ctx context.Context) ([]model.Person, error)
+	GetPerson(ctx context.Context, id int) (model.Person, error)
+	CreatePerson(ctx context.Context, person model.Person) error
+	UpdatePerson(ctx context.Context, id int, person model.Person) error
+	DeletePerson(ctx context.Context, id int) error
+	// GetMovieCredits returns the credits of a movie in billing order, with the person
+	// names filled in.
+	GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error)
+	// GetCreditsByMovies returns the credits of several movies at once, keyed by movie
+	// ID and ordered like GetMovieCredits. Movies without credits have no key.
+	GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error)
+	// GetPersonMovies returns the movies a person is credited on, oldest first. An empty
+	// role matches every role.
+	GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error)
+	AddCredit(ctx context.Context, credit model.Credit) error
+	DeleteCredit(ctx context.Context, movieID int, creditID int) error
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	ErrPendingMigrations = errors.New("FromRepository - database has pending migrations")
+)
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    TEXT PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+
+// Migrate applies every migration in repository/migrations that has not run yet, in
+// file name order, each in its own transaction.
+func (p *postgresqlMovieRepository) Migrate(ctx context.Context) error {
+	if _, err := p.connectionPool.ExecContext(ctx, createMigrationsTable); err != nil {
+		return err
+	}
+
+	pending, err := p.PendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range pending {
+		statements, err := migrationFiles.ReadFile("migrations/" + version)
+		if err != nil {
+			return err
+		}
+
+		tx, err := p.connectionPool.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, string(statements)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		p.logger.InfoContext(ctx, "migration applied", "version", version)
+	}
+
+	return nil
+}
+
+// PendingMigrations lists the migrations that have not been applied yet.
+func (p *postgresqlMovieRepository) PendingMigrations(ctx context.Context) ([]string, error) {
+	applied, err := p.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(versions)
+
+	var pending []string
+	for _, path := range versions {
+		version := path[len("migrations/"):]
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+	return pending, nil
+}
+
+func (p *postgresqlMovieRepository) appliedMigrations(ctx context.Context) (map[string]bool, error) {
+	applied := make(map[string]bool)
+
+	var table sql.NullString
+	err := p.connectionPool.QueryRowContext(ctx, "SELECT to_regclass('public.schema_migrations')").Scan(&table)
+	if err != nil || !table.Valid {
+		return applied, err
+	}
+
+	rows, err := p.connectionPool.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// CheckMigrations fails while PendingMigrations is not empty. It backs the readiness probe.
+func (p *postgresqlMovieRepository) CheckMigrations(ctx context.Context) error {
+	pending, err := p.PendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %v", ErrPendingMigrations, pending)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// mergeMovie follows model.Movie.Merge: the columns the target leaves blank are taken
+// from the source, and so is the score of an unrated target. The score is settled by
+// recalculateRatings once the ratings are moved.
+const mergeMovie = `UPDATE movies t SET
+    release_year      = CASE WHEN t.release_year = 0 THEN s.release_year ELSE t.release_year END,
+    score             = CASE WHEN t.score = 0 AND t.rating_count = 0 THEN s.score ELSE t.score END,
+    editorial_score   = CASE WHEN t.editorial_score = 0 THEN s.editorial_score ELSE t.editorial_score END,
+    runtime_minutes   = CASE WHEN t.runtime_minutes = 0 THEN s.runtime_minutes ELSE t.runtime_minutes END,
+    synopsis          = COALESCE(NULLIF(t.synopsis, ''), s.synopsis),
+    original_language = COALESCE(NULLIF(t.original_language, ''), s.original_language),
+    country           = COALESCE(NULLIF(t.country, ''), s.country),
+    age_rating        = COALESCE(NULLIF(t.age_rating, ''), s.age_rating),
+    poster_url        = COALESCE(NULLIF(t.poster_url, ''), s.poster_url),
+    updated_at        = now()
+FROM movies s
+WHERE t.id = $1 AND s.id = $2`
+
+// mergeMovieRecords move the records of the source movie ($2) to the target one ($1).
+// The ones the target already has are left behind and go with the source.
+var mergeMovieRecords = []string{
+	`INSERT INTO movie_genres (movie_id, genre_id)
+SELECT $1, genre_id FROM movie_genres WHERE movie_id = $2
+ON CONFLICT DO NOTHING`,
+	`UPDATE credits c SET movie_id = $1
+WHERE c.movie_id = $2 AND NOT EXISTS (
+    SELECT 1 FROM credits t
+    WHERE t.movie_id = $1 AND t.person_id = c.person_id AND t.role = c.role AND t.character = c.character)`,
+	`UPDATE ratings r SET movie_id = $1
+WHERE r.movie_id = $2 AND NOT EXISTS (SELECT 1 FROM ratings t WHERE t.movie_id = $1 AND t.user_id = r.user_id)`,
+	"UPDATE reviews SET movie_id = $1 WHERE movie_id = $2",
+	`UPDATE watchlist_items w SET movie_id = $1
+WHERE w.movie_id = $2 AND NOT EXISTS (SELECT 1 FROM watchlist_items t WHERE t.movie_id = $1 AND t.user_id = w.user_id)`,
+	"UPDATE watched_entries SET movie_id = $1 WHERE movie_id = $2",
+}
+
+func (p *postgresqlMovieRepository) MergeMovies(ctx context.Context, targetID int, sourceID int) error {
+	recordStatement(ctx, p.logger, mergeMovie)
+
+	return inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		// Both rows are locked in id order, so that two merges of the same pair in
+		// opposite directions cannot deadlock.
+		var locked int
+		err := tx.QueryRowContext(ctx,
+			"SELECT count(*) FROM (SELECT id FROM movies WHERE id IN ($1, $2) ORDER BY id FOR UPDATE) m",
+			targetID, sourceID).Scan(&locked)
+		if err != nil {
+			return err
+		}
+		if locked < 2 {
+			return ErrMovieNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, mergeMovie, targetID, sourceID); err != nil {
+			return err
+		}
+		for _, statement := range mergeMovieRecords {
+			if _, err := tx.ExecContext(ctx, statement, targetID, sourceID); err != nil {
+				return err
+			}
+		}
+
+		// The source takes its remaining records and neighbours along.
+		if _, err := tx.ExecContext(ctx, deleteMovie, sourceID); err != nil {
+			return err
+		}
+
+		_, err = recalculateRatings(ctx, tx, targetID)
+		return err
+	})
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// The statistics statements aggregate the filtered movies f, the output of
+// moviesQuery. Averages are rounded like model.NewMovieStats rounds them.
+const (
+	selectStatsTotals = `SELECT count(*), COALESCE(round(avg(f.score)::numeric, 2), 0)::float8 FROM f`
+
+	selectStatsByGenre = `SELECT genre, count(*), round(avg(f.score)::numeric, 2)::float8
+FROM f, unnest(f.genres) AS genre
+GROUP BY genre
+ORDER BY genre`
+
+	selectStatsByYear = `SELECT f.release_year, count(*), round(avg(f.score)::numeric, 2)::float8
+FROM f
+GROUP BY f.release_year
+ORDER BY f.release_year`
+
+	selectStatsByDecade = `SELECT f.release_year / 10 * 10, count(*), round(avg(f.score)::numeric, 2)::float8
+FROM f
+GROUP BY 1
+ORDER BY 1`
+
+	selectStatsTop = `SELECT f.* FROM f ORDER BY f.score DESC, f.id LIMIT $%d`
+
+	selectStatsTopByDecade = `SELECT f.* FROM f
+JOIN (SELECT id, row_number() OVER (PARTITION BY release_year / 10 ORDER BY score DESC, id) AS rank FROM f) r ON r.id = f.id
+WHERE r.rank <= $%d
+ORDER BY f.release_year / 10, r.rank`
+)
+
+var selectStatsHistogram = fmt.Sprintf(`SELECT LEAST(GREATEST(floor(f.score)::integer, 0), %d), count(*)
+FROM f
+GROUP BY 1`, model.ScoreBuckets-1)
+
+func (p *postgresqlMovieRepository) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	filtered, args := moviesQuery(filter)
+	with := "WITH f AS (\n" + filtered + "\n)\n"
+	topArgs := append(append([]interface{}{}, args...), topN)
+
+	// One snapshot for all the statements, so that the numbers add up.
+	tx, err := p.connectionPool.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return model.MovieStats{}, err
+	}
+	defer tx.Rollback()
+
+	query := func(statement string, args []interface{}, scan func(rows *sql.Rows) error) error {
+		statement = with + statement
+		recordStatement(ctx, p.logger, statement)
+
+		rows, err := tx.QueryContext(ctx, statement, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}
+
+	stats := model.MovieStats{
+		ByGenre:     make([]model.GenreStats, 0),
+		ByYear:      make([]model.PeriodStats, 0),
+		ByDecade:    make([]model.PeriodStats, 0),
+		Top:         make([]model.Movie, 0, topN),
+		TopByDecade: make([]model.DecadeTop, 0),
+	}
+	var histogram [model.ScoreBuckets]int
+
+	periods := func(list *[]model.PeriodStats) func(rows *sql.Rows) error {
+		return func(rows *sql.Rows) error {
+			period := model.PeriodStats{}
+			if err := rows.Scan(&period.Year, &period.Count, &period.AverageScore); err != nil {
+				return err
+			}
+			*list = append(*list, period)
+			return nil
+		}
+	}
+
+	statements := []struct {
+		statement string
+		args      []interface{}
+		scan      func(rows *sql.Rows) error
+	}{
+		{selectStatsTotals, args, func(rows *sql.Rows) error {
+			return rows.Scan(&stats.Count, &stats.AverageScore)
+		}},
+		{selectStatsByGenre, args, func(rows *sql.Rows) error {
+			genre := model.GenreStats{}
+			if err := rows.Scan(&genre.Genre, &genre.Count, &genre.AverageScore); err != nil {
+				return err
+			}
+			stats.ByGenre = append(stats.ByGenre, genre)
+			return nil
+		}},
+		{selectStatsByYear, args, periods(&stats.ByYear)},
+		{selectStatsByDecade, args, periods(&stats.ByDecade)},
+		{selectStatsHistogram, args, func(rows *sql.Rows) error {
+			var bucket, count int
+			if err := rows.Scan(&bucket, &count); err != nil {
+				return err
+			}
+			histogram[bucket] = count
+			return nil
+		}},
+		{fmt.Sprintf(selectStatsTop, len(topArgs)), topArgs, func(rows *sql.Rows) error {
+			movie, err := scanMovie(rows)
+			if err != nil {
+				return err
+			}
+			stats.Top = append(stats.Top, movie)
+			return nil
+		}},
+		{fmt.Sprintf(selectStatsTopByDecade, len(topArgs)), topArgs, func(rows *sql.Rows) error {
+			movie, err := scanMovie(rows)
+			if err != nil {
+				return err
+			}
+
+			decade := model.Decade(movie.ReleaseYear)
+			last := len(stats.TopByDecade) - 1
+			if last < 0 || stats.TopByDecade[last].Decade != decade {
+				stats.TopByDecade = append(stats.TopByDecade, model.DecadeTop{Decade: decade, Movies: make([]model.Movie, 0, topN)})
+				last++
+			}
+			stats.TopByDecade[last].Movies = append(stats.TopByDecade[last].Movies, movie)
+			return nil
+		}},
+	}
+
+	for _, s := range statements {
+		if err := query(s.statement, s.args, s.scan); err != nil {
+			return model.MovieStats{}, err
+		}
+	}
+
+	stats.ScoreHistogram = model.NewScoreHistogram(histogram)
+	return stats, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/lib/pq"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type postgresqlOutboxRepository struct {
+	connectionPool *sql.DB
+	logger         *slog.Logger
+}
+
+func NewPostgreSQLOutboxRepository(connectionPool *sql.DB, logger *slog.Logger) *postgresqlOutboxRepository {
+	return &postgresqlOutboxRepository{
+		connectionPool: connectionPool,
+		logger:         logger,
+	}
+}
+
+const insertEvent = `INSERT INTO outbox_events (type, movie_id, payload, occurred_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id`
+
+func (p *postgresqlOutboxRepository) AppendEvents(ctx context.Context, events []model.Event) error {
+	recordStatement(ctx, p.logger, insertEvent)
+
+	for k, event := range events {
+		var payload []byte
+		if event.Movie != nil {
+			var err error
+			if payload, err = json.Marshal(event.Movie); err != nil {
+				return err
+			}
+		}
+
+		err := conn(ctx, p.connectionPool).QueryRowContext(ctx, insertEvent,
+			event.Type, event.MovieID, payload, event.OccurredAt).Scan(&events[k].ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The ids are handed out on insert, so a transaction committing late can leave an older
+// event behind a newer one: the order of the events is kept only on a best effort basis.
+// The rows are only locked while they are claimed, by this statement alone.
+const claimPendingEvents = `UPDATE outbox_events
+SET claimed_until = now() + make_interval(secs => $2)
+WHERE id IN (
+    SELECT id FROM outbox_events
+    WHERE claimed_until IS NULL OR claimed_until < now()
+    ORDER BY id
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, type, movie_id, payload, occurred_at`
+
+func (p *postgresqlOutboxRepository) ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	recordStatement(ctx, p.logger, claimPendingEvents)
+
+	rows, err := conn(ctx, p.connectionPool).QueryContext(ctx, claimPendingEvents, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]model.Event, 0)
+	for rows.Next() {
+		var event model.Event
+		var payload []byte
+		if err := rows.Scan(&event.ID, &event.Type, &event.MovieID, &payload, &event.OccurredAt); err != nil {
+			return nil, err
+		}
+
+		if payload != nil {
+			event.Movie = &model.Movie{}
+			if err := json.Unmarshal(payload, event.Movie); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	sort.Slice(events, func(a, b int) bool { return events[a].ID < events[b].ID })
+	return events, nil
+}
+
+const releaseEvents = "UPDATE outbox_events SET claimed_until = NULL WHERE id = ANY($1)"
+
+func (p *postgresqlOutboxRepository) ReleaseEvents(ctx context.Context, ids []int64) error {
+	recordStatement(ctx, p.logger, releaseEvents)
+
+	_, err := conn(ctx, p.connectionPool).ExecContext(ctx, releaseEvents, pq.Array(ids))
+	return err
+}
+
+const deleteEvents = "DELETE FROM outbox_events WHERE id = ANY($1)"
+
+func (p *postgresqlOutboxRepository) DeleteEvents(ctx context.Context, ids []int64) error {
+	recordStatement(ctx, p.logger, deleteEvents)
+
+	_, err := conn(ctx, p.connectionPool).ExecContext(ctx, deleteEvents, pq.Array(ids))
+	return err
+}
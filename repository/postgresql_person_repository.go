@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+type postgresqlPersonRepository struct {
+	connectionPool *sql.DB
+	logger         *slog.Logger
+}
+
+// NewPostgreSQLPersonRepository shares the connection pool of the movie repository,
+// whose Migrate also creates the people and credits tables.
+func NewPostgreSQLPersonRepository(connectionPool *sql.DB, logger *slog.Logger) *postgresqlPersonRepository {
+	return &postgresqlPersonRepository{
+		connectionPool: connectionPool,
+		logger:         logger,
+	}
+}
+
+const selectPeople = "SELECT id, name, birth_year, updated_at FROM people"
+
+func (p *postgresqlPersonRepository) GetPeople(ctx context.Context) ([]model.Person, error) {
+	query := selectPeople + " ORDER BY id"
+	recordStatement(ctx, p.logger, query)
+
+	rows, err := p.connectionPool.QueryContext(ctx, query)
+	if err != nil {
+		return []model.Person{}, err
+	}
+	defer rows.Close()
+
+	people := make([]model.Person, 0)
+	for rows.Next() {
+		person := model.Person{}
+		if err := rows.Scan(&person.ID, &person.Name, &person.BirthYear, &person.UpdatedAt); err != nil {
+			return []model.Person{}, err
+		}
+		people = append(people, person)
+	}
+
+	return people, rows.Err()
+}
+
+func (p *postgresqlPersonRepository) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	query := selectPeople + " WHERE id = $1"
+	recordStatement(ctx, p.logger, query)
+
+	person := model.Person{}
+	err := p.connectionPool.QueryRowContext(ctx, query, id).
+		Scan(&person.ID, &person.Name, &person.BirthYear, &person.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Person{}, ErrPersonNotFound
+	}
+	return person, err
+}
+
+const insertPerson = "INSERT INTO people (name, birth_year) VALUES ($1, $2)"
+
+func (p *postgresqlPersonRepository) CreatePerson(ctx context.Context, person model.Person) error {
+	recordStatement(ctx, p.logger, insertPerson)
+
+	_, err := p.connectionPool.ExecContext(ctx, insertPerson, person.Name, person.BirthYear)
+	return err
+}
+
+const updatePerson = `UPDATE people SET
+    name       = $2,
+    birth_year = COALESCE(NULLIF($3::int, 0), birth_year),
+    updated_at = now()
+WHERE id = $1`
+
+func (p *postgresqlPersonRepository) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	recordStatement(ctx, p.logger, updatePerson)
+
+	result, err := p.connectionPool.ExecContext(ctx, updatePerson, id, person.Name, person.BirthYear)
+	return affectedOne(result, err, ErrPersonNotFound)
+}
+
+const deletePerson = "DELETE FROM people WHERE id = $1"
+
+func (p *postgresqlPersonRepository) DeletePerson(ctx context.Context, id int) error {
+	recordStatement(ctx, p.logger, deletePerson)
+
+	result, err := p.connectionPool.ExecContext(ctx, deletePerson, id)
+	return affectedOne(result, err, ErrPersonNotFound)
+}
+
+const selectCredits = `SELECT c.id, c.movie_id, c.person_id, p.name, c.role, c.character, c.billing_order
+FROM credits c
+JOIN people p ON p.id = c.person_id`
+
+const selectMovieCredits = selectCredits + `
+WHERE c.movie_id = $1
+ORDER BY c.billing_order, c.id`
+
+func (p *postgresqlPersonRepository) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	recordStatement(ctx, p.logger, selectMovieCredits)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectMovieCredits, movieID)
+	if err != nil {
+		return []model.Credit{}, err
+	}
+	defer rows.Close()
+
+	credits := make([]model.Credit, 0)
+	for rows.Next() {
+		credit, err := scanCredit(rows)
+		if err != nil {
+			return []model.Credit{}, err
+		}
+		credits = append(credits, credit)
+	}
+
+	return credits, rows.Err()
+}
+
+const selectCreditsByMovies = selectCredits + `
+WHERE c.movie_id = ANY($1)
+ORDER BY c.movie_id, c.billing_order, c.id`
+
+func (p *postgresqlPersonRepository) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	recordStatement(ctx, p.logger, selectCreditsByMovies)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectCreditsByMovies, pq.Array(movieIDs))
+	if err != nil {
+		return map[int][]model.Credit{}, err
+	}
+	defer rows.Close()
+
+	credits := make(map[int][]model.Credit)
+	for rows.Next() {
+		credit, err := scanCredit(rows)
+		if err != nil {
+			return map[int][]model.Credit{}, err
+		}
+		credits[credit.MovieID] = append(credits[credit.MovieID], credit)
+	}
+
+	return credits, rows.Err()
+}
+
+func scanCredit(row scanner) (model.Credit, error) {
+	credit := model.Credit{}
+	err := row.Scan(&credit.ID, &credit.MovieID, &credit.PersonID, &credit.PersonName,
+		&credit.Role, &credit.Character, &credit.BillingOrder)
+	return credit, err
+}
+
+func (p *postgresqlPersonRepository) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	query := selectMovies + `
+WHERE m.id IN (SELECT movie_id FROM credits WHERE person_id = $1 AND ($2 = '' OR role = $2))
+GROUP BY m.id
+ORDER BY m.release_year, m.id`
+	recordStatement(ctx, p.logger, query)
+
+	rows, err := p.connectionPool.QueryContext(ctx, query, personID, role)
+	if err != nil {
+		return []model.Movie{}, err
+	}
+	defer rows.Close()
+
+	movies := make([]model.Movie, 0)
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			return []model.Movie{}, err
+		}
+		movies = append(movies, movie)
+	}
+
+	return movies, rows.Err()
+}
+
+const insertCredit = `INSERT INTO credits (movie_id, person_id, role, character, billing_order)
+VALUES ($1, $2, $3, $4, $5)`
+
+func (p *postgresqlPersonRepository) AddCredit(ctx context.Context, credit model.Credit) error {
+	recordStatement(ctx, p.logger, insertCredit)
+
+	_, err := p.connectionPool.ExecContext(ctx, insertCredit,
+		credit.MovieID, credit.PersonID, credit.Role, credit.Character, credit.BillingOrder)
+	return err
+}
+
+const deleteCredit = "DELETE FROM credits WHERE id = $1 AND movie_id = $2"
+
+func (p *postgresqlPersonRepository) DeleteCredit(ctx context.Context, movieID int, creditID int) error {
+	recordStatement(ctx, p.logger, deleteCredit)
+
+	result, err := p.connectionPool.ExecContext(ctx, deleteCredit, creditID, movieID)
+	return affectedOne(result, err, ErrCreditNotFound)
+}
+
+// affectedOne turns the result of a statement that matched no row into notFound.
+func affectedOne(result sql.Result, err error, notFound error) error {
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
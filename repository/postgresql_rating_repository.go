@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+type postgresqlRatingRepository struct {
+	connectionPool *sql.DB
+	logger         *slog.Logger
+}
+
+// NewPostgreSQLRatingRepository shares the connection pool of the movie repository,
+// whose Migrate also creates the ratings and reviews tables.
+func NewPostgreSQLRatingRepository(connectionPool *sql.DB, logger *slog.Logger) *postgresqlRatingRepository {
+	return &postgresqlRatingRepository{
+		connectionPool: connectionPool,
+		logger:         logger,
+	}
+}
+
+const selectRatings = "SELECT movie_id, user_id, value, updated_at FROM ratings WHERE movie_id = $1 ORDER BY user_id"
+
+func (p *postgresqlRatingRepository) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	recordStatement(ctx, p.logger, selectRatings)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectRatings, movieID)
+	if err != nil {
+		return []model.Rating{}, err
+	}
+	defer rows.Close()
+
+	ratings := make([]model.Rating, 0)
+	for rows.Next() {
+		rating := model.Rating{}
+		if err := rows.Scan(&rating.MovieID, &rating.UserID, &rating.Value, &rating.UpdatedAt); err != nil {
+			return []model.Rating{}, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
+
+const selectUserRatings = "SELECT movie_id, user_id, value, updated_at FROM ratings WHERE user_id = $1 ORDER BY updated_at DESC, movie_id"
+
+func (p *postgresqlRatingRepository) GetUserRatings(ctx context.Context, userID int) ([]model.Rating, error) {
+	recordStatement(ctx, p.logger, selectUserRatings)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectUserRatings, userID)
+	if err != nil {
+		return []model.Rating{}, err
+	}
+	defer rows.Close()
+
+	ratings := make([]model.Rating, 0)
+	for rows.Next() {
+		rating := model.Rating{}
+		if err := rows.Scan(&rating.MovieID, &rating.UserID, &rating.Value, &rating.UpdatedAt); err != nil {
+			return []model.Rating{}, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
+
+const selectAllRatings = "SELECT movie_id, user_id, value, updated_at FROM ratings ORDER BY movie_id, user_id"
+
+func (p *postgresqlRatingRepository) StreamRatings(ctx context.Context, fn func(rating model.Rating) error) error {
+	recordStatement(ctx, p.logger, selectAllRatings)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectAllRatings)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rating := model.Rating{}
+		if err := rows.Scan(&rating.MovieID, &rating.UserID, &rating.Value, &rating.UpdatedAt); err != nil {
+			return err
+		}
+		if err := fn(rating); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+const upsertRating = `INSERT INTO ratings (movie_id, user_id, value) VALUES ($1, $2, $3)
+ON CONFLICT (movie_id, user_id) DO UPDATE SET value = EXCLUDED.value, updated_at = now()`
+
+func (p *postgresqlRatingRepository) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	recordStatement(ctx, p.logger, upsertRating)
+
+	var summary model.RatingSummary
+	err := inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		if err := lockMovie(ctx, tx, rating.MovieID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, upsertRating, rating.MovieID, rating.UserID, rating.Value); err != nil {
+			return err
+		}
+
+		var err error
+		summary, err = recalculateRatings(ctx, tx, rating.MovieID)
+		return err
+	})
+	return summary, err
+}
+
+const deleteRating = "DELETE FROM ratings WHERE movie_id = $1 AND user_id = $2"
+
+func (p *postgresqlRatingRepository) DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error) {
+	recordStatement(ctx, p.logger, deleteRating)
+
+	var summary model.RatingSummary
+	err := inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		if err := lockMovie(ctx, tx, movieID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, deleteRating, movieID, userID)
+		if err := affectedOne(result, err, ErrRatingNotFound); err != nil {
+			return err
+		}
+
+		summary, err = recalculateRatings(ctx, tx, movieID)
+		return err
+	})
+	return summary, err
+}
+
+// lockMovie locks the movie row, so that concurrent ratings of one movie recalculate
+// its aggregate one after the other.
+func lockMovie(ctx context.Context, tx *sql.Tx, movieID int) error {
+	var id int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM movies WHERE id = $1 FOR UPDATE", movieID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMovieNotFound
+	}
+	return err
+}
+
+// updateRatingSummary falls back to the editorial score once the last rating is gone.
+const updateRatingSummary = `UPDATE movies SET
+    rating_count = $2,
+    rating_mean  = $3,
+    score        = CASE WHEN $2::int > 0 THEN $4::double precision ELSE editorial_score END,
+    updated_at   = now()
+WHERE id = $1
+RETURNING score`
+
+// recalculateRatings stores the model.NewRatingSummary of the movie. It only depends
+// on the ratings of that movie, so the other movies stay up to date.
+func recalculateRatings(ctx context.Context, tx *sql.Tx, movieID int) (model.RatingSummary, error) {
+	var count int
+	var mean float64
+	err := tx.QueryRowContext(ctx, "SELECT count(*), COALESCE(avg(value), 0) FROM ratings WHERE movie_id = $1", movieID).
+		Scan(&count, &mean)
+	if err != nil {
+		return model.RatingSummary{}, err
+	}
+
+	summary := model.RatingSummary{MovieID: movieID}
+	if count > 0 {
+		summary = model.NewRatingSummary(movieID, count, mean)
+	}
+
+	err = tx.QueryRowContext(ctx, updateRatingSummary, movieID, summary.Count, summary.Mean, summary.Score).
+		Scan(&summary.Score)
+	return summary, err
+}
+
+const selectReviews = "SELECT id, movie_id, user_id, body, created_at FROM reviews WHERE movie_id = $1 ORDER BY created_at, id"
+
+func (p *postgresqlRatingRepository) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	recordStatement(ctx, p.logger, selectReviews)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectReviews, movieID)
+	if err != nil {
+		return []model.Review{}, err
+	}
+	defer rows.Close()
+
+	reviews := make([]model.Review, 0)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return []model.Review{}, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+const selectReviewsByMovies = "SELECT id, movie_id, user_id, body, created_at FROM reviews WHERE movie_id = ANY($1) ORDER BY movie_id, created_at, id"
+
+func (p *postgresqlRatingRepository) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	recordStatement(ctx, p.logger, selectReviewsByMovies)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectReviewsByMovies, pq.Array(movieIDs))
+	if err != nil {
+		return map[int][]model.Review{}, err
+	}
+	defer rows.Close()
+
+	reviews := make(map[int][]model.Review)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return map[int][]model.Review{}, err
+		}
+		reviews[review.MovieID] = append(reviews[review.MovieID], review)
+	}
+
+	return reviews, rows.Err()
+}
+
+func scanReview(row scanner) (model.Review, error) {
+	review := model.Review{}
+	err := row.Scan(&review.ID, &review.MovieID, &review.UserID, &review.Body, &review.CreatedAt)
+	return review, err
+}
+
+const insertReview = `INSERT INTO reviews (movie_id, user_id, body)
+SELECT id, $2, $3 FROM movies WHERE id = $1`
+
+func (p *postgresqlRatingRepository) AddReview(ctx context.Context, review model.Review) error {
+	recordStatement(ctx, p.logger, insertReview)
+
+	result, err := p.connectionPool.ExecContext(ctx, insertReview, review.MovieID, review.UserID, review.Body)
+	return affectedOne(result, err, ErrMovieNotFound)
+}
+
+const deleteReview = "DELETE FROM reviews WHERE id = $1 AND movie_id = $2"
+
+func (p *postgresqlRatingRepository) DeleteReview(ctx context.Context, movieID int, reviewID int) error {
+	recordStatement(ctx, p.logger, deleteReview)
+
+	result, err := p.connectionPool.ExecContext(ctx, deleteReview, reviewID, movieID)
+	return affectedOne(result, err, ErrReviewNotFound)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+type postgresqlRecommendationRepository struct {
+	connectionPool *sql.DB
+	logger         *slog.Logger
+}
+
+// NewPostgreSQLRecommendationRepository shares the connection pool of the movie
+// repository, whose Migrate also creates the movie_neighbours table.
+func NewPostgreSQLRecommendationRepository(connectionPool *sql.DB, logger *slog.Logger) *postgresqlRecommendationRepository {
+	return &postgresqlRecommendationRepository{
+		connectionPool: connectionPool,
+		logger:         logger,
+	}
+}
+
+const selectNeighbours = `SELECT movie_id, neighbour_id, similarity FROM movie_neighbours
+WHERE movie_id = $1
+ORDER BY similarity DESC, neighbour_id
+LIMIT $2`
+
+const selectNeighboursComputed = "SELECT EXISTS (SELECT 1 FROM movie_neighbours_computed WHERE movie_id = $1)"
+
+func (p *postgresqlRecommendationRepository) GetNeighbours(ctx context.Context, movieID int, limit int) ([]model.Neighbour, bool, error) {
+	neighbours, err := p.getNeighbours(ctx, movieID, limit)
+	if err != nil || len(neighbours) > 0 {
+		return neighbours, err == nil, err
+	}
+
+	recordStatement(ctx, p.logger, selectNeighboursComputed)
+
+	var computed bool
+	err = p.connectionPool.QueryRowContext(ctx, selectNeighboursComputed, movieID).Scan(&computed)
+	return neighbours, computed, err
+}
+
+func (p *postgresqlRecommendationRepository) getNeighbours(ctx context.Context, movieID int, limit int) ([]model.Neighbour, error) {
+	recordStatement(ctx, p.logger, selectNeighbours)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectNeighbours, movieID, limit)
+	if err != nil {
+		return []model.Neighbour{}, err
+	}
+	defer rows.Close()
+
+	neighbours := make([]model.Neighbour, 0)
+	for rows.Next() {
+		neighbour := model.Neighbour{}
+		if err := rows.Scan(&neighbour.MovieID, &neighbour.NeighbourID, &neighbour.Similarity); err != nil {
+			return []model.Neighbour{}, err
+		}
+		neighbours = append(neighbours, neighbour)
+	}
+
+	return neighbours, rows.Err()
+}
+
+// The neighbours are sent as three arrays, one statement whatever their number. Movies
+// deleted while they were computed are skipped instead of failing the foreign keys.
+const insertNeighbours = `INSERT INTO movie_neighbours (movie_id, neighbour_id, similarity)
+SELECT n.movie_id, n.neighbour_id, n.similarity
+FROM unnest($1::integer[], $2::integer[], $3::double precision[]) AS n (movie_id, neighbour_id, similarity)
+JOIN movies m ON m.id = n.movie_id
+JOIN movies nm ON nm.id = n.neighbour_id`
+
+const insertNeighboursComputed = `INSERT INTO movie_neighbours_computed (movie_id)
+SELECT m.id FROM movies m WHERE m.id = ANY($1::integer[])`
+
+func (p *postgresqlRecommendationRepository) ReplaceNeighbours(ctx context.Context, computedIDs []int, neighbours []model.Neighbour) error {
+	recordStatement(ctx, p.logger, insertNeighboursComputed)
+	recordStatement(ctx, p.logger, insertNeighbours)
+
+	movieIDs := make([]int64, len(neighbours))
+	neighbourIDs := make([]int64, len(neighbours))
+	similarities := make([]float64, len(neighbours))
+	for k, neighbour := range neighbours {
+		movieIDs[k] = int64(neighbour.MovieID)
+		neighbourIDs[k] = int64(neighbour.NeighbourID)
+		similarities[k] = neighbour.Similarity
+	}
+
+	return inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM movie_neighbours"); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM movie_neighbours_computed"); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, insertNeighboursComputed, pq.Array(int64s(computedIDs))); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, insertNeighbours, pq.Array(movieIDs), pq.Array(neighbourIDs), pq.Array(similarities))
+		return err
+	})
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type txKey struct{}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction ctx runs in, if any, and connectionPool otherwise.
+func conn(ctx context.Context, connectionPool *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return connectionPool
+}
+
+// inTx runs fn in a transaction that is committed when fn succeeds and rolled back
+// otherwise. Within the transaction of ctx, fn joins it and leaves the outcome to it.
+func inTx(ctx context.Context, connectionPool *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	tx, err := connectionPool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type postgresqlTransactor struct {
+	connectionPool *sql.DB
+}
+
+// NewPostgreSQLTransactor runs the transactions of the repositories sharing
+// connectionPool.
+func NewPostgreSQLTransactor(connectionPool *sql.DB) *postgresqlTransactor {
+	return &postgresqlTransactor{connectionPool: connectionPool}
+}
+
+func (p *postgresqlTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
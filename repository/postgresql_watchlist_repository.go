@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/dilaragorum/movie-go/model"
+	"log/slog"
+)
+
+type postgresqlWatchlistRepository struct {
+	connectionPool *sql.DB
+	logger         *slog.Logger
+}
+
+// NewPostgreSQLWatchlistRepository shares the connection pool of the movie repository,
+// whose Migrate also creates the watchlist tables. Their foreign keys cascade movie
+// deletions.
+func NewPostgreSQLWatchlistRepository(connectionPool *sql.DB, logger *slog.Logger) *postgresqlWatchlistRepository {
+	return &postgresqlWatchlistRepository{
+		connectionPool: connectionPool,
+		logger:         logger,
+	}
+}
+
+// The listed movies are read with selectMovies, joined on the list by movie id.
+const selectWatchlist = `SELECT w.user_id, w.movie_id, w.position, w.added_at, l.*
+FROM watchlist_items w
+JOIN (` + selectMovies + `
+      GROUP BY m.id) l ON l.id = w.movie_id
+WHERE w.user_id = $1
+ORDER BY w.position, w.added_at`
+
+func (p *postgresqlWatchlistRepository) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	recordStatement(ctx, p.logger, selectWatchlist)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectWatchlist, userID)
+	if err != nil {
+		return []model.WatchlistItem{}, err
+	}
+	defer rows.Close()
+
+	items := make([]model.WatchlistItem, 0)
+	for rows.Next() {
+		item := model.WatchlistItem{}
+		movie, err := scanMovie(newPrefixedScanner(rows, &item.UserID, &item.MovieID, &item.Position, &item.AddedAt))
+		if err != nil {
+			return []model.WatchlistItem{}, err
+		}
+		item.Movie = &movie
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+const insertWatchlistItem = `INSERT INTO watchlist_items (user_id, movie_id, position)
+SELECT $1, m.id, COALESCE((SELECT max(position) + 1 FROM watchlist_items WHERE user_id = $1), 0)
+FROM movies m
+WHERE m.id = $2
+ON CONFLICT (user_id, movie_id) DO NOTHING`
+
+func (p *postgresqlWatchlistRepository) AddToWatchlist(ctx context.Context, userID int, movieID int) error {
+	recordStatement(ctx, p.logger, insertWatchlistItem)
+
+	return inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, insertWatchlistItem, userID, movieID); err != nil {
+			return err
+		}
+
+		// The insert matches no movie when it does not exist, and does nothing when it is
+		// already listed; only the first is an error.
+		var exists bool
+		err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1)", movieID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrMovieNotFound
+		}
+		return nil
+	})
+}
+
+const deleteWatchlistItem = "DELETE FROM watchlist_items WHERE user_id = $1 AND movie_id = $2"
+
+func (p *postgresqlWatchlistRepository) RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error {
+	recordStatement(ctx, p.logger, deleteWatchlistItem)
+
+	result, err := p.connectionPool.ExecContext(ctx, deleteWatchlistItem, userID, movieID)
+	return affectedOne(result, err, ErrWatchlistItemNotFound)
+}
+
+func (p *postgresqlWatchlistRepository) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	const lockWatchlist = "SELECT movie_id FROM watchlist_items WHERE user_id = $1 FOR UPDATE"
+	recordStatement(ctx, p.logger, lockWatchlist)
+
+	return inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, lockWatchlist, userID)
+		if err != nil {
+			return err
+		}
+
+		listed := make(map[int]bool)
+		for rows.Next() {
+			var movieID int
+			if err := rows.Scan(&movieID); err != nil {
+				rows.Close()
+				return err
+			}
+			listed[movieID] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(movieIDs) != len(listed) {
+			return ErrWatchlistOrderInvalid
+		}
+		for position, movieID := range movieIDs {
+			if !listed[movieID] {
+				return ErrWatchlistOrderInvalid
+			}
+			delete(listed, movieID)
+
+			_, err := tx.ExecContext(ctx,
+				"UPDATE watchlist_items SET position = $3 WHERE user_id = $1 AND movie_id = $2",
+				userID, movieID, position)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+const selectWatched = `SELECT e.id, e.user_id, e.movie_id, e.watched_at, l.*
+FROM watched_entries e
+JOIN (` + selectMovies + `
+      GROUP BY m.id) l ON l.id = e.movie_id
+WHERE e.user_id = $1
+ORDER BY e.watched_at DESC, e.id DESC`
+
+func (p *postgresqlWatchlistRepository) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	recordStatement(ctx, p.logger, selectWatched)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectWatched, userID)
+	if err != nil {
+		return []model.WatchedEntry{}, err
+	}
+	defer rows.Close()
+
+	entries := make([]model.WatchedEntry, 0)
+	for rows.Next() {
+		entry := model.WatchedEntry{}
+		movie, err := scanMovie(newPrefixedScanner(rows, &entry.ID, &entry.UserID, &entry.MovieID, &entry.WatchedAt))
+		if err != nil {
+			return []model.WatchedEntry{}, err
+		}
+		entry.Movie = &movie
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+const insertWatchedEntry = `INSERT INTO watched_entries (user_id, movie_id, watched_at)
+SELECT $1, id, $3 FROM movies WHERE id = $2`
+
+func (p *postgresqlWatchlistRepository) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	recordStatement(ctx, p.logger, insertWatchedEntry)
+
+	return inTx(ctx, p.connectionPool, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, insertWatchedEntry, entry.UserID, entry.MovieID, entry.WatchedAt)
+		if err := affectedOne(result, err, ErrMovieNotFound); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, deleteWatchlistItem, entry.UserID, entry.MovieID)
+		return err
+	})
+}
+
+const deleteWatchedEntry = "DELETE FROM watched_entries WHERE id = $1 AND user_id = $2"
+
+func (p *postgresqlWatchlistRepository) DeleteWatched(ctx context.Context, userID int, entryID int) error {
+	recordStatement(ctx, p.logger, deleteWatchedEntry)
+
+	result, err := p.connectionPool.ExecContext(ctx, deleteWatchedEntry, entryID, userID)
+	return affectedOne(result, err, ErrWatchedEntryNotFound)
+}
+
+// prefixedScanner scans a row that starts with the given columns and continues with
+// the ones the wrapped scanner is asked for.
+type prefixedScanner struct {
+	scanner
+	prefix []interface{}
+}
+
+func newPrefixedScanner(row scanner, prefix ...interface{}) prefixedScanner {
+	return prefixedScanner{scanner: row, prefix: prefix}
+}
+
+func (p prefixedScanner) Scan(dest ...interface{}) error {
+	return p.scanner.Scan(append(append([]interface{}{}, p.prefix...), dest...)...)
+}
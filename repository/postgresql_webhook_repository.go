@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+type postgresqlWebhookRepository struct {
+	connectionPool *sql.DB
+	logger         *slog.Logger
+}
+
+// NewPostgreSQLWebhookRepository shares the connection pool of the movie repository,
+// whose Migrate also creates the webhook tables. Deleting a webhook cascades to its
+// deliveries.
+func NewPostgreSQLWebhookRepository(connectionPool *sql.DB, logger *slog.Logger) *postgresqlWebhookRepository {
+	return &postgresqlWebhookRepository{
+		connectionPool: connectionPool,
+		logger:         logger,
+	}
+}
+
+const selectWebhooks = "SELECT id, url, events, secret, active, created_at, updated_at FROM webhooks"
+
+func (p *postgresqlWebhookRepository) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	query := selectWebhooks + " ORDER BY id"
+	recordStatement(ctx, p.logger, query)
+
+	rows, err := p.connectionPool.QueryContext(ctx, query)
+	if err != nil {
+		return []model.Webhook{}, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]model.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return []model.Webhook{}, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (p *postgresqlWebhookRepository) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	query := selectWebhooks + " WHERE id = $1"
+	recordStatement(ctx, p.logger, query)
+
+	webhook, err := scanWebhook(p.connectionPool.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Webhook{}, ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+const insertWebhook = `INSERT INTO webhooks (url, events, secret, active)
+VALUES ($1, $2, $3, $4)
+RETURNING id, created_at, updated_at`
+
+func (p *postgresqlWebhookRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	recordStatement(ctx, p.logger, insertWebhook)
+
+	err := p.connectionPool.QueryRowContext(ctx, insertWebhook,
+		webhook.URL, pq.Array(eventTypeNames(webhook.Events)), webhook.Secret, webhook.Active).
+		Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+	return webhook, err
+}
+
+const updateWebhook = `UPDATE webhooks SET
+    url        = $2,
+    events     = $3,
+    active     = $4,
+    updated_at = now()
+WHERE id = $1`
+
+func (p *postgresqlWebhookRepository) UpdateWebhook(ctx context.Context, id int, webhook model.Webhook) error {
+	recordStatement(ctx, p.logger, updateWebhook)
+
+	result, err := p.connectionPool.ExecContext(ctx, updateWebhook,
+		id, webhook.URL, pq.Array(eventTypeNames(webhook.Events)), webhook.Active)
+	return affectedOne(result, err, ErrWebhookNotFound)
+}
+
+const deleteWebhook = "DELETE FROM webhooks WHERE id = $1"
+
+func (p *postgresqlWebhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	recordStatement(ctx, p.logger, deleteWebhook)
+
+	result, err := p.connectionPool.ExecContext(ctx, deleteWebhook, id)
+	return affectedOne(result, err, ErrWebhookNotFound)
+}
+
+const insertDeliveries = `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at)
+SELECT id, $1, $2, $3, $4
+FROM webhooks
+WHERE active AND (events = '{}' OR $2 = ANY(events))
+ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+func (p *postgresqlWebhookRepository) EnqueueDeliveries(ctx context.Context, event model.Event, payload []byte, now time.Time) (int, error) {
+	recordStatement(ctx, p.logger, insertDeliveries)
+
+	result, err := p.connectionPool.ExecContext(ctx, insertDeliveries, event.ID, string(event.Type), payload, now)
+	if err != nil {
+		return 0, err
+	}
+	enqueued, err := result.RowsAffected()
+	return int(enqueued), err
+}
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+       d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.updated_at`
+
+// The claimed rows are skipped by concurrent claims, and once claimed their next
+// attempt is past now until the lease runs out.
+const claimDueDeliveries = `UPDATE webhook_deliveries d SET next_attempt_at = $2
+FROM webhooks w
+WHERE w.id = d.webhook_id
+  AND d.id IN (SELECT id
+               FROM webhook_deliveries
+               WHERE status = 'pending' AND next_attempt_at <= $1
+               ORDER BY next_attempt_at, id
+               LIMIT $3
+               FOR UPDATE SKIP LOCKED)
+RETURNING ` + deliveryColumns + `, w.url, w.secret`
+
+func (p *postgresqlWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.DueDelivery, error) {
+	recordStatement(ctx, p.logger, claimDueDeliveries)
+
+	rows, err := p.connectionPool.QueryContext(ctx, claimDueDeliveries, now, now.Add(lease), limit)
+	if err != nil {
+		return []model.DueDelivery{}, err
+	}
+	defer rows.Close()
+
+	claimed := make([]model.DueDelivery, 0)
+	for rows.Next() {
+		due := model.DueDelivery{}
+		if err := rows.Scan(append(deliveryFields(&due.Delivery), &due.URL, &due.Secret)...); err != nil {
+			return []model.DueDelivery{}, err
+		}
+		claimed = append(claimed, due)
+	}
+
+	return claimed, rows.Err()
+}
+
+const updateDelivery = `UPDATE webhook_deliveries SET
+    status           = $3,
+    attempts         = $4,
+    next_attempt_at  = $5,
+    last_status_code = $6,
+    last_error       = $7,
+    updated_at       = now()
+WHERE id = $1 AND webhook_id = $2`
+
+func (p *postgresqlWebhookRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	recordStatement(ctx, p.logger, updateDelivery)
+
+	result, err := p.connectionPool.ExecContext(ctx, updateDelivery, delivery.ID, delivery.WebhookID,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError)
+	return affectedOne(result, err, ErrWebhookDeliveryNotFound)
+}
+
+const selectDeliveries = `SELECT ` + deliveryColumns + `
+FROM webhook_deliveries d
+WHERE d.webhook_id = $1 AND ($2 = '' OR d.status = $2)
+ORDER BY d.id DESC
+LIMIT $3`
+
+func (p *postgresqlWebhookRepository) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	recordStatement(ctx, p.logger, selectDeliveries)
+
+	rows, err := p.connectionPool.QueryContext(ctx, selectDeliveries, webhookID, string(status), limit)
+	if err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]model.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery := model.WebhookDelivery{}
+		if err := rows.Scan(deliveryFields(&delivery)...); err != nil {
+			return []model.WebhookDelivery{}, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+
+	// No deliveries may also mean no webhook.
+	if len(deliveries) == 0 {
+		var exists bool
+		err := p.connectionPool.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1)", webhookID).Scan(&exists)
+		if err != nil {
+			return []model.WebhookDelivery{}, err
+		}
+		if !exists {
+			return []model.WebhookDelivery{}, ErrWebhookNotFound
+		}
+	}
+	return deliveries, nil
+}
+
+const redeliverDelivery = `UPDATE webhook_deliveries SET
+    status          = 'pending',
+    attempts        = 0,
+    next_attempt_at = $3,
+    updated_at      = now()
+WHERE id = $1 AND webhook_id = $2`
+
+func (p *postgresqlWebhookRepository) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64, now time.Time) error {
+	recordStatement(ctx, p.logger, redeliverDelivery)
+
+	result, err := p.connectionPool.ExecContext(ctx, redeliverDelivery, deliveryID, webhookID, now)
+	return affectedOne(result, err, ErrWebhookDeliveryNotFound)
+}
+
+func scanWebhook(row scanner) (model.Webhook, error) {
+	webhook := model.Webhook{}
+	var events pq.StringArray
+	err := row.Scan(&webhook.ID, &webhook.URL, &events, &webhook.Secret, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	webhook.Events = make([]model.EventType, 0, len(events))
+	for _, event := range events {
+		webhook.Events = append(webhook.Events, model.EventType(event))
+	}
+	return webhook, nil
+}
+
+// deliveryFields lists the destinations of deliveryColumns.
+func deliveryFields(delivery *model.WebhookDelivery) []interface{} {
+	return []interface{}{&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType,
+		&delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt,
+		&delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.UpdatedAt}
+}
+
+func eventTypeNames(eventTypes []model.EventType) []string {
+	names := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		names = append(names, string(eventType))
+	}
+	return names
+}
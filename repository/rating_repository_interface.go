@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source repository/rating_repository_interface.go -destination repository/mock_rating_repository.go -package repository
+type IRatingRepository interface {
+	GetRatings(ctx context.Context, movieID int) ([]model.Rating, error)
+	// RateMovie stores the rating of a user, replacing an earlier one, and recalculates
+	// the rating aggregate of the movie in the same transaction.
+	RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error)
+	// DeleteRating removes the rating of a user and recalculates the aggregate like
+	// RateMovie.
+	DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error)
+	// GetUserRatings returns the ratings of a user, latest first.
+	GetUserRatings(ctx context.Context, userID int) ([]model.Rating, error)
+	// StreamRatings calls fn for every rating like IMovieRepository.StreamMovies.
+	StreamRatings(ctx context.Context, fn func(rating model.Rating) error) error
+	GetReviews(ctx context.Context, movieID int) ([]model.Review, error)
+	// GetReviewsByMovies returns the reviews of several movies at once, keyed by movie
+	// ID and ordered like GetReviews. Movies without reviews have no key.
+	GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error)
+	AddReview(ctx context.Context, review model.Review) error
+	DeleteReview(ctx context.Context, movieID int, reviewID int) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source repository/recommendation_repository_interface.go -destination repository/mock_recommendation_repository.go -package repository
+type IRecommendationRepository interface {
+	// GetNeighbours returns at most limit neighbours of the movie, most similar first,
+	// and whether they were precomputed at all: a movie created since the last refresh,
+	// or any movie before the first one, has none computed, whereas a movie without
+	// neighbours has none to find.
+	GetNeighbours(ctx context.Context, movieID int, limit int) ([]model.Neighbour, bool, error)
+	// ReplaceNeighbours swaps all precomputed neighbours for neighbours at once, those
+	// of the movies of movieIDs, which may have no neighbours at all.
+	ReplaceNeighbours(ctx context.Context, movieIDs []int, neighbours []model.Neighbour) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// mockgen -source repository/transactor_interface.go -destination repository/mock_transactor.go -package repository
+type ITransactor interface {
+	// InTx runs fn in a transaction that is committed when fn succeeds and rolled back
+	// otherwise. The repositories called with the ctx passed to fn take part in it.
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
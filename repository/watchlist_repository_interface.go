@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source repository/watchlist_repository_interface.go -destination repository/mock_watchlist_repository.go -package repository
+type IWatchlistRepository interface {
+	// GetWatchlist returns the watchlist of a user in position order, with the movies
+	// filled in.
+	GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error)
+	// AddToWatchlist appends the movie to the watchlist, a movie already on it keeps
+	// its position.
+	AddToWatchlist(ctx context.Context, userID int, movieID int) error
+	RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error
+	// ReorderWatchlist gives the movies the positions of their index in movieIDs, which
+	// must list every movie of the watchlist exactly once.
+	ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error
+	// GetWatched returns the watched history of a user, latest first.
+	GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error)
+	// AddWatched records a viewing and takes the movie off the watchlist.
+	AddWatched(ctx context.Context, entry model.WatchedEntry) error
+	DeleteWatched(ctx context.Context, userID int, entryID int) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"time"
+)
+
+// mockgen -source repository/webhook_repository_interface.go -destination repository/mock_webhook_repository.go -package repository
+type IWebhookRepository interface {
+	GetWebhooks(ctx context.Context) ([]model.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (model.Webhook, error)
+	// CreateWebhook returns the webhook with its ID and timestamps.
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int, webhook model.Webhook) error
+	// DeleteWebhook deletes the webhook along with its deliveries.
+	DeleteWebhook(ctx context.Context, id int) error
+	// EnqueueDeliveries creates a pending delivery of the event, due at now, for every
+	// active webhook subscribed to its type and returns how many. An event enqueued
+	// again is skipped by the webhooks that already have it.
+	EnqueueDeliveries(ctx context.Context, event model.Event, payload []byte, now time.Time) (int, error)
+	// ClaimDueDeliveries returns at most limit pending deliveries whose next attempt is
+	// due at now, oldest first, and puts their next attempt off by lease so that they
+	// are not claimed twice while they are sent.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.DueDelivery, error)
+	// UpdateDelivery saves the outcome of an attempt: the status, attempts, next attempt
+	// and last status code and error of delivery.
+	UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error
+	// GetDeliveries returns at most limit deliveries of the webhook, latest first, only
+	// the ones in status unless it is empty.
+	GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error)
+	// RedeliverDelivery makes the delivery pending again with a fresh count of attempts,
+	// the first one due at now.
+	RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64, now time.Time) error
+}
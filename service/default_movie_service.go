@@ -1,73 +1,239 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/dilaragorum/movie-go/events"
 	"github.com/dilaragorum/movie-go/model"
 	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"sort"
 )
 
 var (
 	ErrIDIsNotValid    = errors.New("id is not valid")
 	ErrTitleIsNotEmpty = errors.New("Movie title cannot be empty")
 	ErrMovieNotFound   = errors.New("the movie cannot be found")
+
+	ErrMovieAlreadyExists = errors.New("the movie already exists")
+	ErrMergeIsNotValid    = errors.New("a movie cannot be merged into itself")
 )
 
+var tracer = otel.Tracer("github.com/dilaragorum/movie-go/service")
+
 type DefaultMovieService struct {
-	movieRepo repository.IMovieRepository
+	movieRepo   repository.IMovieRepository
+	transactor  repository.ITransactor
+	outbox      repository.IOutboxRepository
+	broadcaster *events.Broadcaster
+	logger      *slog.Logger
 }
 
-func NewDefaultMovieService(mRepo repository.IMovieRepository) *DefaultMovieService {
+// NewDefaultMovieService writes a model.Event to outbox for every change of the movies,
+// in the same transaction of transactor as the change. Once the change is committed
+// the event also goes to broadcaster, which feeds the live clients of this instance.
+func NewDefaultMovieService(mRepo repository.IMovieRepository, transactor repository.ITransactor, outbox repository.IOutboxRepository, broadcaster *events.Broadcaster, logger *slog.Logger) *DefaultMovieService {
 	return &DefaultMovieService{
-		movieRepo: mRepo,
+		movieRepo:   mRepo,
+		transactor:  transactor,
+		outbox:      outbox,
+		broadcaster: broadcaster,
+		logger:      logger,
 	}
 }
 
-func (d *DefaultMovieService) GetMovies() ([]model.Movie, error) {
-	return d.movieRepo.GetMovies()
+func (d *DefaultMovieService) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.GetMovies")
+	defer span.End()
+
+	return d.movieRepo.GetMovies(ctx, filter)
+}
+
+func (d *DefaultMovieService) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.StreamMovies")
+	defer span.End()
+
+	return d.movieRepo.StreamMovies(ctx, filter, fn)
 }
 
-func (d *DefaultMovieService) GetMovie(id int) (model.Movie, error) {
+func (d *DefaultMovieService) GetMovie(ctx context.Context, id int) (model.Movie, error) {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.GetMovie")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", id))
+
 	if id <= 0 {
 		return model.Movie{}, ErrIDIsNotValid
 	}
-	movie, err := d.movieRepo.GetMovie(id)
+	movie, err := d.movieRepo.GetMovie(ctx, id)
 
 	if err != nil {
 		if errors.Is(err, repository.ErrMovieNotFound) {
 			return model.Movie{}, ErrMovieNotFound
 		}
+		return model.Movie{}, err
 	}
 	return movie, nil
 }
 
-func (d *DefaultMovieService) CreateMovie(movie model.Movie) error {
+func (d *DefaultMovieService) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.GetMovieStats")
+	defer span.End()
+	span.SetAttributes(attribute.Int("top", topN))
+
+	if topN < 1 || topN > MaxLimit {
+		return model.MovieStats{}, ErrLimitIsNotValid
+	}
+
+	return d.movieRepo.GetMovieStats(ctx, filter, topN)
+}
+
+func (d *DefaultMovieService) CreateMovie(ctx context.Context, movie model.Movie) ([]model.Duplicate, error) {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.CreateMovie")
+	defer span.End()
+
 	if movie.Title == "" {
-		return ErrTitleIsNotEmpty
+		return nil, ErrTitleIsNotEmpty
+	}
+
+	movie, err := normalizeMovie(movie)
+	if err != nil {
+		return nil, err
 	}
-	return d.movieRepo.CreateMovie(movie)
+
+	var duplicates []model.Duplicate
+	var changes []model.Event
+	err = d.transactor.InTx(ctx, func(ctx context.Context) error {
+		// The lock keeps a concurrent creation of the same movie from slipping in
+		// between the check and the insert.
+		if err := d.movieRepo.LockMovieTitle(ctx, titleKey(movie.Title), movie.ReleaseYear); err != nil {
+			return err
+		}
+		duplicates, err = d.findDuplicates(ctx, movie)
+		if err != nil {
+			return err
+		}
+		for _, duplicate := range duplicates {
+			if duplicate.Exact {
+				return fmt.Errorf("%w with id %d", ErrMovieAlreadyExists, duplicate.Movie.ID)
+			}
+		}
+
+		id, err := d.movieRepo.CreateMovie(ctx, movie)
+		if err != nil {
+			return err
+		}
+		created, err := d.movieEvent(ctx, model.EventMovieCreated, id)
+		if err != nil {
+			return err
+		}
+		changes = []model.Event{created}
+		return d.outbox.AppendEvents(ctx, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.broadcast(changes)
+
+	if len(duplicates) > 0 {
+		d.logger.WarnContext(ctx, "movie created with near duplicates", "title", movie.Title, "duplicates", len(duplicates))
+	} else {
+		d.logger.InfoContext(ctx, "movie created", "title", movie.Title)
+	}
+	return duplicates, nil
+}
+
+// findDuplicates looks for movie among the ones released within a year of it, or
+// among all the movies when its release year is unknown. The most similar come first.
+func (d *DefaultMovieService) findDuplicates(ctx context.Context, movie model.Movie) ([]model.Duplicate, error) {
+	var filter model.MovieFilter
+	if movie.ReleaseYear != 0 {
+		filter.YearFrom, filter.YearTo = movie.ReleaseYear-1, movie.ReleaseYear+1
+	}
+
+	key := titleKey(movie.Title)
+	var duplicates []model.Duplicate
+	err := d.movieRepo.StreamMovies(ctx, filter, func(existing model.Movie) error {
+		if duplicate, ok := duplicateOf(movie, key, existing); ok {
+			duplicates = append(duplicates, duplicate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(duplicates, func(a, b int) bool {
+		if duplicates[a].Exact != duplicates[b].Exact {
+			return duplicates[a].Exact
+		}
+		return duplicates[a].Similarity > duplicates[b].Similarity
+	})
+	return duplicates, nil
 }
 
-func (d *DefaultMovieService) DeleteMovie(id int) error {
+func (d *DefaultMovieService) DeleteMovie(ctx context.Context, id int) error {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.DeleteMovie")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", id))
+
 	if id <= 0 {
 		return ErrIDIsNotValid
 	}
 
-	err := d.movieRepo.DeleteMovie(id)
+	var changes []model.Event
+	err := d.transactor.InTx(ctx, func(ctx context.Context) error {
+		if err := d.movieRepo.DeleteMovie(ctx, id); err != nil {
+			return err
+		}
+		changes = []model.Event{model.NewMovieDeletedEvent(id)}
+		return d.outbox.AppendEvents(ctx, changes)
+	})
 	if err != nil {
 		if errors.Is(err, repository.ErrMovieNotFound) {
 			return ErrMovieNotFound
 		}
 		return err
 	}
+	d.broadcast(changes)
 
+	d.logger.InfoContext(ctx, "movie deleted", "movie_id", id)
 	return nil
 }
 
-func (d *DefaultMovieService) DeleteAllMovie() error {
-	return d.movieRepo.DeleteAllMovies()
+func (d *DefaultMovieService) DeleteAllMovie(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.DeleteAllMovie")
+	defer span.End()
+
+	var changes []model.Event
+	err := d.transactor.InTx(ctx, func(ctx context.Context) error {
+		ids, err := d.movieRepo.DeleteAllMovies(ctx)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		changes = make([]model.Event, 0, len(ids))
+		for _, id := range ids {
+			changes = append(changes, model.NewMovieDeletedEvent(id))
+		}
+		return d.outbox.AppendEvents(ctx, changes)
+	})
+	if err != nil {
+		return err
+	}
+	d.broadcast(changes)
+
+	d.logger.WarnContext(ctx, "all movies deleted")
+	return nil
 }
 
-func (d *DefaultMovieService) UpdateMovie(id int, movie model.Movie) error {
+func (d *DefaultMovieService) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.UpdateMovie")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", id))
+
 	if id <= 0 {
 		return ErrIDIsNotValid
 	}
@@ -76,10 +242,85 @@ func (d *DefaultMovieService) UpdateMovie(id int, movie model.Movie) error {
 		return ErrTitleIsNotEmpty
 	}
 
-	err := d.movieRepo.UpdateMovie(id, movie)
-	if errors.Is(err, repository.ErrMovieNotFound) {
-		return ErrMovieNotFound
+	movie, err := normalizeMovie(movie)
+	if err != nil {
+		return err
 	}
 
+	var changes []model.Event
+	err = d.transactor.InTx(ctx, func(ctx context.Context) error {
+		if err := d.movieRepo.UpdateMovie(ctx, id, movie); err != nil {
+			return err
+		}
+		updated, err := d.movieEvent(ctx, model.EventMovieUpdated, id)
+		if err != nil {
+			return err
+		}
+		changes = []model.Event{updated}
+		return d.outbox.AppendEvents(ctx, changes)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrMovieNotFound) {
+			return ErrMovieNotFound
+		}
+		return err
+	}
+	d.broadcast(changes)
+
+	d.logger.InfoContext(ctx, "movie updated", "movie_id", id)
 	return nil
 }
+
+func (d *DefaultMovieService) MergeMovies(ctx context.Context, targetID int, sourceID int) (model.Movie, error) {
+	ctx, span := tracer.Start(ctx, "DefaultMovieService.MergeMovies")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", targetID), attribute.Int("movie.source_id", sourceID))
+
+	if targetID <= 0 || sourceID <= 0 {
+		return model.Movie{}, ErrIDIsNotValid
+	}
+	if targetID == sourceID {
+		return model.Movie{}, ErrMergeIsNotValid
+	}
+
+	var changes []model.Event
+	err := d.transactor.InTx(ctx, func(ctx context.Context) error {
+		if err := d.movieRepo.MergeMovies(ctx, targetID, sourceID); err != nil {
+			return err
+		}
+		merged, err := d.movieEvent(ctx, model.EventMovieUpdated, targetID)
+		if err != nil {
+			return err
+		}
+		changes = []model.Event{merged, model.NewMovieDeletedEvent(sourceID)}
+		return d.outbox.AppendEvents(ctx, changes)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrMovieNotFound) {
+			return model.Movie{}, ErrMovieNotFound
+		}
+		return model.Movie{}, err
+	}
+	d.broadcast(changes)
+
+	d.logger.InfoContext(ctx, "movies merged", "movie_id", targetID, "source_id", sourceID)
+	return d.GetMovie(ctx, targetID)
+}
+
+// movieEvent reads the movie as changed within the transaction of ctx and returns an
+// event carrying it.
+func (d *DefaultMovieService) movieEvent(ctx context.Context, eventType model.EventType, id int) (model.Event, error) {
+	movie, err := d.movieRepo.GetMovie(ctx, id)
+	if err != nil {
+		return model.Event{}, err
+	}
+	return model.NewMovieEvent(eventType, movie), nil
+}
+
+// broadcast hands the committed changes, with the IDs the outbox gave them, to the
+// live feed.
+func (d *DefaultMovieService) broadcast(changes []model.Event) {
+	for _, change := range changes {
+		d.broadcaster.Broadcast(change)
+	}
+}
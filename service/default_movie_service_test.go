@@ -1,13 +1,41 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/logging"
 	"github.com/dilaragorum/movie-go/model"
 	"github.com/dilaragorum/movie-go/repository"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
+func newTestBroadcaster() *events.Broadcaster {
+	return events.NewBroadcaster(events.DefaultHistory, events.DefaultSubscriberBuffer)
+}
+
+// newTestMovieService writes the events to an in-memory outbox.
+func newTestMovieService(mRepo repository.IMovieRepository) *DefaultMovieService {
+	return NewDefaultMovieService(mRepo, repository.NewInMemoryTransactor(), repository.NewInMemoryOutboxRepository(logging.NewNop()), newTestBroadcaster(), logging.NewNop())
+}
+
+// slowStreamRepository widens the window between the duplicate check and the insert
+// of a movie.
+type slowStreamRepository struct {
+	repository.IMovieRepository
+}
+
+func (s slowStreamRepository) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error {
+	err := s.IMovieRepository.StreamMovies(ctx, filter, fn)
+	time.Sleep(10 * time.Millisecond)
+	return err
+}
+
 func TestDefaultMovieService_GetMovie(t *testing.T) {
 	t.Run("Error getMovie - ErrIDIsNotValid", func(t *testing.T) {
 		type testCase struct {
@@ -20,8 +48,8 @@ func TestDefaultMovieService_GetMovie(t *testing.T) {
 		}
 
 		for _, test := range testCases {
-			dms := NewDefaultMovieService(nil)
-			_, err := dms.GetMovie(test.id)
+			dms := newTestMovieService(nil)
+			_, err := dms.GetMovie(context.Background(), test.id)
 			assert.ErrorIs(t, err, ErrIDIsNotValid)
 		}
 	})
@@ -29,57 +57,261 @@ func TestDefaultMovieService_GetMovie(t *testing.T) {
 		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
 		mockRepository.
 			EXPECT().
-			GetMovie(6).
+			GetMovie(gomock.Any(), 6).
 			Return(model.Movie{}, repository.ErrMovieNotFound).
 			Times(1)
 
-		dms := NewDefaultMovieService(mockRepository)
-		_, err := dms.GetMovie(6)
+		dms := newTestMovieService(mockRepository)
+		_, err := dms.GetMovie(context.Background(), 6)
 
 		assert.ErrorIs(t, err, ErrMovieNotFound)
 
 	})
+	t.Run("Error getMovie - repository failure", func(t *testing.T) {
+		failure := errors.New("connection refused")
+		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().
+			GetMovie(gomock.Any(), 6).
+			Return(model.Movie{}, failure).
+			Times(1)
+
+		dms := newTestMovieService(mockRepository)
+		_, err := dms.GetMovie(context.Background(), 6)
+
+		assert.ErrorIs(t, err, failure)
+	})
 
 }
 
 func TestDefaultMovieService_CreateMovie(t *testing.T) {
+	t.Run("Error Create Movie - invalid details", func(t *testing.T) {
+		testCases := []struct {
+			movie model.Movie
+			err   error
+		}{
+			{movie: model.Movie{Title: "Film", Genres: []string{"drama", " "}}, err: ErrGenreIsNotValid},
+			{movie: model.Movie{Title: "Film", RuntimeMinutes: -1}, err: ErrRuntimeIsNotValid},
+			{movie: model.Movie{Title: "Film", Synopsis: strings.Repeat("a", 2001)}, err: ErrSynopsisIsTooLong},
+			{movie: model.Movie{Title: "Film", OriginalLanguage: "eng"}, err: ErrLanguageIsNotValid},
+			{movie: model.Movie{Title: "Film", Country: "U1"}, err: ErrCountryIsNotValid},
+			{movie: model.Movie{Title: "Film", AgeRating: "X"}, err: ErrAgeRatingIsNotValid},
+			{movie: model.Movie{Title: "Film", PosterURL: "/posters/1.jpg"}, err: ErrPosterURLIsNotValid},
+		}
+
+		for _, test := range testCases {
+			dms := newTestMovieService(nil)
+			_, err := dms.CreateMovie(context.Background(), test.movie)
+			assert.ErrorIs(t, err, test.err)
+			assert.True(t, IsValidationError(err))
+		}
+	})
+	t.Run("Success Create Movie - details are normalized", func(t *testing.T) {
+		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockRepository.EXPECT().LockMovieTitle(gomock.Any(), gomock.Any(), 0).Return(nil)
+		mockRepository.EXPECT().StreamMovies(gomock.Any(), model.MovieFilter{}, gomock.Any()).Return(nil)
+		mockRepository.
+			EXPECT().CreateMovie(gomock.Any(), model.Movie{
+			Title:            "Film",
+			Genres:           []string{"drama", "crime"},
+			OriginalLanguage: "en",
+			Country:          "US",
+			AgeRating:        "PG-13",
+			PosterURL:        "https://example.com/1.jpg",
+		}).
+			Return(4, nil).
+			Times(1)
+		mockRepository.EXPECT().GetMovie(gomock.Any(), 4).Return(model.Movie{ID: 4, Title: "Film"}, nil)
+
+		dms := newTestMovieService(mockRepository)
+		_, err := dms.CreateMovie(context.Background(), model.Movie{
+			Title:            "Film",
+			Genres:           []string{" Drama", "crime", "DRAMA"},
+			OriginalLanguage: "EN",
+			Country:          "us",
+			AgeRating:        "pg-13",
+			PosterURL:        "https://example.com/1.jpg",
+		})
+
+		assert.Nil(t, err)
+	})
 	t.Run("Error Create Movie - ErrTitleIsNotEmpty", func(t *testing.T) {
-		dms := NewDefaultMovieService(nil)
-		err := dms.CreateMovie(model.Movie{Title: ""})
+		dms := newTestMovieService(nil)
+		_, err := dms.CreateMovie(context.Background(), model.Movie{Title: ""})
 		assert.ErrorIs(t, err, ErrTitleIsNotEmpty)
 	})
 	t.Run("Success Create Movie", func(t *testing.T) {
 		movie := model.Movie{Title: "Test Movie"}
 		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockRepository.EXPECT().LockMovieTitle(gomock.Any(), gomock.Any(), 0).Return(nil)
+		mockRepository.EXPECT().StreamMovies(gomock.Any(), model.MovieFilter{}, gomock.Any()).Return(nil)
 		mockRepository.
-			EXPECT().CreateMovie(movie).
-			Return(nil).
+			EXPECT().CreateMovie(gomock.Any(), movie).
+			Return(4, nil).
 			Times(1)
+		mockRepository.EXPECT().GetMovie(gomock.Any(), 4).Return(model.Movie{ID: 4, Title: "Test Movie"}, nil)
+
+		ms := newTestMovieService(mockRepository)
+		duplicates, err := ms.CreateMovie(context.Background(), movie)
+
+		assert.Nil(t, err)
+		assert.Empty(t, duplicates)
+	})
+	t.Run("Error Create Movie - ErrMovieAlreadyExists", func(t *testing.T) {
+		dms := newTestMovieService(repository.NewInMemoryMovieRepository(logging.NewNop()))
+
+		for _, title := range []string{"The Godfather", "the godfather", "The  Godfather!", "Thé Gödfather"} {
+			_, err := dms.CreateMovie(context.Background(), model.Movie{Title: title, ReleaseYear: 1972})
+			assert.ErrorIs(t, err, ErrMovieAlreadyExists, title)
+			assert.Contains(t, err.Error(), "with id 2")
+		}
+
+		movies, _ := dms.GetMovies(context.Background(), model.MovieFilter{})
+		assert.Len(t, movies, 3)
+	})
+	t.Run("Error Create Movie - concurrent duplicates, only one is created", func(t *testing.T) {
+		dms := newTestMovieService(slowStreamRepository{repository.NewInMemoryMovieRepository(logging.NewNop())})
+
+		var wg sync.WaitGroup
+		errs := make([]error, 8)
+		for k := range errs {
+			wg.Add(1)
+			go func(k int) {
+				defer wg.Done()
+				_, errs[k] = dms.CreateMovie(context.Background(), model.Movie{Title: "Heat", ReleaseYear: 1995})
+			}(k)
+		}
+		wg.Wait()
+
+		created := 0
+		for _, err := range errs {
+			if err == nil {
+				created++
+			} else {
+				assert.ErrorIs(t, err, ErrMovieAlreadyExists)
+			}
+		}
+		assert.Equal(t, 1, created)
+	})
+	t.Run("Success Create Movie - ids of deleted movies are not reused", func(t *testing.T) {
+		dms := newTestMovieService(repository.NewInMemoryMovieRepository(logging.NewNop()))
+
+		assert.Nil(t, dms.DeleteMovie(context.Background(), 3))
+		_, err := dms.CreateMovie(context.Background(), model.Movie{Title: "Heat", ReleaseYear: 1995})
+		assert.Nil(t, err)
 
-		ms := NewDefaultMovieService(mockRepository)
-		err := ms.CreateMovie(movie)
+		movies, _ := dms.GetMovies(context.Background(), model.MovieFilter{})
+		assert.Equal(t, 4, movies[len(movies)-1].ID)
+	})
+	t.Run("Success Create Movie - near duplicates", func(t *testing.T) {
+		dms := newTestMovieService(repository.NewInMemoryMovieRepository(logging.NewNop()))
+
+		duplicates, err := dms.CreateMovie(context.Background(), model.Movie{Title: "The Godfather", ReleaseYear: 1973})
+		assert.Nil(t, err)
+		assert.Len(t, duplicates, 1)
+		assert.Equal(t, 2, duplicates[0].Movie.ID)
+		assert.Equal(t, 1.0, duplicates[0].Similarity)
+		assert.False(t, duplicates[0].Exact)
 
+		duplicates, err = dms.CreateMovie(context.Background(), model.Movie{Title: "The Dark Knigth", ReleaseYear: 2008})
 		assert.Nil(t, err)
+		assert.Len(t, duplicates, 1)
+		assert.Equal(t, 3, duplicates[0].Movie.ID)
+		assert.Less(t, duplicates[0].Similarity, 1.0)
+
+		duplicates, err = dms.CreateMovie(context.Background(), model.Movie{Title: "The Dark Knight Rises", ReleaseYear: 2012})
+		assert.Nil(t, err)
+		assert.Empty(t, duplicates)
 	})
 
 }
 
+func TestTitleKey(t *testing.T) {
+	testCases := map[string]string{
+		"The Godfather":           "the godfather",
+		"  The   GODFATHER ":      "the godfather",
+		"Amélie":                  "amelie",
+		"Spider-Man: No Way Home": "spider man no way home",
+		"WALL·E":                  "walle",
+		"Léon: The Professional":  "leon the professional",
+	}
+
+	for title, key := range testCases {
+		assert.Equal(t, key, titleKey(title), title)
+	}
+}
+
+func TestDefaultMovieService_MergeMovies(t *testing.T) {
+	t.Run("Error Merge Movies - invalid ids", func(t *testing.T) {
+		dms := newTestMovieService(nil)
+
+		_, err := dms.MergeMovies(context.Background(), 0, 2)
+		assert.ErrorIs(t, err, ErrIDIsNotValid)
+
+		_, err = dms.MergeMovies(context.Background(), 2, 2)
+		assert.ErrorIs(t, err, ErrMergeIsNotValid)
+		assert.True(t, IsValidationError(err))
+	})
+	t.Run("Error Merge Movies - ErrMovieNotFound", func(t *testing.T) {
+		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockRepository.EXPECT().MergeMovies(gomock.Any(), 2, 9).Return(repository.ErrMovieNotFound).Times(1)
+
+		dms := newTestMovieService(mockRepository)
+		_, err := dms.MergeMovies(context.Background(), 2, 9)
+
+		assert.ErrorIs(t, err, ErrMovieNotFound)
+	})
+	t.Run("Success Merge Movies", func(t *testing.T) {
+		movieRepo := repository.NewInMemoryMovieRepository(logging.NewNop())
+		ratingRepo := repository.NewInMemoryRatingRepository(movieRepo, logging.NewNop())
+		watchlistRepo := repository.NewInMemoryWatchlistRepository(movieRepo, logging.NewNop())
+		dms := newTestMovieService(movieRepo)
+		ctx := context.Background()
+
+		_, err := dms.CreateMovie(ctx, model.Movie{
+			Title: "Godfather, The", ReleaseYear: 1972, Genres: []string{"drama", "family"}, Synopsis: "An offer.",
+		})
+		assert.Nil(t, err)
+		for _, rating := range []model.Rating{{MovieID: 2, UserID: 1, Value: 10}, {MovieID: 4, UserID: 1, Value: 2}, {MovieID: 4, UserID: 2, Value: 8}} {
+			_, err := ratingRepo.RateMovie(ctx, rating)
+			assert.Nil(t, err)
+		}
+		assert.Nil(t, watchlistRepo.AddToWatchlist(ctx, 1, 4))
+
+		movie, err := dms.MergeMovies(ctx, 2, 4)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "The Godfather", movie.Title)
+		assert.Equal(t, []string{"crime", "drama", "family"}, movie.Genres)
+		assert.Equal(t, "An offer.", movie.Synopsis)
+		assert.Equal(t, 2, movie.RatingCount)
+		assert.Equal(t, 9.0, movie.RatingMean)
+
+		_, err = dms.GetMovie(ctx, 4)
+		assert.ErrorIs(t, err, ErrMovieNotFound)
+
+		items, _ := watchlistRepo.GetWatchlist(ctx, 1)
+		assert.Len(t, items, 1)
+		assert.Equal(t, 2, items[0].MovieID)
+	})
+}
+
 func TestDefaultMovieService_DeleteMovie(t *testing.T) {
 	t.Run("Error Delete Movie - ErrIDIsNotValid", func(t *testing.T) {
-		dms := NewDefaultMovieService(nil)
-		err := dms.DeleteMovie(0)
+		dms := newTestMovieService(nil)
+		err := dms.DeleteMovie(context.Background(), 0)
 		assert.ErrorIs(t, err, ErrIDIsNotValid)
 	})
 	t.Run("Error Delete Movie - ErrMovieNotFound", func(t *testing.T) {
 		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
 		mockRepository.
 			EXPECT().
-			DeleteMovie(6).
+			DeleteMovie(gomock.Any(), 6).
 			Return(repository.ErrMovieNotFound).
 			Times(1)
 
-		ms := NewDefaultMovieService(mockRepository)
-		err := ms.DeleteMovie(6)
+		ms := newTestMovieService(mockRepository)
+		err := ms.DeleteMovie(context.Background(), 6)
 		assert.ErrorIs(t, err, ErrMovieNotFound)
 	})
 
@@ -87,13 +319,13 @@ func TestDefaultMovieService_DeleteMovie(t *testing.T) {
 
 func TestDefaultMovieService_UpdateMovie(t *testing.T) {
 	t.Run("Error Update Movie - IDIsNotValid", func(t *testing.T) {
-		ms := NewDefaultMovieService(nil)
-		err := ms.UpdateMovie(0, model.Movie{Title: ""})
+		ms := newTestMovieService(nil)
+		err := ms.UpdateMovie(context.Background(), 0, model.Movie{Title: ""})
 		assert.ErrorIs(t, err, ErrIDIsNotValid)
 	})
 	t.Run("Error Update Movie - ErrTitleIsNotEmpty", func(t *testing.T) {
-		ms := NewDefaultMovieService(nil)
-		err := ms.UpdateMovie(3, model.Movie{Title: ""})
+		ms := newTestMovieService(nil)
+		err := ms.UpdateMovie(context.Background(), 3, model.Movie{Title: ""})
 		assert.ErrorIs(t, err, ErrTitleIsNotEmpty)
 	})
 	t.Run("Error Update Movie - ErrMovieNotFound", func(t *testing.T) {
@@ -101,12 +333,12 @@ func TestDefaultMovieService_UpdateMovie(t *testing.T) {
 		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
 		mockRepository.
 			EXPECT().
-			UpdateMovie(6, movie).
+			UpdateMovie(gomock.Any(), 6, movie).
 			Return(repository.ErrMovieNotFound).
 			Times(1)
 
-		ms := NewDefaultMovieService(mockRepository)
-		err := ms.UpdateMovie(6, movie)
+		ms := newTestMovieService(mockRepository)
+		err := ms.UpdateMovie(context.Background(), 6, movie)
 
 		assert.ErrorIs(t, err, ErrMovieNotFound)
 	})
@@ -116,13 +348,134 @@ func TestDefaultMovieService_UpdateMovie(t *testing.T) {
 		mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
 		mockRepository.
 			EXPECT().
-			UpdateMovie(2, movie).
+			UpdateMovie(gomock.Any(), 2, movie).
 			Return(nil).
 			Times(1)
+		mockRepository.EXPECT().GetMovie(gomock.Any(), 2).Return(model.Movie{ID: 2, Title: "Test Movie"}, nil)
+
+		ms := newTestMovieService(mockRepository)
+		err := ms.UpdateMovie(context.Background(), 2, movie)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestDefaultMovieService_Events(t *testing.T) {
+	t.Run("Every change appends its events and broadcasts them", func(t *testing.T) {
+		movieRepo := repository.NewInMemoryMovieRepository(logging.NewNop())
+		outbox := repository.NewInMemoryOutboxRepository(logging.NewNop())
+		broadcaster := newTestBroadcaster()
+		subscription, _ := broadcaster.Subscribe(0)
+		dms := NewDefaultMovieService(movieRepo, repository.NewInMemoryTransactor(), outbox, broadcaster, logging.NewNop())
+		ctx := context.Background()
+
+		_, err := dms.CreateMovie(ctx, model.Movie{Title: "Heat", ReleaseYear: 1995, Genres: []string{"Crime"}})
+		assert.Nil(t, err)
+		assert.Nil(t, dms.UpdateMovie(ctx, 4, model.Movie{Title: "Heat", RuntimeMinutes: 170}))
+		_, err = dms.MergeMovies(ctx, 2, 4)
+		assert.Nil(t, err)
+		assert.Nil(t, dms.DeleteMovie(ctx, 1))
+		assert.Nil(t, dms.DeleteAllMovie(ctx))
+
+		events, _ := outbox.ClaimPendingEvents(ctx, 100, time.Minute)
+		var types []model.EventType
+		var movieIDs []int
+		for k, event := range events {
+			assert.Equal(t, int64(k+1), event.ID)
+			types = append(types, event.Type)
+			movieIDs = append(movieIDs, event.MovieID)
+		}
+		assert.Equal(t, []model.EventType{
+			model.EventMovieCreated, model.EventMovieUpdated,
+			model.EventMovieUpdated, model.EventMovieDeleted,
+			model.EventMovieDeleted,
+			model.EventMovieDeleted, model.EventMovieDeleted,
+		}, types)
+		assert.Equal(t, []int{4, 4, 2, 4, 1, 2, 3}, movieIDs)
+
+		assert.Equal(t, []string{"crime"}, events[0].Movie.Genres)
+		assert.Equal(t, 170, events[1].Movie.RuntimeMinutes)
+		assert.Equal(t, "The Godfather", events[2].Movie.Title)
+		assert.Nil(t, events[3].Movie)
+
+		subscription.Close()
+		var broadcast []model.Event
+		for event := range subscription.Events() {
+			broadcast = append(broadcast, event)
+		}
+		assert.Equal(t, events, broadcast)
+	})
+	t.Run("Failed change - no event", func(t *testing.T) {
+		outbox := repository.NewInMemoryOutboxRepository(logging.NewNop())
+		broadcaster := newTestBroadcaster()
+		subscription, _ := broadcaster.Subscribe(0)
+		dms := NewDefaultMovieService(repository.NewInMemoryMovieRepository(logging.NewNop()),
+			repository.NewInMemoryTransactor(), outbox, broadcaster, logging.NewNop())
+
+		assert.ErrorIs(t, dms.DeleteMovie(context.Background(), 9), ErrMovieNotFound)
+
+		events, _ := outbox.ClaimPendingEvents(context.Background(), 100, time.Minute)
+		assert.Empty(t, events)
+		assert.Empty(t, subscription.Events())
+	})
+	t.Run("Failed event - the change fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepository := repository.NewMockIMovieRepository(ctrl)
+		mockRepository.EXPECT().DeleteMovie(gomock.Any(), 1).Return(nil)
+		mockOutbox := repository.NewMockIOutboxRepository(ctrl)
+		mockOutbox.EXPECT().AppendEvents(gomock.Any(), gomock.Any()).Return(errors.New("oops!"))
+
+		dms := NewDefaultMovieService(mockRepository, repository.NewInMemoryTransactor(), mockOutbox, newTestBroadcaster(), logging.NewNop())
+
+		assert.EqualError(t, dms.DeleteMovie(context.Background(), 1), "oops!")
+	})
+}
+
+func TestDefaultMovieService_GetMovieStats(t *testing.T) {
+	t.Run("Error GetMovieStats - ErrLimitIsNotValid", func(t *testing.T) {
+		dms := newTestMovieService(nil)
+
+		for _, topN := range []int{0, MaxLimit + 1} {
+			_, err := dms.GetMovieStats(context.Background(), model.MovieFilter{}, topN)
+			assert.ErrorIs(t, err, ErrLimitIsNotValid)
+		}
+	})
+	t.Run("Success GetMovieStats - in-memory catalog", func(t *testing.T) {
+		dms := newTestMovieService(repository.NewInMemoryMovieRepository(logging.NewNop()))
+
+		stats, err := dms.GetMovieStats(context.Background(), model.MovieFilter{}, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, stats.Count)
+		assert.Equal(t, 9.17, stats.AverageScore)
+		assert.Equal(t, []model.GenreStats{
+			{Genre: "action", Count: 1, AverageScore: 9},
+			{Genre: "crime", Count: 2, AverageScore: 9.1},
+			{Genre: "drama", Count: 3, AverageScore: 9.17},
+		}, stats.ByGenre)
+		assert.Equal(t, []model.PeriodStats{
+			{Year: 1970, Count: 1, AverageScore: 9.2},
+			{Year: 1990, Count: 1, AverageScore: 9.3},
+			{Year: 2000, Count: 1, AverageScore: 9},
+		}, stats.ByDecade)
+		assert.Len(t, stats.ByYear, 3)
+		assert.Len(t, stats.ScoreHistogram, model.ScoreBuckets)
+		assert.Equal(t, model.ScoreBucket{From: 9, To: 10, Count: 3}, stats.ScoreHistogram[9])
+
+		assert.Len(t, stats.Top, 2)
+		assert.Equal(t, 1, stats.Top[0].ID)
+		assert.Equal(t, 2, stats.Top[1].ID)
+		assert.Len(t, stats.TopByDecade, 3)
+		assert.Equal(t, 1970, stats.TopByDecade[0].Decade)
+		assert.Equal(t, 2, stats.TopByDecade[0].Movies[0].ID)
+	})
+	t.Run("Success GetMovieStats - filtered", func(t *testing.T) {
+		dms := newTestMovieService(repository.NewInMemoryMovieRepository(logging.NewNop()))
 
-		ms := NewDefaultMovieService(mockRepository)
-		err := ms.UpdateMovie(2, movie)
+		stats, err := dms.GetMovieStats(context.Background(), model.MovieFilter{Genre: "crime"}, 10)
 
 		assert.Nil(t, err)
+		assert.Equal(t, 2, stats.Count)
+		assert.Equal(t, 9.1, stats.AverageScore)
 	})
 }
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"strings"
+)
+
+var (
+	ErrNameIsNotEmpty         = errors.New("person name cannot be empty")
+	ErrRoleIsNotValid         = errors.New("role must be one of director, writer, producer, actor, composer, crew")
+	ErrCharacterIsNotValid    = errors.New("only actors can play a character")
+	ErrBillingOrderIsNotValid = errors.New("billing order cannot be negative")
+	ErrPersonNotFound         = errors.New("the person cannot be found")
+	ErrCreditNotFound         = errors.New("the credit cannot be found")
+)
+
+var roles = map[string]bool{
+	model.RoleDirector: true,
+	model.RoleWriter:   true,
+	model.RoleProducer: true,
+	model.RoleActor:    true,
+	model.RoleComposer: true,
+	model.RoleCrew:     true,
+}
+
+type DefaultPersonService struct {
+	personRepo repository.IPersonRepository
+	movieRepo  repository.IMovieRepository
+	logger     *slog.Logger
+}
+
+// NewDefaultPersonService needs the movie repository to reject credits and credit
+// lookups for movies that do not exist.
+func NewDefaultPersonService(pRepo repository.IPersonRepository, mRepo repository.IMovieRepository, logger *slog.Logger) *DefaultPersonService {
+	return &DefaultPersonService{
+		personRepo: pRepo,
+		movieRepo:  mRepo,
+		logger:     logger,
+	}
+}
+
+func (d *DefaultPersonService) GetPeople(ctx context.Context) ([]model.Person, error) {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.GetPeople")
+	defer span.End()
+
+	return d.personRepo.GetPeople(ctx)
+}
+
+func (d *DefaultPersonService) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.GetPerson")
+	defer span.End()
+	span.SetAttributes(attribute.Int("person.id", id))
+
+	if id <= 0 {
+		return model.Person{}, ErrIDIsNotValid
+	}
+
+	person, err := d.personRepo.GetPerson(ctx, id)
+	if err != nil {
+		return model.Person{}, personError(err)
+	}
+	return person, nil
+}
+
+func (d *DefaultPersonService) CreatePerson(ctx context.Context, person model.Person) error {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.CreatePerson")
+	defer span.End()
+
+	person.Name = strings.TrimSpace(person.Name)
+	if person.Name == "" {
+		return ErrNameIsNotEmpty
+	}
+
+	if err := d.personRepo.CreatePerson(ctx, person); err != nil {
+		return err
+	}
+
+	d.logger.InfoContext(ctx, "person created", "name", person.Name)
+	return nil
+}
+
+func (d *DefaultPersonService) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.UpdatePerson")
+	defer span.End()
+	span.SetAttributes(attribute.Int("person.id", id))
+
+	if id <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	person.Name = strings.TrimSpace(person.Name)
+	if person.Name == "" {
+		return ErrNameIsNotEmpty
+	}
+
+	if err := d.personRepo.UpdatePerson(ctx, id, person); err != nil {
+		return personError(err)
+	}
+
+	d.logger.InfoContext(ctx, "person updated", "person_id", id)
+	return nil
+}
+
+func (d *DefaultPersonService) DeletePerson(ctx context.Context, id int) error {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.DeletePerson")
+	defer span.End()
+	span.SetAttributes(attribute.Int("person.id", id))
+
+	if id <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	if err := d.personRepo.DeletePerson(ctx, id); err != nil {
+		return personError(err)
+	}
+
+	d.logger.InfoContext(ctx, "person deleted", "person_id", id)
+	return nil
+}
+
+func (d *DefaultPersonService) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.GetMovieCredits")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", movieID))
+
+	if err := checkMovie(ctx, d.movieRepo, movieID); err != nil {
+		return nil, err
+	}
+
+	return d.personRepo.GetMovieCredits(ctx, movieID)
+}
+
+func (d *DefaultPersonService) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.GetCreditsByMovies")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.count", len(movieIDs)))
+
+	if len(movieIDs) == 0 {
+		return map[int][]model.Credit{}, nil
+	}
+	return d.personRepo.GetCreditsByMovies(ctx, movieIDs)
+}
+
+func (d *DefaultPersonService) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.GetPersonMovies")
+	defer span.End()
+	span.SetAttributes(attribute.Int("person.id", personID), attribute.String("credit.role", role))
+
+	if personID <= 0 {
+		return nil, ErrIDIsNotValid
+	}
+	if role != "" && !roles[role] {
+		return nil, ErrRoleIsNotValid
+	}
+
+	if _, err := d.personRepo.GetPerson(ctx, personID); err != nil {
+		return nil, personError(err)
+	}
+
+	return d.personRepo.GetPersonMovies(ctx, personID, role)
+}
+
+func (d *DefaultPersonService) AddCredit(ctx context.Context, credit model.Credit) error {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.AddCredit")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", credit.MovieID), attribute.Int("person.id", credit.PersonID))
+
+	credit.Role = strings.ToLower(strings.TrimSpace(credit.Role))
+	if !roles[credit.Role] {
+		return ErrRoleIsNotValid
+	}
+	if credit.Character != "" && credit.Role != model.RoleActor {
+		return ErrCharacterIsNotValid
+	}
+	if credit.BillingOrder < 0 {
+		return ErrBillingOrderIsNotValid
+	}
+
+	if err := checkMovie(ctx, d.movieRepo, credit.MovieID); err != nil {
+		return err
+	}
+	if credit.PersonID <= 0 {
+		return ErrIDIsNotValid
+	}
+	if _, err := d.personRepo.GetPerson(ctx, credit.PersonID); err != nil {
+		return personError(err)
+	}
+
+	if err := d.personRepo.AddCredit(ctx, credit); err != nil {
+		return err
+	}
+
+	d.logger.InfoContext(ctx, "credit added", "movie_id", credit.MovieID, "person_id", credit.PersonID, "role", credit.Role)
+	return nil
+}
+
+func (d *DefaultPersonService) DeleteCredit(ctx context.Context, movieID int, creditID int) error {
+	ctx, span := tracer.Start(ctx, "DefaultPersonService.DeleteCredit")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", movieID), attribute.Int("credit.id", creditID))
+
+	if movieID <= 0 || creditID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	err := d.personRepo.DeleteCredit(ctx, movieID, creditID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCreditNotFound) {
+			return ErrCreditNotFound
+		}
+		return err
+	}
+
+	d.logger.InfoContext(ctx, "credit deleted", "movie_id", movieID, "credit_id", creditID)
+	return nil
+}
+
+// checkMovie returns ErrMovieNotFound unless the movie exists.
+func checkMovie(ctx context.Context, movieRepo repository.IMovieRepository, movieID int) error {
+	if movieID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	_, err := movieRepo.GetMovie(ctx, movieID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMovieNotFound) {
+			return ErrMovieNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func personError(err error) error {
+	if errors.Is(err, repository.ErrPersonNotFound) {
+		return ErrPersonNotFound
+	}
+	return err
+}
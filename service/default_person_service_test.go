@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultPersonService_CreatePerson(t *testing.T) {
+	t.Run("Error Create Person - ErrNameIsNotEmpty", func(t *testing.T) {
+		dps := NewDefaultPersonService(nil, nil, logging.NewNop())
+		err := dps.CreatePerson(context.Background(), model.Person{Name: "  "})
+		assert.ErrorIs(t, err, ErrNameIsNotEmpty)
+	})
+	t.Run("Success Create Person", func(t *testing.T) {
+		mockPersonRepository := repository.NewMockIPersonRepository(gomock.NewController(t))
+		mockPersonRepository.
+			EXPECT().CreatePerson(gomock.Any(), model.Person{Name: "Sofia Coppola"}).
+			Return(nil).
+			Times(1)
+
+		dps := NewDefaultPersonService(mockPersonRepository, nil, logging.NewNop())
+		err := dps.CreatePerson(context.Background(), model.Person{Name: " Sofia Coppola "})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestDefaultPersonService_GetPersonMovies(t *testing.T) {
+	t.Run("Error - ErrRoleIsNotValid", func(t *testing.T) {
+		dps := NewDefaultPersonService(nil, nil, logging.NewNop())
+		_, err := dps.GetPersonMovies(context.Background(), 1, "gaffer")
+		assert.ErrorIs(t, err, ErrRoleIsNotValid)
+	})
+	t.Run("Error - ErrPersonNotFound", func(t *testing.T) {
+		mockPersonRepository := repository.NewMockIPersonRepository(gomock.NewController(t))
+		mockPersonRepository.EXPECT().GetPerson(gomock.Any(), 7).Return(model.Person{}, repository.ErrPersonNotFound).Times(1)
+		mockPersonRepository.EXPECT().GetPersonMovies(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		dps := NewDefaultPersonService(mockPersonRepository, nil, logging.NewNop())
+		_, err := dps.GetPersonMovies(context.Background(), 7, model.RoleDirector)
+
+		assert.ErrorIs(t, err, ErrPersonNotFound)
+	})
+	t.Run("Success", func(t *testing.T) {
+		mockPersonRepository := repository.NewMockIPersonRepository(gomock.NewController(t))
+		mockPersonRepository.EXPECT().GetPerson(gomock.Any(), 7).Return(model.Person{ID: 7}, nil).Times(1)
+		mockPersonRepository.
+			EXPECT().GetPersonMovies(gomock.Any(), 7, model.RoleDirector).
+			Return([]model.Movie{{ID: 3, Title: "The Dark Knight"}}, nil).
+			Times(1)
+
+		dps := NewDefaultPersonService(mockPersonRepository, nil, logging.NewNop())
+		movies, err := dps.GetPersonMovies(context.Background(), 7, model.RoleDirector)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []model.Movie{{ID: 3, Title: "The Dark Knight"}}, movies)
+	})
+}
+
+func TestDefaultPersonService_AddCredit(t *testing.T) {
+	t.Run("Error Add Credit - invalid credit", func(t *testing.T) {
+		testCases := []struct {
+			credit model.Credit
+			err    error
+		}{
+			{credit: model.Credit{MovieID: 1, PersonID: 1, Role: "gaffer"}, err: ErrRoleIsNotValid},
+			{credit: model.Credit{MovieID: 1, PersonID: 1, Role: model.RoleDirector, Character: "Himself"}, err: ErrCharacterIsNotValid},
+			{credit: model.Credit{MovieID: 1, PersonID: 1, Role: model.RoleActor, BillingOrder: -1}, err: ErrBillingOrderIsNotValid},
+			{credit: model.Credit{MovieID: 0, PersonID: 1, Role: model.RoleActor}, err: ErrIDIsNotValid},
+		}
+
+		for _, test := range testCases {
+			dps := NewDefaultPersonService(nil, nil, logging.NewNop())
+			err := dps.AddCredit(context.Background(), test.credit)
+			assert.ErrorIs(t, err, test.err)
+		}
+	})
+	t.Run("Error Add Credit - ErrMovieNotFound", func(t *testing.T) {
+		mockMovieRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockMovieRepository.EXPECT().GetMovie(gomock.Any(), 9).Return(model.Movie{}, repository.ErrMovieNotFound).Times(1)
+
+		dps := NewDefaultPersonService(nil, mockMovieRepository, logging.NewNop())
+		err := dps.AddCredit(context.Background(), model.Credit{MovieID: 9, PersonID: 1, Role: model.RoleActor})
+
+		assert.ErrorIs(t, err, ErrMovieNotFound)
+	})
+	t.Run("Success Add Credit", func(t *testing.T) {
+		credit := model.Credit{MovieID: 1, PersonID: 3, Role: model.RoleActor, Character: "Red", BillingOrder: 3}
+		mockMovieRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockMovieRepository.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1}, nil).Times(1)
+		mockPersonRepository := repository.NewMockIPersonRepository(gomock.NewController(t))
+		mockPersonRepository.EXPECT().GetPerson(gomock.Any(), 3).Return(model.Person{ID: 3}, nil).Times(1)
+		mockPersonRepository.EXPECT().AddCredit(gomock.Any(), credit).Return(nil).Times(1)
+
+		dps := NewDefaultPersonService(mockPersonRepository, mockMovieRepository, logging.NewNop())
+		err := dps.AddCredit(context.Background(), model.Credit{MovieID: 1, PersonID: 3, Role: " Actor", Character: "Red", BillingOrder: 3})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestDefaultPersonService_DeleteCredit(t *testing.T) {
+	t.Run("Error - ErrCreditNotFound", func(t *testing.T) {
+		mockPersonRepository := repository.NewMockIPersonRepository(gomock.NewController(t))
+		mockPersonRepository.EXPECT().DeleteCredit(gomock.Any(), 1, 99).Return(repository.ErrCreditNotFound).Times(1)
+
+		dps := NewDefaultPersonService(mockPersonRepository, nil, logging.NewNop())
+		err := dps.DeleteCredit(context.Background(), 1, 99)
+
+		assert.ErrorIs(t, err, ErrCreditNotFound)
+	})
+	t.Run("Error - repository failure", func(t *testing.T) {
+		mockPersonRepository := repository.NewMockIPersonRepository(gomock.NewController(t))
+		mockPersonRepository.EXPECT().DeleteCredit(gomock.Any(), 1, 2).Return(errors.New("oops!")).Times(1)
+
+		dps := NewDefaultPersonService(mockPersonRepository, nil, logging.NewNop())
+		err := dps.DeleteCredit(context.Background(), 1, 2)
+
+		assert.EqualError(t, err, "oops!")
+	})
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxReviewLength = 5000
+
+var (
+	ErrUserIDIsNotValid = errors.New("user id is not valid")
+	ErrRatingIsNotValid = errors.New("rating must be between 1 and 10")
+	ErrReviewIsNotEmpty = errors.New("review cannot be empty")
+	ErrReviewIsTooLong  = errors.New("review cannot be longer than 5000 characters")
+	ErrRatingNotFound   = errors.New("the rating cannot be found")
+	ErrReviewNotFound   = errors.New("the review cannot be found")
+)
+
+type DefaultRatingService struct {
+	ratingRepo  repository.IRatingRepository
+	movieRepo   repository.IMovieRepository
+	transactor  repository.ITransactor
+	outbox      repository.IOutboxRepository
+	broadcaster *events.Broadcaster
+	logger      *slog.Logger
+}
+
+// NewDefaultRatingService writes a model.EventMovieUpdated to outbox for every rating
+// that changes the score of a movie, in the same transaction of transactor, and hands
+// it to broadcaster once committed, like NewDefaultMovieService. The reviews leave the
+// movie as it was, so they have no event.
+func NewDefaultRatingService(rRepo repository.IRatingRepository, mRepo repository.IMovieRepository, transactor repository.ITransactor, outbox repository.IOutboxRepository, broadcaster *events.Broadcaster, logger *slog.Logger) *DefaultRatingService {
+	return &DefaultRatingService{
+		ratingRepo:  rRepo,
+		movieRepo:   mRepo,
+		transactor:  transactor,
+		outbox:      outbox,
+		broadcaster: broadcaster,
+		logger:      logger,
+	}
+}
+
+func (d *DefaultRatingService) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	ctx, span := tracer.Start(ctx, "DefaultRatingService.GetRatings")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", movieID))
+
+	if err := checkMovie(ctx, d.movieRepo, movieID); err != nil {
+		return nil, err
+	}
+
+	return d.ratingRepo.GetRatings(ctx, movieID)
+}
+
+func (d *DefaultRatingService) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	ctx, span := tracer.Start(ctx, "DefaultRatingService.RateMovie")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", rating.MovieID), attribute.Int("user.id", rating.UserID))
+
+	if rating.MovieID <= 0 {
+		return model.RatingSummary{}, ErrIDIsNotValid
+	}
+	if rating.UserID <= 0 {
+		return model.RatingSummary{}, ErrUserIDIsNotValid
+	}
+	if rating.Value < model.MinRatingValue || rating.Value > model.MaxRatingValue {
+		return model.RatingSummary{}, ErrRatingIsNotValid
+	}
+
+	var summary model.RatingSummary
+	var changes []model.Event
+	err := d.transactor.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if summary, err = d.ratingRepo.RateMovie(ctx, rating); err != nil {
+			return err
+		}
+		changes, err = d.appendScoreEvent(ctx, rating.MovieID)
+		return err
+	})
+	if err != nil {
+		return model.RatingSummary{}, ratingError(err)
+	}
+	d.broadcast(changes)
+
+	d.logger.InfoContext(ctx, "movie rated", "movie_id", rating.MovieID, "user_id", rating.UserID, "score", summary.Score)
+	return summary, nil
+}
+
+func (d *DefaultRatingService) DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error) {
+	ctx, span := tracer.Start(ctx, "DefaultRatingService.DeleteRating")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", movieID), attribute.Int("user.id", userID))
+
+	if movieID <= 0 {
+		return model.RatingSummary{}, ErrIDIsNotValid
+	}
+	if userID <= 0 {
+		return model.RatingSummary{}, ErrUserIDIsNotValid
+	}
+
+	var summary model.RatingSummary
+	var changes []model.Event
+	err := d.transactor.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if summary, err = d.ratingRepo.DeleteRating(ctx, movieID, userID); err != nil {
+			return err
+		}
+		changes, err = d.appendScoreEvent(ctx, movieID)
+		return err
+	})
+	if err != nil {
+		return model.RatingSummary{}, ratingError(err)
+	}
+	d.broadcast(changes)
+
+	d.logger.InfoContext(ctx, "rating deleted", "movie_id", movieID, "user_id", userID)
+	return summary, nil
+}
+
+func (d *DefaultRatingService) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	ctx, span := tracer.Start(ctx, "DefaultRatingService.GetReviews")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", movieID))
+
+	if err := checkMovie(ctx, d.movieRepo, movieID); err != nil {
+		return nil, err
+	}
+
+	return d.ratingRepo.GetReviews(ctx, movieID)
+}
+
+func (d *DefaultRatingService) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	ctx, span := tracer.Start(ctx, "DefaultRatingService.GetReviewsByMovies")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.count", len(movieIDs)))
+
+	if len(movieIDs) == 0 {
+		return map[int][]model.Review{}, nil
+	}
+	return d.ratingRepo.GetReviewsByMovies(ctx, movieIDs)
+}
+
+func (d *DefaultRatingService) AddReview(ctx context.Context, review model.Review) error {
+	ctx, span := tracer.Start(ctx, "DefaultRatingService.AddReview")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", review.MovieID), attribute.Int("user.id", review.UserID))
+
+	if review.MovieID <= 0 {
+		return ErrIDIsNotValid
+	}
+	if review.UserID <= 0 {
+		return ErrUserIDIsNotValid
+	}
+
+	review.Body = strings.TrimSpace(review.Body)
+	if review.Body == "" {
+		return ErrReviewIsNotEmpty
+	}
+	if utf8.RuneCountInString(review.Body) > maxReviewLength {
+		return ErrReviewIsTooLong
+	}
+
+	if err := d.ratingRepo.AddReview(ctx, review); err != nil {
+		return ratingError(err)
+	}
+
+	d.logger.InfoContext(ctx, "review added", "movie_id", review.MovieID, "user_id", review.UserID)
+	return nil
+}
+
+func (d *DefaultRatingService) DeleteReview(ctx context.Context, movieID int, reviewID int) error {
+	ctx, span := tracer.Start(ctx, "DefaultRatingService.DeleteReview")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", movieID), attribute.Int("review.id", reviewID))
+
+	if movieID <= 0 || reviewID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	if err := d.ratingRepo.DeleteReview(ctx, movieID, reviewID); err != nil {
+		return ratingError(err)
+	}
+
+	d.logger.InfoContext(ctx, "review deleted", "movie_id", movieID, "review_id", reviewID)
+	return nil
+}
+
+// appendScoreEvent writes the movie, as its new score left it within the transaction of
+// ctx, to the outbox.
+func (d *DefaultRatingService) appendScoreEvent(ctx context.Context, movieID int) ([]model.Event, error) {
+	movie, err := d.movieRepo.GetMovie(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+	changes := []model.Event{model.NewMovieEvent(model.EventMovieUpdated, movie)}
+	return changes, d.outbox.AppendEvents(ctx, changes)
+}
+
+func (d *DefaultRatingService) broadcast(changes []model.Event) {
+	for _, change := range changes {
+		d.broadcaster.Broadcast(change)
+	}
+}
+
+func ratingError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrMovieNotFound):
+		return ErrMovieNotFound
+	case errors.Is(err, repository.ErrRatingNotFound):
+		return ErrRatingNotFound
+	case errors.Is(err, repository.ErrReviewNotFound):
+		return ErrReviewNotFound
+	}
+	return err
+}
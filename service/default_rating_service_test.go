@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRatingService writes the events to an in-memory outbox.
+func newTestRatingService(rRepo repository.IRatingRepository, mRepo repository.IMovieRepository) *DefaultRatingService {
+	return NewDefaultRatingService(rRepo, mRepo, repository.NewInMemoryTransactor(),
+		repository.NewInMemoryOutboxRepository(logging.NewNop()), newTestBroadcaster(), logging.NewNop())
+}
+
+func TestDefaultRatingService_RateMovie(t *testing.T) {
+	t.Run("Error Rate Movie - invalid rating", func(t *testing.T) {
+		testCases := []struct {
+			rating model.Rating
+			err    error
+		}{
+			{rating: model.Rating{MovieID: 0, UserID: 1, Value: 5}, err: ErrIDIsNotValid},
+			{rating: model.Rating{MovieID: 1, UserID: 0, Value: 5}, err: ErrUserIDIsNotValid},
+			{rating: model.Rating{MovieID: 1, UserID: 1, Value: 0}, err: ErrRatingIsNotValid},
+			{rating: model.Rating{MovieID: 1, UserID: 1, Value: 11}, err: ErrRatingIsNotValid},
+		}
+
+		for _, test := range testCases {
+			drs := newTestRatingService(nil, nil)
+			_, err := drs.RateMovie(context.Background(), test.rating)
+			assert.ErrorIs(t, err, test.err)
+		}
+	})
+	t.Run("Error Rate Movie - ErrMovieNotFound", func(t *testing.T) {
+		mockRepository := repository.NewMockIRatingRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().RateMovie(gomock.Any(), gomock.Any()).
+			Return(model.RatingSummary{}, repository.ErrMovieNotFound).
+			Times(1)
+
+		drs := newTestRatingService(mockRepository, nil)
+		_, err := drs.RateMovie(context.Background(), model.Rating{MovieID: 9, UserID: 1, Value: 5})
+
+		assert.ErrorIs(t, err, ErrMovieNotFound)
+	})
+	t.Run("Success Rate Movie - aggregate is recalculated", func(t *testing.T) {
+		ctx := context.Background()
+		movies := repository.NewInMemoryMovieRepository(logging.NewNop())
+		drs := newTestRatingService(repository.NewInMemoryRatingRepository(movies, logging.NewNop()), movies)
+
+		drs.RateMovie(ctx, model.Rating{MovieID: 2, UserID: 1, Value: 4})
+		drs.RateMovie(ctx, model.Rating{MovieID: 1, UserID: 1, Value: 10})
+		summary, err := drs.RateMovie(ctx, model.Rating{MovieID: 1, UserID: 2, Value: 7})
+		assert.Nil(t, err)
+
+		// mean 8.5 over 2 ratings, pulled towards model.ScorePrior, 5.5.
+		assert.Equal(t, model.RatingSummary{MovieID: 1, Count: 2, Mean: 8.5, Score: 6}, summary)
+
+		movie, _ := movies.GetMovie(ctx, 1)
+		assert.Equal(t, 2, movie.RatingCount)
+		assert.Equal(t, 8.5, movie.RatingMean)
+		assert.Equal(t, 6.0, movie.Score)
+
+		// Rating movie 1 leaves the score of movie 2 as it was.
+		movie, _ = movies.GetMovie(ctx, 2)
+		assert.Equal(t, 5.36, movie.Score)
+
+		// A second rating by the same user replaces the first one.
+		summary, _ = drs.RateMovie(ctx, model.Rating{MovieID: 1, UserID: 2, Value: 10})
+		assert.Equal(t, 2, summary.Count)
+		assert.Equal(t, 10.0, summary.Mean)
+
+		summary, err = drs.DeleteRating(ctx, 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, summary.Count)
+
+		_, err = drs.DeleteRating(ctx, 1, 2)
+		assert.ErrorIs(t, err, ErrRatingNotFound)
+
+		// Once the last rating is gone the editorial score comes back.
+		summary, err = drs.DeleteRating(ctx, 2, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, model.RatingSummary{MovieID: 2, Score: 9.2}, summary)
+	})
+	t.Run("Success Rate Movie - the new score is written to the outbox and broadcast", func(t *testing.T) {
+		ctx := context.Background()
+		movies := repository.NewInMemoryMovieRepository(logging.NewNop())
+		outbox := repository.NewInMemoryOutboxRepository(logging.NewNop())
+		broadcaster := newTestBroadcaster()
+		subscription, _ := broadcaster.Subscribe(0)
+		drs := NewDefaultRatingService(repository.NewInMemoryRatingRepository(movies, logging.NewNop()), movies,
+			repository.NewInMemoryTransactor(), outbox, broadcaster, logging.NewNop())
+
+		_, err := drs.RateMovie(ctx, model.Rating{MovieID: 1, UserID: 1, Value: 10})
+		assert.Nil(t, err)
+		_, err = drs.DeleteRating(ctx, 1, 1)
+		assert.Nil(t, err)
+		_, err = drs.DeleteRating(ctx, 1, 1)
+		assert.ErrorIs(t, err, ErrRatingNotFound)
+
+		events, _ := outbox.ClaimPendingEvents(ctx, 100, time.Minute)
+		assert.Len(t, events, 2)
+		for _, event := range events {
+			assert.Equal(t, model.EventMovieUpdated, event.Type)
+			assert.Equal(t, 1, event.MovieID)
+		}
+		assert.Equal(t, 1, events[0].Movie.RatingCount)
+		assert.Equal(t, 0, events[1].Movie.RatingCount)
+
+		subscription.Close()
+		var broadcast []model.Event
+		for event := range subscription.Events() {
+			broadcast = append(broadcast, event)
+		}
+		assert.Equal(t, events, broadcast)
+	})
+}
+
+func TestDefaultRatingService_AddReview(t *testing.T) {
+	t.Run("Error Add Review - invalid review", func(t *testing.T) {
+		testCases := []struct {
+			review model.Review
+			err    error
+		}{
+			{review: model.Review{MovieID: 1, UserID: 0, Body: "Good"}, err: ErrUserIDIsNotValid},
+			{review: model.Review{MovieID: 1, UserID: 1, Body: "  "}, err: ErrReviewIsNotEmpty},
+			{review: model.Review{MovieID: 1, UserID: 1, Body: strings.Repeat("a", 5001)}, err: ErrReviewIsTooLong},
+		}
+
+		for _, test := range testCases {
+			drs := newTestRatingService(nil, nil)
+			err := drs.AddReview(context.Background(), test.review)
+			assert.ErrorIs(t, err, test.err)
+		}
+	})
+	t.Run("Success Add Review", func(t *testing.T) {
+		mockRepository := repository.NewMockIRatingRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().AddReview(gomock.Any(), model.Review{MovieID: 1, UserID: 42, Body: "Hope is a good thing."}).
+			Return(nil).
+			Times(1)
+
+		drs := newTestRatingService(mockRepository, nil)
+		err := drs.AddReview(context.Background(), model.Review{MovieID: 1, UserID: 42, Body: " Hope is a good thing. "})
+
+		assert.Nil(t, err)
+	})
+}
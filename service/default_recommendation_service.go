@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/recommend"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"time"
+)
+
+// maxRatingsConsidered bounds the work per recommendation to the latest ratings of
+// the user, which also reflect their current taste best.
+const maxRatingsConsidered = 50
+
+type DefaultRecommendationService struct {
+	movieRepo          repository.IMovieRepository
+	ratingRepo         repository.IRatingRepository
+	recommendationRepo repository.IRecommendationRepository
+	logger             *slog.Logger
+}
+
+func NewDefaultRecommendationService(mRepo repository.IMovieRepository, rRepo repository.IRatingRepository,
+	recRepo repository.IRecommendationRepository, logger *slog.Logger) *DefaultRecommendationService {
+	return &DefaultRecommendationService{
+		movieRepo:          mRepo,
+		ratingRepo:         rRepo,
+		recommendationRepo: recRepo,
+		logger:             logger,
+	}
+}
+
+func (d *DefaultRecommendationService) GetSimilarMovies(ctx context.Context, movieID int, limit int) ([]model.SimilarMovie, error) {
+	ctx, span := tracer.Start(ctx, "DefaultRecommendationService.GetSimilarMovies")
+	defer span.End()
+	span.SetAttributes(attribute.Int("movie.id", movieID), attribute.Int("limit", limit))
+
+	if limit < 1 || limit > MaxLimit {
+		return nil, ErrLimitIsNotValid
+	}
+	if err := checkMovie(ctx, d.movieRepo, movieID); err != nil {
+		return nil, err
+	}
+
+	neighbours, err := d.newNeighbourSource().neighbours(ctx, movieID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(neighbours))
+	for _, neighbour := range neighbours {
+		ids = append(ids, neighbour.NeighbourID)
+	}
+	movies, err := d.movies(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	similar := make([]model.SimilarMovie, 0, len(neighbours))
+	for _, neighbour := range neighbours {
+		if movie, ok := movies[neighbour.NeighbourID]; ok {
+			similar = append(similar, model.SimilarMovie{Movie: movie, Similarity: neighbour.Similarity})
+		}
+	}
+	return similar, nil
+}
+
+// GetRecommendations recommends the movies the user is predicted to rate best, and
+// fills up with the best scored movies when the ratings of the user do not predict
+// enough of them.
+func (d *DefaultRecommendationService) GetRecommendations(ctx context.Context, userID int, limit int) ([]model.Recommendation, error) {
+	ctx, span := tracer.Start(ctx, "DefaultRecommendationService.GetRecommendations")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", userID), attribute.Int("limit", limit))
+
+	if userID <= 0 {
+		return nil, ErrUserIDIsNotValid
+	}
+	if limit < 1 || limit > MaxLimit {
+		return nil, ErrLimitIsNotValid
+	}
+
+	ratings, err := d.ratingRepo.GetUserRatings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rated movies are never recommended, including the ones left out of the latest
+	// ratings.
+	seen := make(map[int]bool, len(ratings))
+	for _, rating := range ratings {
+		seen[rating.MovieID] = true
+	}
+	if len(ratings) > maxRatingsConsidered {
+		ratings = ratings[:maxRatingsConsidered]
+	}
+
+	source := d.newNeighbourSource()
+	neighbours := make(map[int][]model.Neighbour, len(ratings))
+	for _, rating := range ratings {
+		neighbours[rating.MovieID], err = source.neighbours(ctx, rating.MovieID, recommend.DefaultNeighbours)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The predictions are bounded by the neighbours of the latest ratings, so all of
+	// them are read at once.
+	var predictions []recommend.Prediction
+	var predictedIDs []int
+	for _, prediction := range recommend.Predict(ratings, neighbours) {
+		if !seen[prediction.MovieID] {
+			predictions = append(predictions, prediction)
+			predictedIDs = append(predictedIDs, prediction.MovieID)
+		}
+	}
+	predicted, err := d.movies(ctx, predictedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]model.Recommendation, 0, limit)
+	for _, prediction := range predictions {
+		if len(recommendations) == limit {
+			break
+		}
+		if movie, ok := predicted[prediction.MovieID]; ok {
+			seen[movie.ID] = true
+			recommendations = append(recommendations, model.Recommendation{
+				Movie:  movie,
+				Score:  prediction.Rating,
+				Reason: model.ReasonSimilar,
+			})
+		}
+	}
+
+	if len(recommendations) < limit {
+		excluded := make([]int, 0, len(seen))
+		for id := range seen {
+			excluded = append(excluded, id)
+		}
+		popular, err := d.movieRepo.GetBestScoredMovies(ctx, limit-len(recommendations), excluded)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, movie := range popular {
+			recommendations = append(recommendations, model.Recommendation{
+				Movie:  movie,
+				Score:  movie.Score,
+				Reason: model.ReasonPopular,
+			})
+		}
+	}
+
+	return recommendations, nil
+}
+
+func (d *DefaultRecommendationService) RefreshNeighbours(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "DefaultRecommendationService.RefreshNeighbours")
+	defer span.End()
+
+	start := time.Now()
+	m, movieIDs, err := d.loadModel(ctx)
+	if err != nil {
+		return err
+	}
+
+	neighbours := m.AllNeighbours(recommend.DefaultNeighbours)
+	span.SetAttributes(attribute.Int("neighbours", len(neighbours)))
+
+	if err := d.recommendationRepo.ReplaceNeighbours(ctx, movieIDs, neighbours); err != nil {
+		return err
+	}
+
+	d.logger.InfoContext(ctx, "neighbours refreshed", "neighbours", len(neighbours), "duration", time.Since(start))
+	return nil
+}
+
+// loadModel reads the whole catalog and all ratings, it also returns the ids of the
+// movies of the model.
+func (d *DefaultRecommendationService) loadModel(ctx context.Context) (*recommend.Model, []int, error) {
+	movies, err := d.movieRepo.GetMovies(ctx, model.MovieFilter{})
+	if err != nil {
+		return nil, nil, err
+	}
+	movieIDs := make([]int, 0, len(movies))
+	for _, movie := range movies {
+		movieIDs = append(movieIDs, movie.ID)
+	}
+
+	ratings := make([]model.Rating, 0)
+	err = d.ratingRepo.StreamRatings(ctx, func(rating model.Rating) error {
+		ratings = append(ratings, rating)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return recommend.NewModel(movies, ratings), movieIDs, nil
+}
+
+// movies reads the movies of ids by id. The ones deleted after the neighbours were
+// computed are missing.
+func (d *DefaultRecommendationService) movies(ctx context.Context, ids []int) (map[int]model.Movie, error) {
+	byID := make(map[int]model.Movie, len(ids))
+	if len(ids) == 0 {
+		return byID, nil
+	}
+
+	movies, err := d.movieRepo.GetMoviesByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, movie := range movies {
+		byID[movie.ID] = movie
+	}
+	return byID, nil
+}
+
+// neighbourSource reads the precomputed neighbours and computes the ones missing, of
+// movies created since the last refresh or of all movies before the first one. A
+// movie whose neighbours were computed but came out empty is not computed again. The
+// catalog and ratings are loaded at most once per source.
+type neighbourSource struct {
+	service  *DefaultRecommendationService
+	fallback *recommend.Model
+}
+
+func (d *DefaultRecommendationService) newNeighbourSource() *neighbourSource {
+	return &neighbourSource{service: d}
+}
+
+func (n *neighbourSource) neighbours(ctx context.Context, movieID int, limit int) ([]model.Neighbour, error) {
+	neighbours, computed, err := n.service.recommendationRepo.GetNeighbours(ctx, movieID, limit)
+	if err != nil || computed {
+		return neighbours, err
+	}
+
+	if n.fallback == nil {
+		n.fallback, _, err = n.service.loadModel(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n.fallback.Neighbours(movieID, limit), nil
+}
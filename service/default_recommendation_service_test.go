@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func similarIDs(similar []model.SimilarMovie) []int {
+	ids := make([]int, 0, len(similar))
+	for _, s := range similar {
+		ids = append(ids, s.Movie.ID)
+	}
+	return ids
+}
+
+func TestDefaultRecommendationService_GetSimilarMovies(t *testing.T) {
+	t.Run("Error Get Similar Movies - invalid input", func(t *testing.T) {
+		drs := NewDefaultRecommendationService(nil, nil, nil, logging.NewNop())
+
+		_, err := drs.GetSimilarMovies(context.Background(), 0, 10)
+		assert.ErrorIs(t, err, ErrIDIsNotValid)
+		_, err = drs.GetSimilarMovies(context.Background(), 1, 0)
+		assert.ErrorIs(t, err, ErrLimitIsNotValid)
+		_, err = drs.GetSimilarMovies(context.Background(), 1, MaxLimit+1)
+		assert.ErrorIs(t, err, ErrLimitIsNotValid)
+	})
+	t.Run("Success Get Similar Movies - precomputed neighbours", func(t *testing.T) {
+		mockMovieRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockMovieRepository.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1}, nil).Times(1)
+		mockMovieRepository.EXPECT().GetMoviesByID(gomock.Any(), []int{2, 3}).Return([]model.Movie{{ID: 3}}, nil).Times(1)
+
+		mockRecommendationRepository := repository.NewMockIRecommendationRepository(gomock.NewController(t))
+		mockRecommendationRepository.
+			EXPECT().GetNeighbours(gomock.Any(), 1, 2).
+			Return([]model.Neighbour{{MovieID: 1, NeighbourID: 2, Similarity: 0.9}, {MovieID: 1, NeighbourID: 3, Similarity: 0.5}}, true, nil).
+			Times(1)
+
+		drs := NewDefaultRecommendationService(mockMovieRepository, nil, mockRecommendationRepository, logging.NewNop())
+		similar, err := drs.GetSimilarMovies(context.Background(), 1, 2)
+
+		// Movie 2 was deleted since the neighbours were computed.
+		assert.Nil(t, err)
+		assert.Equal(t, []model.SimilarMovie{{Movie: model.Movie{ID: 3}, Similarity: 0.5}}, similar)
+	})
+	t.Run("Success Get Similar Movies - computed without neighbours, the catalog is not read", func(t *testing.T) {
+		mockMovieRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockMovieRepository.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{ID: 1}, nil).Times(1)
+
+		mockRecommendationRepository := repository.NewMockIRecommendationRepository(gomock.NewController(t))
+		mockRecommendationRepository.EXPECT().GetNeighbours(gomock.Any(), 1, 2).Return([]model.Neighbour{}, true, nil).Times(1)
+
+		drs := NewDefaultRecommendationService(mockMovieRepository, nil, mockRecommendationRepository, logging.NewNop())
+		similar, err := drs.GetSimilarMovies(context.Background(), 1, 2)
+
+		assert.Nil(t, err)
+		assert.Empty(t, similar)
+	})
+}
+
+func TestDefaultRecommendationService_GetRecommendations(t *testing.T) {
+	t.Run("Success Get Recommendations - best scored movies fill up without reading the catalog", func(t *testing.T) {
+		mockRatingRepository := repository.NewMockIRatingRepository(gomock.NewController(t))
+		mockRatingRepository.EXPECT().GetUserRatings(gomock.Any(), 42).Return([]model.Rating{{MovieID: 1, UserID: 42, Value: 9}}, nil).Times(1)
+
+		mockRecommendationRepository := repository.NewMockIRecommendationRepository(gomock.NewController(t))
+		mockRecommendationRepository.EXPECT().GetNeighbours(gomock.Any(), 1, gomock.Any()).Return([]model.Neighbour{}, true, nil).Times(1)
+
+		mockMovieRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+		mockMovieRepository.EXPECT().GetBestScoredMovies(gomock.Any(), 2, []int{1}).Return([]model.Movie{{ID: 2, Score: 9}, {ID: 3, Score: 8}}, nil).Times(1)
+
+		drs := NewDefaultRecommendationService(mockMovieRepository, mockRatingRepository, mockRecommendationRepository, logging.NewNop())
+		picks, err := drs.GetRecommendations(context.Background(), 42, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []model.Recommendation{
+			{Movie: model.Movie{ID: 2, Score: 9}, Score: 9, Reason: model.ReasonPopular},
+			{Movie: model.Movie{ID: 3, Score: 8}, Score: 8, Reason: model.ReasonPopular},
+		}, picks)
+	})
+}
+
+func TestDefaultRecommendationService_InMemory(t *testing.T) {
+	ctx := context.Background()
+	movies := repository.NewInMemoryMovieRepository(logging.NewNop())
+	ratings := repository.NewInMemoryRatingRepository(movies, logging.NewNop())
+	recommendations := repository.NewInMemoryRecommendationRepository(movies, logging.NewNop())
+	drs := NewDefaultRecommendationService(movies, ratings, recommendations, logging.NewNop())
+
+	t.Run("Similar movies are computed before the first refresh", func(t *testing.T) {
+		neighbours, computed, _ := recommendations.GetNeighbours(ctx, 1, 10)
+		assert.Empty(t, neighbours)
+		assert.False(t, computed)
+
+		similar, err := drs.GetSimilarMovies(ctx, 1, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2, 3}, similarIDs(similar))
+	})
+	t.Run("RefreshNeighbours stores the same neighbours", func(t *testing.T) {
+		before, _ := drs.GetSimilarMovies(ctx, 1, 10)
+
+		assert.Nil(t, drs.RefreshNeighbours(ctx))
+		neighbours, _, _ := recommendations.GetNeighbours(ctx, 1, 10)
+		assert.Len(t, neighbours, 2)
+
+		after, _ := drs.GetSimilarMovies(ctx, 1, 10)
+		assert.Equal(t, before, after)
+	})
+	t.Run("Users without ratings get the best scored movies", func(t *testing.T) {
+		picks, err := drs.GetRecommendations(ctx, 42, 2)
+
+		assert.Nil(t, err)
+		assert.Len(t, picks, 2)
+		assert.Equal(t, 1, picks[0].Movie.ID)
+		assert.Equal(t, model.ReasonPopular, picks[0].Reason)
+	})
+	t.Run("Rated movies are not recommended", func(t *testing.T) {
+		ratings.RateMovie(ctx, model.Rating{MovieID: 3, UserID: 42, Value: 9})
+
+		picks, err := drs.GetRecommendations(ctx, 42, 10)
+
+		assert.Nil(t, err)
+		assert.Len(t, picks, 2)
+		for _, pick := range picks {
+			assert.NotEqual(t, 3, pick.Movie.ID)
+			assert.Equal(t, model.ReasonSimilar, pick.Reason)
+		}
+	})
+	t.Run("DeleteMovie cascades", func(t *testing.T) {
+		assert.Nil(t, movies.DeleteMovie(ctx, 2))
+
+		neighbours, _, _ := recommendations.GetNeighbours(ctx, 1, 10)
+		for _, neighbour := range neighbours {
+			assert.NotEqual(t, 2, neighbour.NeighbourID)
+		}
+		neighbours, _, _ = recommendations.GetNeighbours(ctx, 2, 10)
+		assert.Empty(t, neighbours)
+	})
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrWatchlistOrderIsNotValid = errors.New("the order must list every movie of the watchlist exactly once")
+	ErrWatchedAtIsNotValid      = errors.New("watched at cannot be in the future")
+	ErrWatchlistItemNotFound    = errors.New("the movie is not on the watchlist")
+	ErrWatchedEntryNotFound     = errors.New("the watched entry cannot be found")
+)
+
+type DefaultWatchlistService struct {
+	watchlistRepo repository.IWatchlistRepository
+	logger        *slog.Logger
+	now           func() time.Time
+}
+
+func NewDefaultWatchlistService(wRepo repository.IWatchlistRepository, logger *slog.Logger) *DefaultWatchlistService {
+	return &DefaultWatchlistService{
+		watchlistRepo: wRepo,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+func (d *DefaultWatchlistService) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	ctx, span := tracer.Start(ctx, "DefaultWatchlistService.GetWatchlist")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", userID))
+
+	if userID <= 0 {
+		return nil, ErrUserIDIsNotValid
+	}
+
+	return d.watchlistRepo.GetWatchlist(ctx, userID)
+}
+
+func (d *DefaultWatchlistService) AddToWatchlist(ctx context.Context, userID int, movieID int) error {
+	ctx, span := tracer.Start(ctx, "DefaultWatchlistService.AddToWatchlist")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", userID), attribute.Int("movie.id", movieID))
+
+	if userID <= 0 {
+		return ErrUserIDIsNotValid
+	}
+	if movieID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	if err := d.watchlistRepo.AddToWatchlist(ctx, userID, movieID); err != nil {
+		return watchlistError(err)
+	}
+
+	d.logger.InfoContext(ctx, "movie added to watchlist", "user_id", userID, "movie_id", movieID)
+	return nil
+}
+
+func (d *DefaultWatchlistService) RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error {
+	ctx, span := tracer.Start(ctx, "DefaultWatchlistService.RemoveFromWatchlist")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", userID), attribute.Int("movie.id", movieID))
+
+	if userID <= 0 {
+		return ErrUserIDIsNotValid
+	}
+	if movieID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	if err := d.watchlistRepo.RemoveFromWatchlist(ctx, userID, movieID); err != nil {
+		return watchlistError(err)
+	}
+
+	d.logger.InfoContext(ctx, "movie removed from watchlist", "user_id", userID, "movie_id", movieID)
+	return nil
+}
+
+func (d *DefaultWatchlistService) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	ctx, span := tracer.Start(ctx, "DefaultWatchlistService.ReorderWatchlist")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", userID), attribute.Int("watchlist.size", len(movieIDs)))
+
+	if userID <= 0 {
+		return ErrUserIDIsNotValid
+	}
+
+	seen := make(map[int]bool, len(movieIDs))
+	for _, movieID := range movieIDs {
+		if movieID <= 0 || seen[movieID] {
+			return ErrWatchlistOrderIsNotValid
+		}
+		seen[movieID] = true
+	}
+
+	if err := d.watchlistRepo.ReorderWatchlist(ctx, userID, movieIDs); err != nil {
+		return watchlistError(err)
+	}
+
+	d.logger.InfoContext(ctx, "watchlist reordered", "user_id", userID)
+	return nil
+}
+
+func (d *DefaultWatchlistService) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	ctx, span := tracer.Start(ctx, "DefaultWatchlistService.GetWatched")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", userID))
+
+	if userID <= 0 {
+		return nil, ErrUserIDIsNotValid
+	}
+
+	return d.watchlistRepo.GetWatched(ctx, userID)
+}
+
+// AddWatched records the entry as watched now unless it says when, and takes the movie
+// off the watchlist.
+func (d *DefaultWatchlistService) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	ctx, span := tracer.Start(ctx, "DefaultWatchlistService.AddWatched")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", entry.UserID), attribute.Int("movie.id", entry.MovieID))
+
+	if entry.UserID <= 0 {
+		return ErrUserIDIsNotValid
+	}
+	if entry.MovieID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	now := d.now().UTC()
+	if entry.WatchedAt.IsZero() {
+		entry.WatchedAt = now
+	}
+	if entry.WatchedAt.After(now) {
+		return ErrWatchedAtIsNotValid
+	}
+	entry.WatchedAt = entry.WatchedAt.UTC()
+
+	if err := d.watchlistRepo.AddWatched(ctx, entry); err != nil {
+		return watchlistError(err)
+	}
+
+	d.logger.InfoContext(ctx, "movie watched", "user_id", entry.UserID, "movie_id", entry.MovieID)
+	return nil
+}
+
+func (d *DefaultWatchlistService) DeleteWatched(ctx context.Context, userID int, entryID int) error {
+	ctx, span := tracer.Start(ctx, "DefaultWatchlistService.DeleteWatched")
+	defer span.End()
+	span.SetAttributes(attribute.Int("user.id", userID), attribute.Int("watched.id", entryID))
+
+	if userID <= 0 {
+		return ErrUserIDIsNotValid
+	}
+	if entryID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	if err := d.watchlistRepo.DeleteWatched(ctx, userID, entryID); err != nil {
+		return watchlistError(err)
+	}
+
+	d.logger.InfoContext(ctx, "watched entry deleted", "user_id", userID, "watched_id", entryID)
+	return nil
+}
+
+func watchlistError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrMovieNotFound):
+		return ErrMovieNotFound
+	case errors.Is(err, repository.ErrWatchlistItemNotFound):
+		return ErrWatchlistItemNotFound
+	case errors.Is(err, repository.ErrWatchlistOrderInvalid):
+		return ErrWatchlistOrderIsNotValid
+	case errors.Is(err, repository.ErrWatchedEntryNotFound):
+		return ErrWatchedEntryNotFound
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDefaultWatchlistService_ReorderWatchlist(t *testing.T) {
+	t.Run("Error Reorder Watchlist - invalid order", func(t *testing.T) {
+		testCases := []struct {
+			userID   int
+			movieIDs []int
+			err      error
+		}{
+			{userID: 0, movieIDs: []int{1}, err: ErrUserIDIsNotValid},
+			{userID: 1, movieIDs: []int{1, 0}, err: ErrWatchlistOrderIsNotValid},
+			{userID: 1, movieIDs: []int{1, 2, 1}, err: ErrWatchlistOrderIsNotValid},
+		}
+
+		for _, test := range testCases {
+			dws := NewDefaultWatchlistService(nil, logging.NewNop())
+			err := dws.ReorderWatchlist(context.Background(), test.userID, test.movieIDs)
+			assert.ErrorIs(t, err, test.err)
+		}
+	})
+	t.Run("Error Reorder Watchlist - order does not match the watchlist", func(t *testing.T) {
+		mockRepository := repository.NewMockIWatchlistRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().ReorderWatchlist(gomock.Any(), 1, []int{2, 1}).
+			Return(repository.ErrWatchlistOrderInvalid).
+			Times(1)
+
+		dws := NewDefaultWatchlistService(mockRepository, logging.NewNop())
+		err := dws.ReorderWatchlist(context.Background(), 1, []int{2, 1})
+
+		assert.ErrorIs(t, err, ErrWatchlistOrderIsNotValid)
+		assert.True(t, IsValidationError(err))
+	})
+}
+
+func TestDefaultWatchlistService_AddWatched(t *testing.T) {
+	t.Run("Error Add Watched - watched in the future", func(t *testing.T) {
+		dws := NewDefaultWatchlistService(nil, logging.NewNop())
+		err := dws.AddWatched(context.Background(), model.WatchedEntry{UserID: 1, MovieID: 1, WatchedAt: time.Now().Add(time.Hour)})
+
+		assert.ErrorIs(t, err, ErrWatchedAtIsNotValid)
+	})
+	t.Run("Success Add Watched - defaults to now", func(t *testing.T) {
+		now := time.Date(2022, 9, 24, 20, 30, 0, 0, time.UTC)
+		mockRepository := repository.NewMockIWatchlistRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().AddWatched(gomock.Any(), model.WatchedEntry{UserID: 1, MovieID: 2, WatchedAt: now}).
+			Return(nil).
+			Times(1)
+
+		dws := NewDefaultWatchlistService(mockRepository, logging.NewNop())
+		dws.now = func() time.Time { return now }
+		err := dws.AddWatched(context.Background(), model.WatchedEntry{UserID: 1, MovieID: 2})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestDefaultWatchlistService_InMemory(t *testing.T) {
+	ctx := context.Background()
+	movies := repository.NewInMemoryMovieRepository(logging.NewNop())
+	dws := NewDefaultWatchlistService(repository.NewInMemoryWatchlistRepository(movies, logging.NewNop()), logging.NewNop())
+
+	movieIDs := func(items []model.WatchlistItem) []int {
+		ids := make([]int, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.MovieID)
+		}
+		return ids
+	}
+
+	t.Run("Add, reorder and watch", func(t *testing.T) {
+		for _, movieID := range []int{1, 2, 3, 2} {
+			assert.Nil(t, dws.AddToWatchlist(ctx, 1, movieID))
+		}
+		assert.ErrorIs(t, dws.AddToWatchlist(ctx, 1, 99), ErrMovieNotFound)
+
+		items, _ := dws.GetWatchlist(ctx, 1)
+		assert.Equal(t, []int{1, 2, 3}, movieIDs(items))
+		assert.Equal(t, "The Shawshank Redemption", items[0].Movie.Title)
+
+		assert.ErrorIs(t, dws.ReorderWatchlist(ctx, 1, []int{3, 1}), ErrWatchlistOrderIsNotValid)
+		assert.Nil(t, dws.ReorderWatchlist(ctx, 1, []int{3, 1, 2}))
+		items, _ = dws.GetWatchlist(ctx, 1)
+		assert.Equal(t, []int{3, 1, 2}, movieIDs(items))
+
+		assert.Nil(t, dws.AddWatched(ctx, model.WatchedEntry{UserID: 1, MovieID: 1}))
+		items, _ = dws.GetWatchlist(ctx, 1)
+		assert.Equal(t, []int{3, 2}, movieIDs(items))
+
+		entries, _ := dws.GetWatched(ctx, 1)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, 1, entries[0].MovieID)
+
+		assert.ErrorIs(t, dws.RemoveFromWatchlist(ctx, 1, 1), ErrWatchlistItemNotFound)
+		assert.ErrorIs(t, dws.DeleteWatched(ctx, 2, entries[0].ID), ErrWatchedEntryNotFound)
+	})
+	t.Run("DeleteMovie cascades", func(t *testing.T) {
+		assert.Nil(t, dws.AddWatched(ctx, model.WatchedEntry{UserID: 1, MovieID: 3}))
+		assert.Nil(t, dws.AddToWatchlist(ctx, 1, 3))
+		items, _ := dws.GetWatchlist(ctx, 1)
+		assert.Equal(t, []int{2, 3}, movieIDs(items))
+
+		assert.Nil(t, movies.DeleteMovie(ctx, 3))
+
+		items, _ = dws.GetWatchlist(ctx, 1)
+		assert.Equal(t, []int{2}, movieIDs(items))
+
+		entries, _ := dws.GetWatched(ctx, 1)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, 1, entries[0].MovieID)
+	})
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrWebhookURLIsNotValid     = errors.New("webhook url must be an absolute http or https URL")
+	ErrEventTypeIsNotValid      = errors.New("event types must be movie.created, movie.updated or movie.deleted")
+	ErrDeliveryStatusIsNotValid = errors.New("delivery status must be pending, succeeded or dead")
+	ErrWebhookNotFound          = errors.New("the webhook cannot be found")
+	ErrWebhookDeliveryNotFound  = errors.New("the webhook delivery cannot be found")
+)
+
+var eventTypes = map[model.EventType]bool{
+	model.EventMovieCreated: true,
+	model.EventMovieUpdated: true,
+	model.EventMovieDeleted: true,
+}
+
+var deliveryStatuses = map[model.DeliveryStatus]bool{
+	model.DeliveryPending:   true,
+	model.DeliverySucceeded: true,
+	model.DeliveryDead:      true,
+}
+
+type DefaultWebhookService struct {
+	webhookRepo repository.IWebhookRepository
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+func NewDefaultWebhookService(wRepo repository.IWebhookRepository, logger *slog.Logger) *DefaultWebhookService {
+	return &DefaultWebhookService{
+		webhookRepo: wRepo,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// GetWebhooks lists the webhooks without their secrets.
+func (d *DefaultWebhookService) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "DefaultWebhookService.GetWebhooks")
+	defer span.End()
+
+	webhooks, err := d.webhookRepo.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for k := range webhooks {
+		webhooks[k].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns the webhook without its secret.
+func (d *DefaultWebhookService) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "DefaultWebhookService.GetWebhook")
+	defer span.End()
+	span.SetAttributes(attribute.Int("webhook.id", id))
+
+	if id <= 0 {
+		return model.Webhook{}, ErrIDIsNotValid
+	}
+
+	webhook, err := d.webhookRepo.GetWebhook(ctx, id)
+	if err != nil {
+		return model.Webhook{}, webhookError(err)
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// CreateWebhook creates an active webhook with a new random secret.
+func (d *DefaultWebhookService) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "DefaultWebhookService.CreateWebhook")
+	defer span.End()
+
+	if err := validateWebhookURL(webhook.URL); err != nil {
+		return model.Webhook{}, err
+	}
+	events, err := normalizeEventTypes(webhook.Events)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	created, err := d.webhookRepo.CreateWebhook(ctx, model.Webhook{
+		URL:    webhook.URL,
+		Events: events,
+		Secret: secret,
+		Active: true,
+	})
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	d.logger.InfoContext(ctx, "webhook created", "webhook_id", created.ID, "url", created.URL)
+	return created, nil
+}
+
+func (d *DefaultWebhookService) UpdateWebhook(ctx context.Context, id int, patch model.WebhookPatch) error {
+	ctx, span := tracer.Start(ctx, "DefaultWebhookService.UpdateWebhook")
+	defer span.End()
+	span.SetAttributes(attribute.Int("webhook.id", id))
+
+	if id <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	webhook, err := d.webhookRepo.GetWebhook(ctx, id)
+	if err != nil {
+		return webhookError(err)
+	}
+
+	if patch.URL != nil {
+		if err := validateWebhookURL(*patch.URL); err != nil {
+			return err
+		}
+		webhook.URL = *patch.URL
+	}
+	if patch.Events != nil {
+		if webhook.Events, err = normalizeEventTypes(*patch.Events); err != nil {
+			return err
+		}
+	}
+	if patch.Active != nil {
+		webhook.Active = *patch.Active
+	}
+
+	if err := d.webhookRepo.UpdateWebhook(ctx, id, webhook); err != nil {
+		return webhookError(err)
+	}
+
+	d.logger.InfoContext(ctx, "webhook updated", "webhook_id", id)
+	return nil
+}
+
+func (d *DefaultWebhookService) DeleteWebhook(ctx context.Context, id int) error {
+	ctx, span := tracer.Start(ctx, "DefaultWebhookService.DeleteWebhook")
+	defer span.End()
+	span.SetAttributes(attribute.Int("webhook.id", id))
+
+	if id <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	if err := d.webhookRepo.DeleteWebhook(ctx, id); err != nil {
+		return webhookError(err)
+	}
+
+	d.logger.InfoContext(ctx, "webhook deleted", "webhook_id", id)
+	return nil
+}
+
+// GetDeliveries returns the latest deliveries of the webhook, the dead letters when
+// status is model.DeliveryDead.
+func (d *DefaultWebhookService) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "DefaultWebhookService.GetDeliveries")
+	defer span.End()
+	span.SetAttributes(attribute.Int("webhook.id", webhookID), attribute.String("delivery.status", string(status)))
+
+	if webhookID <= 0 {
+		return nil, ErrIDIsNotValid
+	}
+	if status != "" && !deliveryStatuses[status] {
+		return nil, ErrDeliveryStatusIsNotValid
+	}
+	if limit < 1 || limit > MaxLimit {
+		return nil, ErrLimitIsNotValid
+	}
+
+	deliveries, err := d.webhookRepo.GetDeliveries(ctx, webhookID, status, limit)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	return deliveries, nil
+}
+
+// RedeliverDelivery sends the delivery again as soon as possible, with the full number
+// of attempts. It is meant for dead letters but takes any delivery.
+func (d *DefaultWebhookService) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64) error {
+	ctx, span := tracer.Start(ctx, "DefaultWebhookService.RedeliverDelivery")
+	defer span.End()
+	span.SetAttributes(attribute.Int("webhook.id", webhookID), attribute.Int64("delivery.id", deliveryID))
+
+	if webhookID <= 0 || deliveryID <= 0 {
+		return ErrIDIsNotValid
+	}
+
+	if err := d.webhookRepo.RedeliverDelivery(ctx, webhookID, deliveryID, d.now()); err != nil {
+		return webhookError(err)
+	}
+
+	d.logger.InfoContext(ctx, "webhook delivery scheduled again", "webhook_id", webhookID, "delivery_id", deliveryID)
+	return nil
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrWebhookURLIsNotValid
+	}
+	return nil
+}
+
+// normalizeEventTypes checks the event types and drops the repeated ones.
+func normalizeEventTypes(events []model.EventType) ([]model.EventType, error) {
+	normalized := make([]model.EventType, 0, len(events))
+	seen := make(map[model.EventType]bool)
+	for _, event := range events {
+		if !eventTypes[event] {
+			return nil, ErrEventTypeIsNotValid
+		}
+		if !seen[event] {
+			seen[event] = true
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func webhookError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrWebhookNotFound):
+		return ErrWebhookNotFound
+	case errors.Is(err, repository.ErrWebhookDeliveryNotFound):
+		return ErrWebhookDeliveryNotFound
+	}
+	return err
+}
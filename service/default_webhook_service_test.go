@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDefaultWebhookService_CreateWebhook(t *testing.T) {
+	t.Run("Error Create Webhook - invalid webhook", func(t *testing.T) {
+		testCases := []struct {
+			webhook model.Webhook
+			err     error
+		}{
+			{webhook: model.Webhook{URL: ""}, err: ErrWebhookURLIsNotValid},
+			{webhook: model.Webhook{URL: "ftp://example.com/hooks"}, err: ErrWebhookURLIsNotValid},
+			{webhook: model.Webhook{URL: "/hooks"}, err: ErrWebhookURLIsNotValid},
+			{webhook: model.Webhook{URL: "https://example.com/hooks", Events: []model.EventType{"movie.rated"}}, err: ErrEventTypeIsNotValid},
+		}
+
+		for _, test := range testCases {
+			dws := NewDefaultWebhookService(nil, logging.NewNop())
+			_, err := dws.CreateWebhook(context.Background(), test.webhook)
+			assert.ErrorIs(t, err, test.err)
+			assert.True(t, IsValidationError(err))
+		}
+	})
+	t.Run("Success Create Webhook - active with a new secret", func(t *testing.T) {
+		mockRepository := repository.NewMockIWebhookRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+				webhook.ID = 1
+				return webhook, nil
+			}).
+			Times(1)
+
+		dws := NewDefaultWebhookService(mockRepository, logging.NewNop())
+		created, err := dws.CreateWebhook(context.Background(), model.Webhook{
+			URL:    "https://example.com/hooks",
+			Events: []model.EventType{model.EventMovieCreated, model.EventMovieCreated},
+			Secret: "chosen-by-the-client",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, created.ID)
+		assert.True(t, created.Active)
+		assert.Equal(t, []model.EventType{model.EventMovieCreated}, created.Events)
+		assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+		assert.Len(t, created.Secret, len("whsec_")+64)
+	})
+}
+
+func TestDefaultWebhookService_GetWebhook(t *testing.T) {
+	t.Run("Success Get Webhook - secret is hidden", func(t *testing.T) {
+		mockRepository := repository.NewMockIWebhookRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().GetWebhook(gomock.Any(), 1).
+			Return(model.Webhook{ID: 1, URL: "https://example.com/hooks", Secret: "whsec_test"}, nil).
+			Times(1)
+
+		dws := NewDefaultWebhookService(mockRepository, logging.NewNop())
+		webhook, err := dws.GetWebhook(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Empty(t, webhook.Secret)
+	})
+	t.Run("Error Get Webhook - not found", func(t *testing.T) {
+		mockRepository := repository.NewMockIWebhookRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().GetWebhook(gomock.Any(), 2).
+			Return(model.Webhook{}, repository.ErrWebhookNotFound).
+			Times(1)
+
+		dws := NewDefaultWebhookService(mockRepository, logging.NewNop())
+		_, err := dws.GetWebhook(context.Background(), 2)
+
+		assert.ErrorIs(t, err, ErrWebhookNotFound)
+	})
+}
+
+func TestDefaultWebhookService_UpdateWebhook(t *testing.T) {
+	t.Run("Success Update Webhook - only the given fields change", func(t *testing.T) {
+		stored := model.Webhook{ID: 1, URL: "https://example.com/hooks", Events: []model.EventType{model.EventMovieDeleted}, Secret: "whsec_test", Active: true}
+		active := false
+
+		mockRepository := repository.NewMockIWebhookRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().GetWebhook(gomock.Any(), 1).
+			Return(stored, nil).
+			Times(1)
+		updated := stored
+		updated.Active = false
+		mockRepository.
+			EXPECT().UpdateWebhook(gomock.Any(), 1, updated).
+			Return(nil).
+			Times(1)
+
+		dws := NewDefaultWebhookService(mockRepository, logging.NewNop())
+		err := dws.UpdateWebhook(context.Background(), 1, model.WebhookPatch{Active: &active})
+
+		assert.Nil(t, err)
+	})
+	t.Run("Error Update Webhook - invalid url", func(t *testing.T) {
+		url := "example.com"
+		mockRepository := repository.NewMockIWebhookRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().GetWebhook(gomock.Any(), 1).
+			Return(model.Webhook{ID: 1, URL: "https://example.com/hooks"}, nil).
+			Times(1)
+
+		dws := NewDefaultWebhookService(mockRepository, logging.NewNop())
+		err := dws.UpdateWebhook(context.Background(), 1, model.WebhookPatch{URL: &url})
+
+		assert.ErrorIs(t, err, ErrWebhookURLIsNotValid)
+	})
+}
+
+func TestDefaultWebhookService_GetDeliveries(t *testing.T) {
+	t.Run("Error Get Deliveries - invalid parameters", func(t *testing.T) {
+		testCases := []struct {
+			status model.DeliveryStatus
+			limit  int
+			err    error
+		}{
+			{status: "failed", limit: 10, err: ErrDeliveryStatusIsNotValid},
+			{status: model.DeliveryDead, limit: 0, err: ErrLimitIsNotValid},
+			{status: "", limit: MaxLimit + 1, err: ErrLimitIsNotValid},
+		}
+
+		for _, test := range testCases {
+			dws := NewDefaultWebhookService(nil, logging.NewNop())
+			_, err := dws.GetDeliveries(context.Background(), 1, test.status, test.limit)
+			assert.ErrorIs(t, err, test.err)
+		}
+	})
+	t.Run("Success Get Deliveries - dead letters", func(t *testing.T) {
+		dead := []model.WebhookDelivery{{ID: 3, WebhookID: 1, Status: model.DeliveryDead, Attempts: 10}}
+		mockRepository := repository.NewMockIWebhookRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().GetDeliveries(gomock.Any(), 1, model.DeliveryDead, 20).
+			Return(dead, nil).
+			Times(1)
+
+		dws := NewDefaultWebhookService(mockRepository, logging.NewNop())
+		deliveries, err := dws.GetDeliveries(context.Background(), 1, model.DeliveryDead, 20)
+
+		assert.Nil(t, err)
+		assert.Equal(t, dead, deliveries)
+	})
+}
+
+func TestDefaultWebhookService_RedeliverDelivery(t *testing.T) {
+	t.Run("Error Redeliver Delivery - not found", func(t *testing.T) {
+		mockRepository := repository.NewMockIWebhookRepository(gomock.NewController(t))
+		mockRepository.
+			EXPECT().RedeliverDelivery(gomock.Any(), 1, int64(9), gomock.Any()).
+			Return(repository.ErrWebhookDeliveryNotFound).
+			Times(1)
+
+		dws := NewDefaultWebhookService(mockRepository, logging.NewNop())
+		err := dws.RedeliverDelivery(context.Background(), 1, 9)
+
+		assert.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+	})
+}
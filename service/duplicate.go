@@ -0,0 +1,94 @@
+package service
+
+import (
+	"github.com/dilaragorum/movie-go/model"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// nearDuplicateSimilarity is the title similarity from which a movie released within a
+// year of a new one is reported as a possible duplicate.
+const nearDuplicateSimilarity = 0.85
+
+// titleKey normalizes a title for comparison: accents are stripped, letters are lower
+// cased, punctuation is dropped and words are separated by single spaces, so that
+// "Amélie" and "amelie", or "Spider-Man" and "Spider Man", have the same key.
+func titleKey(title string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// A combining mark, the accent of a decomposed letter.
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-' || r == '_' || r == '/':
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// titleSimilarity compares two title keys, 1 for equal keys and 0 for keys without
+// anything in common, based on their Levenshtein distance.
+func titleSimilarity(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for k := range previous {
+		previous[k] = k
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// duplicateOf compares movie, whose title key is key, with an existing one. Only the
+// same title and release year make an exact duplicate, an unknown year does not.
+func duplicateOf(movie model.Movie, key string, existing model.Movie) (model.Duplicate, bool) {
+	similarity := titleSimilarity(key, titleKey(existing.Title))
+
+	if similarity == 1 && movie.ReleaseYear != 0 && movie.ReleaseYear == existing.ReleaseYear {
+		return model.Duplicate{Movie: existing, Similarity: 1, Exact: true}, true
+	}
+	if similarity >= nearDuplicateSimilarity {
+		return model.Duplicate{Movie: existing, Similarity: similarity}, true
+	}
+	return model.Duplicate{}, false
+}
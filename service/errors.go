@@ -0,0 +1,42 @@
+package service
+
+import "errors"
+
+var notFoundErrors = []error{
+	ErrMovieNotFound,
+	ErrPersonNotFound,
+	ErrCreditNotFound,
+	ErrRatingNotFound,
+	ErrReviewNotFound,
+	ErrWatchlistItemNotFound,
+	ErrWatchedEntryNotFound,
+	ErrWebhookNotFound,
+	ErrWebhookDeliveryNotFound,
+}
+
+var conflictErrors = []error{
+	ErrMovieAlreadyExists,
+}
+
+// IsNotFoundError reports whether err means that the requested entity does not exist.
+// With IsValidationError and IsConflictError it lets every API map the service errors
+// to the same status codes.
+func IsNotFoundError(err error) bool {
+	for _, notFound := range notFoundErrors {
+		if errors.Is(err, notFound) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsConflictError reports whether err means that the change clashes with the stored
+// state, such as creating a movie that already exists.
+func IsConflictError(err error) bool {
+	for _, conflict := range conflictErrors {
+		if errors.Is(err, conflict) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@
 package service
 
 import (
+	context "context"
 	reflect "reflect"
 
 	model "github.com/dilaragorum/movie-go/model"
@@ -35,87 +36,132 @@ func (m *MockIMovieService) EXPECT() *MockIMovieServiceMockRecorder {
 }
 
 // CreateMovie mocks base method.
-func (m *MockIMovieService) CreateMovie(movie model.Movie) error {
+func (m *MockIMovieService) CreateMovie(ctx context.Context, movie model.Movie) ([]model.Duplicate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMovie", movie)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateMovie", ctx, movie)
+	ret0, _ := ret[0].([]model.Duplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateMovie indicates an expected call of CreateMovie.
-func (mr *MockIMovieServiceMockRecorder) CreateMovie(movie interface{}) *gomock.Call {
+func (mr *MockIMovieServiceMockRecorder) CreateMovie(ctx, movie interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockIMovieService)(nil).CreateMovie), movie)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockIMovieService)(nil).CreateMovie), ctx, movie)
 }
 
 // DeleteAllMovie mocks base method.
-func (m *MockIMovieService) DeleteAllMovie() error {
+func (m *MockIMovieService) DeleteAllMovie(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAllMovie")
+	ret := m.ctrl.Call(m, "DeleteAllMovie", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAllMovie indicates an expected call of DeleteAllMovie.
-func (mr *MockIMovieServiceMockRecorder) DeleteAllMovie() *gomock.Call {
+func (mr *MockIMovieServiceMockRecorder) DeleteAllMovie(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllMovie", reflect.TypeOf((*MockIMovieService)(nil).DeleteAllMovie))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllMovie", reflect.TypeOf((*MockIMovieService)(nil).DeleteAllMovie), ctx)
 }
 
 // DeleteMovie mocks base method.
-func (m *MockIMovieService) DeleteMovie(id int) error {
+func (m *MockIMovieService) DeleteMovie(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMovie", id)
+	ret := m.ctrl.Call(m, "DeleteMovie", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteMovie indicates an expected call of DeleteMovie.
-func (mr *MockIMovieServiceMockRecorder) DeleteMovie(id interface{}) *gomock.Call {
+func (mr *MockIMovieServiceMockRecorder) DeleteMovie(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockIMovieService)(nil).DeleteMovie), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockIMovieService)(nil).DeleteMovie), ctx, id)
 }
 
 // GetMovie mocks base method.
-func (m *MockIMovieService) GetMovie(id int) (model.Movie, error) {
+func (m *MockIMovieService) GetMovie(ctx context.Context, id int) (model.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovie", id)
+	ret := m.ctrl.Call(m, "GetMovie", ctx, id)
 	ret0, _ := ret[0].(model.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovie indicates an expected call of GetMovie.
-func (mr *MockIMovieServiceMockRecorder) GetMovie(id interface{}) *gomock.Call {
+func (mr *MockIMovieServiceMockRecorder) GetMovie(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovie", reflect.TypeOf((*MockIMovieService)(nil).GetMovie), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovie", reflect.TypeOf((*MockIMovieService)(nil).GetMovie), ctx, id)
+}
+
+// GetMovieStats mocks base method.
+func (m *MockIMovieService) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovieStats", ctx, filter, topN)
+	ret0, _ := ret[0].(model.MovieStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovieStats indicates an expected call of GetMovieStats.
+func (mr *MockIMovieServiceMockRecorder) GetMovieStats(ctx, filter, topN interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieStats", reflect.TypeOf((*MockIMovieService)(nil).GetMovieStats), ctx, filter, topN)
 }
 
 // GetMovies mocks base method.
-func (m *MockIMovieService) GetMovies() ([]model.Movie, error) {
+func (m *MockIMovieService) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovies")
+	ret := m.ctrl.Call(m, "GetMovies", ctx, filter)
 	ret0, _ := ret[0].([]model.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovies indicates an expected call of GetMovies.
-func (mr *MockIMovieServiceMockRecorder) GetMovies() *gomock.Call {
+func (mr *MockIMovieServiceMockRecorder) GetMovies(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockIMovieService)(nil).GetMovies), ctx, filter)
+}
+
+// MergeMovies mocks base method.
+func (m *MockIMovieService) MergeMovies(ctx context.Context, targetID, sourceID int) (model.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeMovies", ctx, targetID, sourceID)
+	ret0, _ := ret[0].(model.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeMovies indicates an expected call of MergeMovies.
+func (mr *MockIMovieServiceMockRecorder) MergeMovies(ctx, targetID, sourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeMovies", reflect.TypeOf((*MockIMovieService)(nil).MergeMovies), ctx, targetID, sourceID)
+}
+
+// StreamMovies mocks base method.
+func (m *MockIMovieService) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(model.Movie) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamMovies", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamMovies indicates an expected call of StreamMovies.
+func (mr *MockIMovieServiceMockRecorder) StreamMovies(ctx, filter, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockIMovieService)(nil).GetMovies))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamMovies", reflect.TypeOf((*MockIMovieService)(nil).StreamMovies), ctx, filter, fn)
 }
 
 // UpdateMovie mocks base method.
-func (m *MockIMovieService) UpdateMovie(id int, movie model.Movie) error {
+func (m *MockIMovieService) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateMovie", id, movie)
+	ret := m.ctrl.Call(m, "UpdateMovie", ctx, id, movie)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateMovie indicates an expected call of UpdateMovie.
-func (mr *MockIMovieServiceMockRecorder) UpdateMovie(id, movie interface{}) *gomock.Call {
+func (mr *MockIMovieServiceMockRecorder) UpdateMovie(ctx, id, movie interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockIMovieService)(nil).UpdateMovie), id, movie)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockIMovieService)(nil).UpdateMovie), ctx, id, movie)
 }
@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/person_service_interface.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIPersonService is a mock of IPersonService interface.
+type MockIPersonService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPersonServiceMockRecorder
+}
+
+// MockIPersonServiceMockRecorder is the mock recorder for MockIPersonService.
+type MockIPersonServiceMockRecorder struct {
+	mock *MockIPersonService
+}
+
+// NewMockIPersonService creates a new mock instance.
+func NewMockIPersonService(ctrl *gomock.Controller) *MockIPersonService {
+	mock := &MockIPersonService{ctrl: ctrl}
+	mock.recorder = &MockIPersonServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPersonService) EXPECT() *MockIPersonServiceMockRecorder {
+	return m.recorder
+}
+
+// AddCredit mocks base method.
+func (m *MockIPersonService) AddCredit(ctx context.Context, credit model.Credit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCredit", ctx, credit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCredit indicates an expected call of AddCredit.
+func (mr *MockIPersonServiceMockRecorder) AddCredit(ctx, credit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCredit", reflect.TypeOf((*MockIPersonService)(nil).AddCredit), ctx, credit)
+}
+
+// CreatePerson mocks base method.
+func (m *MockIPersonService) CreatePerson(ctx context.Context, person model.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePerson", ctx, person)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePerson indicates an expected call of CreatePerson.
+func (mr *MockIPersonServiceMockRecorder) CreatePerson(ctx, person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePerson", reflect.TypeOf((*MockIPersonService)(nil).CreatePerson), ctx, person)
+}
+
+// DeleteCredit mocks base method.
+func (m *MockIPersonService) DeleteCredit(ctx context.Context, movieID, creditID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCredit", ctx, movieID, creditID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCredit indicates an expected call of DeleteCredit.
+func (mr *MockIPersonServiceMockRecorder) DeleteCredit(ctx, movieID, creditID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCredit", reflect.TypeOf((*MockIPersonService)(nil).DeleteCredit), ctx, movieID, creditID)
+}
+
+// DeletePerson mocks base method.
+func (m *MockIPersonService) DeletePerson(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePerson", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePerson indicates an expected call of DeletePerson.
+func (mr *MockIPersonServiceMockRecorder) DeletePerson(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePerson", reflect.TypeOf((*MockIPersonService)(nil).DeletePerson), ctx, id)
+}
+
+// GetCreditsByMovies mocks base method.
+func (m *MockIPersonService) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreditsByMovies", ctx, movieIDs)
+	ret0, _ := ret[0].(map[int][]model.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreditsByMovies indicates an expected call of GetCreditsByMovies.
+func (mr *MockIPersonServiceMockRecorder) GetCreditsByMovies(ctx, movieIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditsByMovies", reflect.TypeOf((*MockIPersonService)(nil).GetCreditsByMovies), ctx, movieIDs)
+}
+
+// GetMovieCredits mocks base method.
+func (m *MockIPersonService) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovieCredits", ctx, movieID)
+	ret0, _ := ret[0].([]model.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovieCredits indicates an expected call of GetMovieCredits.
+func (mr *MockIPersonServiceMockRecorder) GetMovieCredits(ctx, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieCredits", reflect.TypeOf((*MockIPersonService)(nil).GetMovieCredits), ctx, movieID)
+}
+
+// GetPeople mocks base method.
+func (m *MockIPersonService) GetPeople(ctx context.Context) ([]model.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeople", ctx)
+	ret0, _ := ret[0].([]model.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeople indicates an expected call of GetPeople.
+func (mr *MockIPersonServiceMockRecorder) GetPeople(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeople", reflect.TypeOf((*MockIPersonService)(nil).GetPeople), ctx)
+}
+
+// GetPerson mocks base method.
+func (m *MockIPersonService) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPerson", ctx, id)
+	ret0, _ := ret[0].(model.Person)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPerson indicates an expected call of GetPerson.
+func (mr *MockIPersonServiceMockRecorder) GetPerson(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPerson", reflect.TypeOf((*MockIPersonService)(nil).GetPerson), ctx, id)
+}
+
+// GetPersonMovies mocks base method.
+func (m *MockIPersonService) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonMovies", ctx, personID, role)
+	ret0, _ := ret[0].([]model.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonMovies indicates an expected call of GetPersonMovies.
+func (mr *MockIPersonServiceMockRecorder) GetPersonMovies(ctx, personID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonMovies", reflect.TypeOf((*MockIPersonService)(nil).GetPersonMovies), ctx, personID, role)
+}
+
+// UpdatePerson mocks base method.
+func (m *MockIPersonService) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePerson", ctx, id, person)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePerson indicates an expected call of UpdatePerson.
+func (mr *MockIPersonServiceMockRecorder) UpdatePerson(ctx, id, person interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePerson", reflect.TypeOf((*MockIPersonService)(nil).UpdatePerson), ctx, id, person)
+}
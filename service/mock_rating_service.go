@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/rating_service_interface.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRatingService is a mock of IRatingService interface.
+type MockIRatingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRatingServiceMockRecorder
+}
+
+// MockIRatingServiceMockRecorder is the mock recorder for MockIRatingService.
+type MockIRatingServiceMockRecorder struct {
+	mock *MockIRatingService
+}
+
+// NewMockIRatingService creates a new mock instance.
+func NewMockIRatingService(ctrl *gomock.Controller) *MockIRatingService {
+	mock := &MockIRatingService{ctrl: ctrl}
+	mock.recorder = &MockIRatingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRatingService) EXPECT() *MockIRatingServiceMockRecorder {
+	return m.recorder
+}
+
+// AddReview mocks base method.
+func (m *MockIRatingService) AddReview(ctx context.Context, review model.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReview", ctx, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReview indicates an expected call of AddReview.
+func (mr *MockIRatingServiceMockRecorder) AddReview(ctx, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReview", reflect.TypeOf((*MockIRatingService)(nil).AddReview), ctx, review)
+}
+
+// DeleteRating mocks base method.
+func (m *MockIRatingService) DeleteRating(ctx context.Context, movieID, userID int) (model.RatingSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRating", ctx, movieID, userID)
+	ret0, _ := ret[0].(model.RatingSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRating indicates an expected call of DeleteRating.
+func (mr *MockIRatingServiceMockRecorder) DeleteRating(ctx, movieID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRating", reflect.TypeOf((*MockIRatingService)(nil).DeleteRating), ctx, movieID, userID)
+}
+
+// DeleteReview mocks base method.
+func (m *MockIRatingService) DeleteReview(ctx context.Context, movieID, reviewID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", ctx, movieID, reviewID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockIRatingServiceMockRecorder) DeleteReview(ctx, movieID, reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockIRatingService)(nil).DeleteReview), ctx, movieID, reviewID)
+}
+
+// GetRatings mocks base method.
+func (m *MockIRatingService) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatings", ctx, movieID)
+	ret0, _ := ret[0].([]model.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatings indicates an expected call of GetRatings.
+func (mr *MockIRatingServiceMockRecorder) GetRatings(ctx, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatings", reflect.TypeOf((*MockIRatingService)(nil).GetRatings), ctx, movieID)
+}
+
+// GetReviews mocks base method.
+func (m *MockIRatingService) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx, movieID)
+	ret0, _ := ret[0].([]model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockIRatingServiceMockRecorder) GetReviews(ctx, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockIRatingService)(nil).GetReviews), ctx, movieID)
+}
+
+// GetReviewsByMovies mocks base method.
+func (m *MockIRatingService) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByMovies", ctx, movieIDs)
+	ret0, _ := ret[0].(map[int][]model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByMovies indicates an expected call of GetReviewsByMovies.
+func (mr *MockIRatingServiceMockRecorder) GetReviewsByMovies(ctx, movieIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByMovies", reflect.TypeOf((*MockIRatingService)(nil).GetReviewsByMovies), ctx, movieIDs)
+}
+
+// RateMovie mocks base method.
+func (m *MockIRatingService) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateMovie", ctx, rating)
+	ret0, _ := ret[0].(model.RatingSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RateMovie indicates an expected call of RateMovie.
+func (mr *MockIRatingServiceMockRecorder) RateMovie(ctx, rating interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateMovie", reflect.TypeOf((*MockIRatingService)(nil).RateMovie), ctx, rating)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/recommendation_service_interface.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRecommendationService is a mock of IRecommendationService interface.
+type MockIRecommendationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRecommendationServiceMockRecorder
+}
+
+// MockIRecommendationServiceMockRecorder is the mock recorder for MockIRecommendationService.
+type MockIRecommendationServiceMockRecorder struct {
+	mock *MockIRecommendationService
+}
+
+// NewMockIRecommendationService creates a new mock instance.
+func NewMockIRecommendationService(ctrl *gomock.Controller) *MockIRecommendationService {
+	mock := &MockIRecommendationService{ctrl: ctrl}
+	mock.recorder = &MockIRecommendationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRecommendationService) EXPECT() *MockIRecommendationServiceMockRecorder {
+	return m.recorder
+}
+
+// GetRecommendations mocks base method.
+func (m *MockIRecommendationService) GetRecommendations(ctx context.Context, userID, limit int) ([]model.Recommendation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendations", ctx, userID, limit)
+	ret0, _ := ret[0].([]model.Recommendation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendations indicates an expected call of GetRecommendations.
+func (mr *MockIRecommendationServiceMockRecorder) GetRecommendations(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockIRecommendationService)(nil).GetRecommendations), ctx, userID, limit)
+}
+
+// GetSimilarMovies mocks base method.
+func (m *MockIRecommendationService) GetSimilarMovies(ctx context.Context, movieID, limit int) ([]model.SimilarMovie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimilarMovies", ctx, movieID, limit)
+	ret0, _ := ret[0].([]model.SimilarMovie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimilarMovies indicates an expected call of GetSimilarMovies.
+func (mr *MockIRecommendationServiceMockRecorder) GetSimilarMovies(ctx, movieID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimilarMovies", reflect.TypeOf((*MockIRecommendationService)(nil).GetSimilarMovies), ctx, movieID, limit)
+}
+
+// RefreshNeighbours mocks base method.
+func (m *MockIRecommendationService) RefreshNeighbours(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshNeighbours", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshNeighbours indicates an expected call of RefreshNeighbours.
+func (mr *MockIRecommendationServiceMockRecorder) RefreshNeighbours(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshNeighbours", reflect.TypeOf((*MockIRecommendationService)(nil).RefreshNeighbours), ctx)
+}
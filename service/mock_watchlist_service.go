@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/watchlist_service_interface.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWatchlistService is a mock of IWatchlistService interface.
+type MockIWatchlistService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWatchlistServiceMockRecorder
+}
+
+// MockIWatchlistServiceMockRecorder is the mock recorder for MockIWatchlistService.
+type MockIWatchlistServiceMockRecorder struct {
+	mock *MockIWatchlistService
+}
+
+// NewMockIWatchlistService creates a new mock instance.
+func NewMockIWatchlistService(ctrl *gomock.Controller) *MockIWatchlistService {
+	mock := &MockIWatchlistService{ctrl: ctrl}
+	mock.recorder = &MockIWatchlistServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWatchlistService) EXPECT() *MockIWatchlistServiceMockRecorder {
+	return m.recorder
+}
+
+// AddToWatchlist mocks base method.
+func (m *MockIWatchlistService) AddToWatchlist(ctx context.Context, userID, movieID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToWatchlist", ctx, userID, movieID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToWatchlist indicates an expected call of AddToWatchlist.
+func (mr *MockIWatchlistServiceMockRecorder) AddToWatchlist(ctx, userID, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToWatchlist", reflect.TypeOf((*MockIWatchlistService)(nil).AddToWatchlist), ctx, userID, movieID)
+}
+
+// AddWatched mocks base method.
+func (m *MockIWatchlistService) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWatched", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWatched indicates an expected call of AddWatched.
+func (mr *MockIWatchlistServiceMockRecorder) AddWatched(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWatched", reflect.TypeOf((*MockIWatchlistService)(nil).AddWatched), ctx, entry)
+}
+
+// DeleteWatched mocks base method.
+func (m *MockIWatchlistService) DeleteWatched(ctx context.Context, userID, entryID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatched", ctx, userID, entryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatched indicates an expected call of DeleteWatched.
+func (mr *MockIWatchlistServiceMockRecorder) DeleteWatched(ctx, userID, entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatched", reflect.TypeOf((*MockIWatchlistService)(nil).DeleteWatched), ctx, userID, entryID)
+}
+
+// GetWatched mocks base method.
+func (m *MockIWatchlistService) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatched", ctx, userID)
+	ret0, _ := ret[0].([]model.WatchedEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatched indicates an expected call of GetWatched.
+func (mr *MockIWatchlistServiceMockRecorder) GetWatched(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatched", reflect.TypeOf((*MockIWatchlistService)(nil).GetWatched), ctx, userID)
+}
+
+// GetWatchlist mocks base method.
+func (m *MockIWatchlistService) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlist", ctx, userID)
+	ret0, _ := ret[0].([]model.WatchlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlist indicates an expected call of GetWatchlist.
+func (mr *MockIWatchlistServiceMockRecorder) GetWatchlist(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlist", reflect.TypeOf((*MockIWatchlistService)(nil).GetWatchlist), ctx, userID)
+}
+
+// RemoveFromWatchlist mocks base method.
+func (m *MockIWatchlistService) RemoveFromWatchlist(ctx context.Context, userID, movieID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromWatchlist", ctx, userID, movieID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromWatchlist indicates an expected call of RemoveFromWatchlist.
+func (mr *MockIWatchlistServiceMockRecorder) RemoveFromWatchlist(ctx, userID, movieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromWatchlist", reflect.TypeOf((*MockIWatchlistService)(nil).RemoveFromWatchlist), ctx, userID, movieID)
+}
+
+// ReorderWatchlist mocks base method.
+func (m *MockIWatchlistService) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderWatchlist", ctx, userID, movieIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderWatchlist indicates an expected call of ReorderWatchlist.
+func (mr *MockIWatchlistServiceMockRecorder) ReorderWatchlist(ctx, userID, movieIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderWatchlist", reflect.TypeOf((*MockIWatchlistService)(nil).ReorderWatchlist), ctx, userID, movieIDs)
+}
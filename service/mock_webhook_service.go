@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/webhook_service_interface.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/dilaragorum/movie-go/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWebhookService is a mock of IWebhookService interface.
+type MockIWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookServiceMockRecorder
+}
+
+// MockIWebhookServiceMockRecorder is the mock recorder for MockIWebhookService.
+type MockIWebhookServiceMockRecorder struct {
+	mock *MockIWebhookService
+}
+
+// NewMockIWebhookService creates a new mock instance.
+func NewMockIWebhookService(ctrl *gomock.Controller) *MockIWebhookService {
+	mock := &MockIWebhookService{ctrl: ctrl}
+	mock.recorder = &MockIWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebhookService) EXPECT() *MockIWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockIWebhookService) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockIWebhookServiceMockRecorder) CreateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIWebhookService)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockIWebhookService) DeleteWebhook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockIWebhookServiceMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIWebhookService)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockIWebhookService) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookID, status, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockIWebhookServiceMockRecorder) GetDeliveries(ctx, webhookID, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockIWebhookService)(nil).GetDeliveries), ctx, webhookID, status, limit)
+}
+
+// GetWebhook mocks base method.
+func (m *MockIWebhookService) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockIWebhookServiceMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockIWebhookService)(nil).GetWebhook), ctx, id)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockIWebhookService) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockIWebhookServiceMockRecorder) GetWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockIWebhookService)(nil).GetWebhooks), ctx)
+}
+
+// RedeliverDelivery mocks base method.
+func (m *MockIWebhookService) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverDelivery", ctx, webhookID, deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverDelivery indicates an expected call of RedeliverDelivery.
+func (mr *MockIWebhookServiceMockRecorder) RedeliverDelivery(ctx, webhookID, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverDelivery", reflect.TypeOf((*MockIWebhookService)(nil).RedeliverDelivery), ctx, webhookID, deliveryID)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockIWebhookService) UpdateWebhook(ctx context.Context, id int, patch model.WebhookPatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, id, patch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockIWebhookServiceMockRecorder) UpdateWebhook(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockIWebhookService)(nil).UpdateWebhook), ctx, id, patch)
+}
@@ -1,13 +1,22 @@
 package service
 
-import "github.com/dilaragorum/movie-go/model"
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
 
 // mockgen -source service/movie_service_interface.go -destination service/mock_movie_service.go -package service
 type IMovieService interface {
-	GetMovies() ([]model.Movie, error)
-	GetMovie(id int) (model.Movie, error)
-	CreateMovie(movie model.Movie) error
-	DeleteMovie(id int) error
-	DeleteAllMovie() error
-	UpdateMovie(id int, movie model.Movie) error
+	GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error)
+	StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error
+	GetMovie(ctx context.Context, id int) (model.Movie, error)
+	GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error)
+	// CreateMovie rejects an exact duplicate with ErrMovieAlreadyExists and returns the
+	// near duplicates of a created movie, for the caller to review.
+	CreateMovie(ctx context.Context, movie model.Movie) ([]model.Duplicate, error)
+	DeleteMovie(ctx context.Context, id int) error
+	DeleteAllMovie(ctx context.Context) error
+	UpdateMovie(ctx context.Context, id int, movie model.Movie) error
+	// MergeMovies folds the source movie into the target one and returns the result.
+	MergeMovies(ctx context.Context, targetID int, sourceID int) (model.Movie, error)
 }
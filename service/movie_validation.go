@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"github.com/dilaragorum/movie-go/model"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxRuntimeMinutes = 24 * 60
+	maxSynopsisLength = 2000
+
+	// MaxLimit bounds the lists that take a limit or top-N parameter.
+	MaxLimit = 100
+)
+
+var (
+	ErrGenreIsNotValid     = errors.New("genre names cannot be empty")
+	ErrRuntimeIsNotValid   = errors.New("runtime must be between 0 and 1440 minutes")
+	ErrSynopsisIsTooLong   = errors.New("synopsis cannot be longer than 2000 characters")
+	ErrLanguageIsNotValid  = errors.New("original language must be a two-letter ISO 639-1 code")
+	ErrCountryIsNotValid   = errors.New("country must be a two-letter ISO 3166-1 code")
+	ErrAgeRatingIsNotValid = errors.New("age rating must be one of G, PG, PG-13, R, NC-17")
+	ErrPosterURLIsNotValid = errors.New("poster url must be an absolute http or https URL")
+	ErrLimitIsNotValid     = errors.New("limit must be between 1 and 100")
+)
+
+var validationErrors = []error{
+	ErrIDIsNotValid,
+	ErrTitleIsNotEmpty,
+	ErrGenreIsNotValid,
+	ErrRuntimeIsNotValid,
+	ErrSynopsisIsTooLong,
+	ErrLanguageIsNotValid,
+	ErrCountryIsNotValid,
+	ErrAgeRatingIsNotValid,
+	ErrPosterURLIsNotValid,
+	ErrNameIsNotEmpty,
+	ErrRoleIsNotValid,
+	ErrCharacterIsNotValid,
+	ErrBillingOrderIsNotValid,
+	ErrUserIDIsNotValid,
+	ErrRatingIsNotValid,
+	ErrReviewIsNotEmpty,
+	ErrReviewIsTooLong,
+	ErrWatchlistOrderIsNotValid,
+	ErrWatchedAtIsNotValid,
+	ErrLimitIsNotValid,
+	ErrMergeIsNotValid,
+	ErrWebhookURLIsNotValid,
+	ErrEventTypeIsNotValid,
+	ErrDeliveryStatusIsNotValid,
+}
+
+// IsValidationError reports whether err was caused by invalid input rather than by
+// a failing dependency.
+func IsValidationError(err error) bool {
+	for _, validationErr := range validationErrors {
+		if errors.Is(err, validationErr) {
+			return true
+		}
+	}
+	return false
+}
+
+var ageRatings = map[string]bool{"G": true, "PG": true, "PG-13": true, "R": true, "NC-17": true}
+
+// normalizeMovie validates the optional details of movie and returns it in canonical
+// form: genres trimmed, lower case and unique, the language in lower case and the
+// country and age rating in upper case. Zero values are valid, they mean unknown on
+// create and unchanged on update.
+func normalizeMovie(movie model.Movie) (model.Movie, error) {
+	if movie.Genres != nil {
+		genres := make([]string, 0, len(movie.Genres))
+		seen := make(map[string]bool)
+		for _, genre := range movie.Genres {
+			genre = strings.ToLower(strings.TrimSpace(genre))
+			if genre == "" {
+				return model.Movie{}, ErrGenreIsNotValid
+			}
+			if !seen[genre] {
+				seen[genre] = true
+				genres = append(genres, genre)
+			}
+		}
+		movie.Genres = genres
+	}
+
+	if movie.RuntimeMinutes < 0 || movie.RuntimeMinutes > maxRuntimeMinutes {
+		return model.Movie{}, ErrRuntimeIsNotValid
+	}
+
+	if utf8.RuneCountInString(movie.Synopsis) > maxSynopsisLength {
+		return model.Movie{}, ErrSynopsisIsTooLong
+	}
+
+	movie.OriginalLanguage = strings.ToLower(movie.OriginalLanguage)
+	if movie.OriginalLanguage != "" && !isLetterCode(movie.OriginalLanguage) {
+		return model.Movie{}, ErrLanguageIsNotValid
+	}
+
+	movie.Country = strings.ToUpper(movie.Country)
+	if movie.Country != "" && !isLetterCode(movie.Country) {
+		return model.Movie{}, ErrCountryIsNotValid
+	}
+
+	movie.AgeRating = strings.ToUpper(movie.AgeRating)
+	if movie.AgeRating != "" && !ageRatings[movie.AgeRating] {
+		return model.Movie{}, ErrAgeRatingIsNotValid
+	}
+
+	if movie.PosterURL != "" {
+		u, err := url.Parse(movie.PosterURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return model.Movie{}, ErrPosterURLIsNotValid
+		}
+	}
+
+	return movie, nil
+}
+
+// isLetterCode reports whether code is made of two ASCII letters, the shape shared by
+// ISO 639-1 language and ISO 3166-1 alpha-2 country codes.
+func isLetterCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
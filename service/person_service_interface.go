@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source service/person_service_interface.go -destination service/mock_person_service.go -package service
+type IPersonService interface {
+	GetPeople(ctx context.Context) ([]model.Person, error)
+	GetPerson(ctx context.Context, id int) (model.Person, error)
+	CreatePerson(ctx context.Context, person model.Person) error
+	UpdatePerson(ctx context.Context, id int, person model.Person) error
+	DeletePerson(ctx context.Context, id int) error
+	GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error)
+	// GetCreditsByMovies returns the credits of several movies keyed by movie ID, for
+	// callers that would otherwise call GetMovieCredits in a loop. Unknown movies are
+	// left out rather than reported.
+	GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error)
+	GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error)
+	AddCredit(ctx context.Context, credit model.Credit) error
+	DeleteCredit(ctx context.Context, movieID int, creditID int) error
+}
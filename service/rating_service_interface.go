@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source service/rating_service_interface.go -destination service/mock_rating_service.go -package service
+type IRatingService interface {
+	GetRatings(ctx context.Context, movieID int) ([]model.Rating, error)
+	RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error)
+	DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error)
+	GetReviews(ctx context.Context, movieID int) ([]model.Review, error)
+	// GetReviewsByMovies returns the reviews of several movies keyed by movie ID, like
+	// IPersonService.GetCreditsByMovies.
+	GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error)
+	AddReview(ctx context.Context, review model.Review) error
+	DeleteReview(ctx context.Context, movieID int, reviewID int) error
+}
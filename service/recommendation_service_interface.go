@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source service/recommendation_service_interface.go -destination service/mock_recommendation_service.go -package service
+type IRecommendationService interface {
+	GetSimilarMovies(ctx context.Context, movieID int, limit int) ([]model.SimilarMovie, error)
+	GetRecommendations(ctx context.Context, userID int, limit int) ([]model.Recommendation, error)
+	// RefreshNeighbours recomputes the neighbours of every movie and stores them for
+	// the reads above.
+	RefreshNeighbours(ctx context.Context) error
+}
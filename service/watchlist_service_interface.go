@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source service/watchlist_service_interface.go -destination service/mock_watchlist_service.go -package service
+type IWatchlistService interface {
+	GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error)
+	AddToWatchlist(ctx context.Context, userID int, movieID int) error
+	RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error
+	ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error
+	GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error)
+	AddWatched(ctx context.Context, entry model.WatchedEntry) error
+	DeleteWatched(ctx context.Context, userID int, entryID int) error
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+)
+
+// mockgen -source service/webhook_service_interface.go -destination service/mock_webhook_service.go -package service
+type IWebhookService interface {
+	GetWebhooks(ctx context.Context) ([]model.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (model.Webhook, error)
+	// CreateWebhook returns the created webhook with the secret its payloads are signed
+	// with, which is not shown afterwards.
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int, patch model.WebhookPatch) error
+	DeleteWebhook(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64) error
+}
@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"github.com/dilaragorum/movie-go/middleware"
+	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+const instrumentationName = "github.com/dilaragorum/movie-go/tracing"
+
+// Middleware starts a server span named after the route pattern, continuing the trace
+// of an incoming traceparent header if there is one.
+func Middleware(route string, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", route, r)...),
+		)
+		defer span.End()
+
+		sr := middleware.NewStatusRecorder(w)
+		next(sr, r.WithContext(ctx), ps)
+
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(sr.Status)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(sr.Status, trace.SpanKindServer))
+	}
+}
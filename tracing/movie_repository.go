@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedMovieRepository struct {
+	next     repository.IMovieRepository
+	dbSystem attribute.KeyValue
+}
+
+// NewMovieRepository decorates next with one client span per call. dbSystem is the
+// db.system attribute value, e.g. "postgresql" or "memory".
+func NewMovieRepository(next repository.IMovieRepository, dbSystem string) *tracedMovieRepository {
+	return &tracedMovieRepository{next: next, dbSystem: semconv.DBSystemKey.String(dbSystem)}
+}
+
+func (t *tracedMovieRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "IMovieRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.dbSystem, semconv.DBOperationKey.String(method)),
+	)
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *tracedMovieRepository) GetMovies(ctx context.Context, filter model.MovieFilter) ([]model.Movie, error) {
+	ctx, span := t.start(ctx, "GetMovies")
+	movies, err := t.next.GetMovies(ctx, filter)
+	end(span, err)
+	return movies, err
+}
+
+func (t *tracedMovieRepository) StreamMovies(ctx context.Context, filter model.MovieFilter, fn func(movie model.Movie) error) error {
+	ctx, span := t.start(ctx, "StreamMovies")
+	err := t.next.StreamMovies(ctx, filter, fn)
+	end(span, err)
+	return err
+}
+
+func (t *tracedMovieRepository) GetMovie(ctx context.Context, id int) (model.Movie, error) {
+	ctx, span := t.start(ctx, "GetMovie")
+	movie, err := t.next.GetMovie(ctx, id)
+	end(span, err)
+	return movie, err
+}
+
+func (t *tracedMovieRepository) GetMoviesByID(ctx context.Context, ids []int) ([]model.Movie, error) {
+	ctx, span := t.start(ctx, "GetMoviesByID")
+	movies, err := t.next.GetMoviesByID(ctx, ids)
+	end(span, err)
+	return movies, err
+}
+
+func (t *tracedMovieRepository) GetBestScoredMovies(ctx context.Context, limit int, excludedIDs []int) ([]model.Movie, error) {
+	ctx, span := t.start(ctx, "GetBestScoredMovies")
+	movies, err := t.next.GetBestScoredMovies(ctx, limit, excludedIDs)
+	end(span, err)
+	return movies, err
+}
+
+func (t *tracedMovieRepository) GetMovieStats(ctx context.Context, filter model.MovieFilter, topN int) (model.MovieStats, error) {
+	ctx, span := t.start(ctx, "GetMovieStats")
+	stats, err := t.next.GetMovieStats(ctx, filter, topN)
+	end(span, err)
+	return stats, err
+}
+
+func (t *tracedMovieRepository) LockMovieTitle(ctx context.Context, titleKey string, releaseYear int) error {
+	ctx, span := t.start(ctx, "LockMovieTitle")
+	err := t.next.LockMovieTitle(ctx, titleKey, releaseYear)
+	end(span, err)
+	return err
+}
+
+func (t *tracedMovieRepository) CreateMovie(ctx context.Context, movie model.Movie) (int, error) {
+	ctx, span := t.start(ctx, "CreateMovie")
+	id, err := t.next.CreateMovie(ctx, movie)
+	end(span, err)
+	return id, err
+}
+
+func (t *tracedMovieRepository) DeleteMovie(ctx context.Context, id int) error {
+	ctx, span := t.start(ctx, "DeleteMovie")
+	err := t.next.DeleteMovie(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (t *tracedMovieRepository) DeleteAllMovies(ctx context.Context) ([]int, error) {
+	ctx, span := t.start(ctx, "DeleteAllMovies")
+	ids, err := t.next.DeleteAllMovies(ctx)
+	end(span, err)
+	return ids, err
+}
+
+func (t *tracedMovieRepository) UpdateMovie(ctx context.Context, id int, movie model.Movie) error {
+	ctx, span := t.start(ctx, "UpdateMovie")
+	err := t.next.UpdateMovie(ctx, id, movie)
+	end(span, err)
+	return err
+}
+
+func (t *tracedMovieRepository) MergeMovies(ctx context.Context, targetID int, sourceID int) error {
+	ctx, span := t.start(ctx, "MergeMovies")
+	err := t.next.MergeMovies(ctx, targetID, sourceID)
+	end(span, err)
+	return err
+}
+
+func (t *tracedMovieRepository) Ping(ctx context.Context) error {
+	ctx, span := t.start(ctx, "Ping")
+	err := t.next.Ping(ctx)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+type tracedOutboxRepository struct {
+	next     repository.IOutboxRepository
+	dbSystem attribute.KeyValue
+}
+
+// NewOutboxRepository decorates next like NewMovieRepository.
+func NewOutboxRepository(next repository.IOutboxRepository, dbSystem string) *tracedOutboxRepository {
+	return &tracedOutboxRepository{next: next, dbSystem: semconv.DBSystemKey.String(dbSystem)}
+}
+
+func (t *tracedOutboxRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "IOutboxRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.dbSystem, semconv.DBOperationKey.String(method)),
+	)
+}
+
+func (t *tracedOutboxRepository) AppendEvents(ctx context.Context, events []model.Event) error {
+	ctx, span := t.start(ctx, "AppendEvents")
+	err := t.next.AppendEvents(ctx, events)
+	end(span, err)
+	return err
+}
+
+func (t *tracedOutboxRepository) ClaimPendingEvents(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	ctx, span := t.start(ctx, "ClaimPendingEvents")
+	events, err := t.next.ClaimPendingEvents(ctx, limit, lease)
+	end(span, err)
+	return events, err
+}
+
+func (t *tracedOutboxRepository) ReleaseEvents(ctx context.Context, ids []int64) error {
+	ctx, span := t.start(ctx, "ReleaseEvents")
+	err := t.next.ReleaseEvents(ctx, ids)
+	end(span, err)
+	return err
+}
+
+func (t *tracedOutboxRepository) DeleteEvents(ctx context.Context, ids []int64) error {
+	ctx, span := t.start(ctx, "DeleteEvents")
+	err := t.next.DeleteEvents(ctx, ids)
+	end(span, err)
+	return err
+}
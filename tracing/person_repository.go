@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedPersonRepository struct {
+	next     repository.IPersonRepository
+	dbSystem attribute.KeyValue
+}
+
+// NewPersonRepository decorates next like NewMovieRepository.
+func NewPersonRepository(next repository.IPersonRepository, dbSystem string) *tracedPersonRepository {
+	return &tracedPersonRepository{next: next, dbSystem: semconv.DBSystemKey.String(dbSystem)}
+}
+
+func (t *tracedPersonRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "IPersonRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.dbSystem, semconv.DBOperationKey.String(method)),
+	)
+}
+
+func (t *tracedPersonRepository) GetPeople(ctx context.Context) ([]model.Person, error) {
+	ctx, span := t.start(ctx, "GetPeople")
+	people, err := t.next.GetPeople(ctx)
+	end(span, err)
+	return people, err
+}
+
+func (t *tracedPersonRepository) GetPerson(ctx context.Context, id int) (model.Person, error) {
+	ctx, span := t.start(ctx, "GetPerson")
+	person, err := t.next.GetPerson(ctx, id)
+	end(span, err)
+	return person, err
+}
+
+func (t *tracedPersonRepository) CreatePerson(ctx context.Context, person model.Person) error {
+	ctx, span := t.start(ctx, "CreatePerson")
+	err := t.next.CreatePerson(ctx, person)
+	end(span, err)
+	return err
+}
+
+func (t *tracedPersonRepository) UpdatePerson(ctx context.Context, id int, person model.Person) error {
+	ctx, span := t.start(ctx, "UpdatePerson")
+	err := t.next.UpdatePerson(ctx, id, person)
+	end(span, err)
+	return err
+}
+
+func (t *tracedPersonRepository) DeletePerson(ctx context.Context, id int) error {
+	ctx, span := t.start(ctx, "DeletePerson")
+	err := t.next.DeletePerson(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (t *tracedPersonRepository) GetMovieCredits(ctx context.Context, movieID int) ([]model.Credit, error) {
+	ctx, span := t.start(ctx, "GetMovieCredits")
+	credits, err := t.next.GetMovieCredits(ctx, movieID)
+	end(span, err)
+	return credits, err
+}
+
+func (t *tracedPersonRepository) GetCreditsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Credit, error) {
+	ctx, span := t.start(ctx, "GetCreditsByMovies")
+	credits, err := t.next.GetCreditsByMovies(ctx, movieIDs)
+	end(span, err)
+	return credits, err
+}
+
+func (t *tracedPersonRepository) GetPersonMovies(ctx context.Context, personID int, role string) ([]model.Movie, error) {
+	ctx, span := t.start(ctx, "GetPersonMovies")
+	movies, err := t.next.GetPersonMovies(ctx, personID, role)
+	end(span, err)
+	return movies, err
+}
+
+func (t *tracedPersonRepository) AddCredit(ctx context.Context, credit model.Credit) error {
+	ctx, span := t.start(ctx, "AddCredit")
+	err := t.next.AddCredit(ctx, credit)
+	end(span, err)
+	return err
+}
+
+func (t *tracedPersonRepository) DeleteCredit(ctx context.Context, movieID int, creditID int) error {
+	ctx, span := t.start(ctx, "DeleteCredit")
+	err := t.next.DeleteCredit(ctx, movieID, creditID)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedRatingRepository struct {
+	next     repository.IRatingRepository
+	dbSystem attribute.KeyValue
+}
+
+// NewRatingRepository decorates next like NewMovieRepository.
+func NewRatingRepository(next repository.IRatingRepository, dbSystem string) *tracedRatingRepository {
+	return &tracedRatingRepository{next: next, dbSystem: semconv.DBSystemKey.String(dbSystem)}
+}
+
+func (t *tracedRatingRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "IRatingRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.dbSystem, semconv.DBOperationKey.String(method)),
+	)
+}
+
+func (t *tracedRatingRepository) GetRatings(ctx context.Context, movieID int) ([]model.Rating, error) {
+	ctx, span := t.start(ctx, "GetRatings")
+	ratings, err := t.next.GetRatings(ctx, movieID)
+	end(span, err)
+	return ratings, err
+}
+
+func (t *tracedRatingRepository) RateMovie(ctx context.Context, rating model.Rating) (model.RatingSummary, error) {
+	ctx, span := t.start(ctx, "RateMovie")
+	summary, err := t.next.RateMovie(ctx, rating)
+	end(span, err)
+	return summary, err
+}
+
+func (t *tracedRatingRepository) DeleteRating(ctx context.Context, movieID int, userID int) (model.RatingSummary, error) {
+	ctx, span := t.start(ctx, "DeleteRating")
+	summary, err := t.next.DeleteRating(ctx, movieID, userID)
+	end(span, err)
+	return summary, err
+}
+
+func (t *tracedRatingRepository) GetUserRatings(ctx context.Context, userID int) ([]model.Rating, error) {
+	ctx, span := t.start(ctx, "GetUserRatings")
+	ratings, err := t.next.GetUserRatings(ctx, userID)
+	end(span, err)
+	return ratings, err
+}
+
+func (t *tracedRatingRepository) StreamRatings(ctx context.Context, fn func(rating model.Rating) error) error {
+	ctx, span := t.start(ctx, "StreamRatings")
+	err := t.next.StreamRatings(ctx, fn)
+	end(span, err)
+	return err
+}
+
+func (t *tracedRatingRepository) GetReviews(ctx context.Context, movieID int) ([]model.Review, error) {
+	ctx, span := t.start(ctx, "GetReviews")
+	reviews, err := t.next.GetReviews(ctx, movieID)
+	end(span, err)
+	return reviews, err
+}
+
+func (t *tracedRatingRepository) GetReviewsByMovies(ctx context.Context, movieIDs []int) (map[int][]model.Review, error) {
+	ctx, span := t.start(ctx, "GetReviewsByMovies")
+	reviews, err := t.next.GetReviewsByMovies(ctx, movieIDs)
+	end(span, err)
+	return reviews, err
+}
+
+func (t *tracedRatingRepository) AddReview(ctx context.Context, review model.Review) error {
+	ctx, span := t.start(ctx, "AddReview")
+	err := t.next.AddReview(ctx, review)
+	end(span, err)
+	return err
+}
+
+func (t *tracedRatingRepository) DeleteReview(ctx context.Context, movieID int, reviewID int) error {
+	ctx, span := t.start(ctx, "DeleteReview")
+	err := t.next.DeleteReview(ctx, movieID, reviewID)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedRecommendationRepository struct {
+	next     repository.IRecommendationRepository
+	dbSystem attribute.KeyValue
+}
+
+// NewRecommendationRepository decorates next like NewMovieRepository.
+func NewRecommendationRepository(next repository.IRecommendationRepository, dbSystem string) *tracedRecommendationRepository {
+	return &tracedRecommendationRepository{next: next, dbSystem: semconv.DBSystemKey.String(dbSystem)}
+}
+
+func (t *tracedRecommendationRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "IRecommendationRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.dbSystem, semconv.DBOperationKey.String(method)),
+	)
+}
+
+func (t *tracedRecommendationRepository) GetNeighbours(ctx context.Context, movieID int, limit int) ([]model.Neighbour, bool, error) {
+	ctx, span := t.start(ctx, "GetNeighbours")
+	neighbours, computed, err := t.next.GetNeighbours(ctx, movieID, limit)
+	end(span, err)
+	return neighbours, computed, err
+}
+
+func (t *tracedRecommendationRepository) ReplaceNeighbours(ctx context.Context, movieIDs []int, neighbours []model.Neighbour) error {
+	ctx, span := t.start(ctx, "ReplaceNeighbours")
+	err := t.next.ReplaceNeighbours(ctx, movieIDs, neighbours)
+	end(span, err)
+	return err
+}
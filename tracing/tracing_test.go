@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/handler"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/dilaragorum/movie-go/service"
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	assert.Nil(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mockRepository := repository.NewMockIMovieRepository(gomock.NewController(t))
+	mockRepository.EXPECT().GetMovie(gomock.Any(), 1).Return(model.Movie{}, repository.ErrMovieNotFound).Times(1)
+
+	ms := service.NewDefaultMovieService(NewMovieRepository(mockRepository, "postgresql"), nil, nil, nil, logging.NewNop())
+	router := httprouter.New()
+	router.GET("/movies/:id", Middleware("/movies/:id", handler.NewMovieHandler(ms, logging.NewNop()).GetMovie))
+
+	req, _ := http.NewRequest(http.MethodGet, "/movies/1", http.NoBody)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	repoSpan, serviceSpan, httpSpan := spans[0], spans[1], spans[2]
+	assert.Equal(t, "IMovieRepository.GetMovie", repoSpan.Name())
+	assert.Equal(t, "DefaultMovieService.GetMovie", serviceSpan.Name())
+	assert.Equal(t, "GET /movies/:id", httpSpan.Name())
+
+	for _, span := range spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	}
+	assert.Equal(t, "00f067aa0ba902b7", httpSpan.Parent().SpanID().String())
+	assert.Equal(t, httpSpan.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+	assert.Equal(t, serviceSpan.SpanContext().SpanID(), repoSpan.Parent().SpanID())
+
+	assert.Equal(t, codes.Error, repoSpan.Status().Code)
+	assert.Contains(t, httpSpan.Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusNotFound))
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("Error - unknown exporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), Config{Exporter: "jaeger"})
+		assert.ErrorIs(t, err, ErrUnknownExporter)
+	})
+	t.Run("Success - file exporter", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "traces.json")
+		shutdown, err := Setup(context.Background(), Config{ServiceName: "movie-go", Exporter: ExporterFile, FilePath: path})
+		assert.Nil(t, err)
+
+		_, span := otel.Tracer("test").Start(context.Background(), "GET /movies")
+		span.End()
+		assert.Nil(t, shutdown(context.Background()))
+
+		content, _ := os.ReadFile(path)
+		assert.True(t, strings.Contains(string(content), `"Name":"GET /movies"`))
+	})
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedWatchlistRepository struct {
+	next     repository.IWatchlistRepository
+	dbSystem attribute.KeyValue
+}
+
+// NewWatchlistRepository decorates next like NewMovieRepository.
+func NewWatchlistRepository(next repository.IWatchlistRepository, dbSystem string) *tracedWatchlistRepository {
+	return &tracedWatchlistRepository{next: next, dbSystem: semconv.DBSystemKey.String(dbSystem)}
+}
+
+func (t *tracedWatchlistRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "IWatchlistRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.dbSystem, semconv.DBOperationKey.String(method)),
+	)
+}
+
+func (t *tracedWatchlistRepository) GetWatchlist(ctx context.Context, userID int) ([]model.WatchlistItem, error) {
+	ctx, span := t.start(ctx, "GetWatchlist")
+	items, err := t.next.GetWatchlist(ctx, userID)
+	end(span, err)
+	return items, err
+}
+
+func (t *tracedWatchlistRepository) AddToWatchlist(ctx context.Context, userID int, movieID int) error {
+	ctx, span := t.start(ctx, "AddToWatchlist")
+	err := t.next.AddToWatchlist(ctx, userID, movieID)
+	end(span, err)
+	return err
+}
+
+func (t *tracedWatchlistRepository) RemoveFromWatchlist(ctx context.Context, userID int, movieID int) error {
+	ctx, span := t.start(ctx, "RemoveFromWatchlist")
+	err := t.next.RemoveFromWatchlist(ctx, userID, movieID)
+	end(span, err)
+	return err
+}
+
+func (t *tracedWatchlistRepository) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int) error {
+	ctx, span := t.start(ctx, "ReorderWatchlist")
+	err := t.next.ReorderWatchlist(ctx, userID, movieIDs)
+	end(span, err)
+	return err
+}
+
+func (t *tracedWatchlistRepository) GetWatched(ctx context.Context, userID int) ([]model.WatchedEntry, error) {
+	ctx, span := t.start(ctx, "GetWatched")
+	entries, err := t.next.GetWatched(ctx, userID)
+	end(span, err)
+	return entries, err
+}
+
+func (t *tracedWatchlistRepository) AddWatched(ctx context.Context, entry model.WatchedEntry) error {
+	ctx, span := t.start(ctx, "AddWatched")
+	err := t.next.AddWatched(ctx, entry)
+	end(span, err)
+	return err
+}
+
+func (t *tracedWatchlistRepository) DeleteWatched(ctx context.Context, userID int, entryID int) error {
+	ctx, span := t.start(ctx, "DeleteWatched")
+	err := t.next.DeleteWatched(ctx, userID, entryID)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+type tracedWebhookRepository struct {
+	next     repository.IWebhookRepository
+	dbSystem attribute.KeyValue
+}
+
+// NewWebhookRepository decorates next like NewMovieRepository.
+func NewWebhookRepository(next repository.IWebhookRepository, dbSystem string) *tracedWebhookRepository {
+	return &tracedWebhookRepository{next: next, dbSystem: semconv.DBSystemKey.String(dbSystem)}
+}
+
+func (t *tracedWebhookRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "IWebhookRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.dbSystem, semconv.DBOperationKey.String(method)),
+	)
+}
+
+func (t *tracedWebhookRepository) GetWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	ctx, span := t.start(ctx, "GetWebhooks")
+	webhooks, err := t.next.GetWebhooks(ctx)
+	end(span, err)
+	return webhooks, err
+}
+
+func (t *tracedWebhookRepository) GetWebhook(ctx context.Context, id int) (model.Webhook, error) {
+	ctx, span := t.start(ctx, "GetWebhook")
+	webhook, err := t.next.GetWebhook(ctx, id)
+	end(span, err)
+	return webhook, err
+}
+
+func (t *tracedWebhookRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	ctx, span := t.start(ctx, "CreateWebhook")
+	created, err := t.next.CreateWebhook(ctx, webhook)
+	end(span, err)
+	return created, err
+}
+
+func (t *tracedWebhookRepository) UpdateWebhook(ctx context.Context, id int, webhook model.Webhook) error {
+	ctx, span := t.start(ctx, "UpdateWebhook")
+	err := t.next.UpdateWebhook(ctx, id, webhook)
+	end(span, err)
+	return err
+}
+
+func (t *tracedWebhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	ctx, span := t.start(ctx, "DeleteWebhook")
+	err := t.next.DeleteWebhook(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (t *tracedWebhookRepository) EnqueueDeliveries(ctx context.Context, event model.Event, payload []byte, now time.Time) (int, error) {
+	ctx, span := t.start(ctx, "EnqueueDeliveries")
+	enqueued, err := t.next.EnqueueDeliveries(ctx, event, payload, now)
+	end(span, err)
+	return enqueued, err
+}
+
+func (t *tracedWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.DueDelivery, error) {
+	ctx, span := t.start(ctx, "ClaimDueDeliveries")
+	claimed, err := t.next.ClaimDueDeliveries(ctx, now, lease, limit)
+	end(span, err)
+	return claimed, err
+}
+
+func (t *tracedWebhookRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	ctx, span := t.start(ctx, "UpdateDelivery")
+	err := t.next.UpdateDelivery(ctx, delivery)
+	end(span, err)
+	return err
+}
+
+func (t *tracedWebhookRepository) GetDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus, limit int) ([]model.WebhookDelivery, error) {
+	ctx, span := t.start(ctx, "GetDeliveries")
+	deliveries, err := t.next.GetDeliveries(ctx, webhookID, status, limit)
+	end(span, err)
+	return deliveries, err
+}
+
+func (t *tracedWebhookRepository) RedeliverDelivery(ctx context.Context, webhookID int, deliveryID int64, now time.Time) error {
+	ctx, span := t.start(ctx, "RedeliverDelivery")
+	err := t.next.RedeliverDelivery(ctx, webhookID, deliveryID, now)
+	end(span, err)
+	return err
+}
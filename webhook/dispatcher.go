@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const maxErrorLength = 500
+
+type Config struct {
+	// MaxAttempts is how many times a delivery is tried before it is dead-lettered.
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failed attempt, it doubles after every
+	// other one up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// BatchSize is how many deliveries are claimed at once.
+	BatchSize int
+	// Lease keeps claimed deliveries from other dispatchers, it must be longer than
+	// sending a whole batch takes.
+	Lease time.Duration
+}
+
+// DefaultConfig retries for about four hours before giving up on a delivery.
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:    10,
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     2 * time.Hour,
+		BatchSize:      20,
+		Lease:          5 * time.Minute,
+	}
+}
+
+// Dispatcher sends the catalog events to the subscribed webhooks. Enqueue records a
+// delivery per webhook and DeliverDue sends them, retrying the failed ones with an
+// exponential backoff.
+type Dispatcher struct {
+	repo   repository.IWebhookRepository
+	client *http.Client
+	config Config
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewDispatcher sends the deliveries with client, whose timeout bounds an attempt.
+func NewDispatcher(repo repository.IWebhookRepository, client *http.Client, config Config, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: client,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Enqueue is an events.Handler: subscribed to the bus, it records the deliveries of
+// every published event. An event published again is not delivered twice.
+func (d *Dispatcher) Enqueue(ctx context.Context, event model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	enqueued, err := d.repo.EnqueueDeliveries(ctx, event, payload, d.now())
+	if err != nil {
+		return fmt.Errorf("enqueuing webhook deliveries of event %d: %w", event.ID, err)
+	}
+	if enqueued > 0 {
+		d.logger.DebugContext(ctx, "webhook deliveries enqueued", "event_id", event.ID, "count", enqueued)
+	}
+	return nil
+}
+
+// DeliverDue sends the deliveries whose attempt is due, batch after batch. It returns
+// the first error saving an outcome; a delivery whose outcome is lost is tried again
+// once its lease runs out.
+func (d *Dispatcher) DeliverDue(ctx context.Context) error {
+	for {
+		claimed, err := d.repo.ClaimDueDeliveries(ctx, d.now(), d.config.Lease, d.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, due := range claimed {
+			delivery := d.attempt(ctx, due)
+			// A cancelled attempt says nothing about the receiver, it is not counted.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+				return fmt.Errorf("saving webhook delivery %d: %w", delivery.ID, err)
+			}
+		}
+
+		if len(claimed) < d.config.BatchSize {
+			return nil
+		}
+	}
+}
+
+// attempt sends the delivery once and returns it with the outcome: succeeded, pending
+// for a retry, or dead after the last attempt.
+func (d *Dispatcher) attempt(ctx context.Context, due model.DueDelivery) model.WebhookDelivery {
+	delivery := due.Delivery
+	delivery.Attempts++
+
+	statusCode, err := d.send(ctx, due)
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = model.DeliverySucceeded
+		delivery.LastError = ""
+		d.logger.DebugContext(ctx, "webhook delivered",
+			"webhook_id", delivery.WebhookID, "delivery_id", delivery.ID, "attempts", delivery.Attempts)
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > maxErrorLength {
+		delivery.LastError = delivery.LastError[:maxErrorLength]
+	}
+
+	if delivery.Attempts >= d.config.MaxAttempts {
+		delivery.Status = model.DeliveryDead
+		d.logger.ErrorContext(ctx, "webhook delivery dead-lettered",
+			"webhook_id", delivery.WebhookID, "delivery_id", delivery.ID, "attempts", delivery.Attempts, "error", err)
+		return delivery
+	}
+
+	delivery.Status = model.DeliveryPending
+	delivery.NextAttemptAt = d.now().Add(d.backoff(delivery.Attempts))
+	d.logger.WarnContext(ctx, "webhook delivery failed",
+		"webhook_id", delivery.WebhookID, "delivery_id", delivery.ID, "attempts", delivery.Attempts,
+		"next_attempt_at", delivery.NextAttemptAt, "error", err)
+	return delivery
+}
+
+// send posts the signed payload and returns the status code of the response, if any.
+func (d *Dispatcher) send(ctx context.Context, due model.DueDelivery) (int, error) {
+	delivery := due.Delivery
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, due.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "movie-go-webhooks")
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(events.EventIDHeader, strconv.FormatInt(delivery.EventID, 10))
+	req.Header.Set(events.EventTypeHeader, string(delivery.EventType))
+	req.Header.Set(SignatureHeader, Sign(due.Secret, d.now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.config.InitialBackoff
+	for k := 1; k < attempts && wait < d.config.MaxBackoff; k++ {
+		wait *= 2
+	}
+	if wait > d.config.MaxBackoff {
+		wait = d.config.MaxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dilaragorum/movie-go/events"
+	"github.com/dilaragorum/movie-go/logging"
+	"github.com/dilaragorum/movie-go/model"
+	"github.com/dilaragorum/movie-go/repository"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+// receiver is a webhook endpoint answering status and checking the signatures.
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	received []model.Event
+	invalid  int
+}
+
+func newReceiver(t *testing.T, now *time.Time) (*receiver, *httptest.Server) {
+	r := &receiver{status: http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		body, _ := io.ReadAll(req.Body)
+		if err := Verify(testSecret, req.Header.Get(SignatureHeader), body, *now, DefaultTolerance); err != nil {
+			r.invalid++
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event model.Event
+		json.Unmarshal(body, &event)
+		assert.Equal(t, string(event.Type), req.Header.Get(events.EventTypeHeader))
+		assert.NotEmpty(t, req.Header.Get(DeliveryHeader))
+		r.received = append(r.received, event)
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func newTestDispatcher(t *testing.T, url string, events []model.EventType, now *time.Time) (*Dispatcher, repository.IWebhookRepository) {
+	repo := repository.NewInMemoryWebhookRepository(logging.NewNop())
+	_, err := repo.CreateWebhook(context.Background(), model.Webhook{URL: url, Events: events, Secret: testSecret, Active: true})
+	assert.Nil(t, err)
+
+	config := Config{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour, BatchSize: 10, Lease: time.Minute}
+	d := NewDispatcher(repo, http.DefaultClient, config, logging.NewNop())
+	d.now = func() time.Time { return *now }
+	return d, repo
+}
+
+func testEvent(id int64, eventType model.EventType) model.Event {
+	event := model.NewMovieEvent(eventType, model.Movie{ID: 7, Title: "Heat"})
+	event.ID = id
+	return event
+}
+
+func TestDispatcher_DeliverDue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - signed payload is delivered once", func(t *testing.T) {
+		now := time.Date(2022, 9, 24, 20, 30, 0, 0, time.UTC)
+		r, server := newReceiver(t, &now)
+		d, repo := newTestDispatcher(t, server.URL, nil, &now)
+
+		assert.Nil(t, d.Enqueue(ctx, testEvent(1, model.EventMovieCreated)))
+		// Published again by the relay, the event is not enqueued twice.
+		assert.Nil(t, d.Enqueue(ctx, testEvent(1, model.EventMovieCreated)))
+		assert.Nil(t, d.DeliverDue(ctx))
+		assert.Nil(t, d.DeliverDue(ctx))
+
+		assert.Equal(t, 0, r.invalid)
+		assert.Len(t, r.received, 1)
+		assert.Equal(t, "Heat", r.received[0].Movie.Title)
+
+		deliveries, _ := repo.GetDeliveries(ctx, 1, "", 10)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, model.DeliverySucceeded, deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusOK, deliveries[0].LastStatusCode)
+	})
+	t.Run("Subscribed events only", func(t *testing.T) {
+		now := time.Date(2022, 9, 24, 20, 30, 0, 0, time.UTC)
+		r, server := newReceiver(t, &now)
+		d, _ := newTestDispatcher(t, server.URL, []model.EventType{model.EventMovieDeleted}, &now)
+
+		d.Enqueue(ctx, testEvent(1, model.EventMovieCreated))
+		d.Enqueue(ctx, model.NewMovieDeletedEvent(7))
+		assert.Nil(t, d.DeliverDue(ctx))
+
+		assert.Len(t, r.received, 1)
+		assert.Equal(t, model.EventMovieDeleted, r.received[0].Type)
+	})
+	t.Run("Failing receiver - retried with backoff, then dead-lettered", func(t *testing.T) {
+		now := time.Date(2022, 9, 24, 20, 30, 0, 0, time.UTC)
+		r, server := newReceiver(t, &now)
+		r.status = http.StatusServiceUnavailable
+		d, repo := newTestDispatcher(t, server.URL, nil, &now)
+
+		d.Enqueue(ctx, testEvent(1, model.EventMovieUpdated))
+		assert.Nil(t, d.DeliverDue(ctx))
+
+		deliveries, _ := repo.GetDeliveries(ctx, 1, model.DeliveryPending, 10)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].LastStatusCode)
+		assert.Equal(t, now.Add(time.Minute), deliveries[0].NextAttemptAt)
+
+		// Not due yet.
+		now = now.Add(59 * time.Second)
+		assert.Nil(t, d.DeliverDue(ctx))
+		assert.Len(t, r.received, 1)
+
+		now = now.Add(time.Second)
+		assert.Nil(t, d.DeliverDue(ctx))
+		deliveries, _ = repo.GetDeliveries(ctx, 1, "", 10)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Equal(t, now.Add(2*time.Minute), deliveries[0].NextAttemptAt)
+
+		now = now.Add(2 * time.Minute)
+		assert.Nil(t, d.DeliverDue(ctx))
+		dead, _ := repo.GetDeliveries(ctx, 1, model.DeliveryDead, 10)
+		assert.Len(t, dead, 1)
+		assert.Equal(t, 3, dead[0].Attempts)
+		assert.Equal(t, "webhook answered 503 Service Unavailable", dead[0].LastError)
+
+		// Dead letters are not retried until they are redelivered.
+		now = now.Add(time.Hour)
+		assert.Nil(t, d.DeliverDue(ctx))
+		assert.Len(t, r.received, 3)
+
+		r.status = http.StatusNoContent
+		assert.Nil(t, repo.RedeliverDelivery(ctx, 1, dead[0].ID, now))
+		assert.Nil(t, d.DeliverDue(ctx))
+		deliveries, _ = repo.GetDeliveries(ctx, 1, model.DeliverySucceeded, 10)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+	})
+	t.Run("Unreachable receiver - attempt fails", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+		now := time.Now()
+		d, repo := newTestDispatcher(t, url, nil, &now)
+
+		d.Enqueue(ctx, testEvent(1, model.EventMovieCreated))
+		assert.Nil(t, d.DeliverDue(ctx))
+
+		deliveries, _ := repo.GetDeliveries(ctx, 1, model.DeliveryPending, 10)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, 0, deliveries[0].LastStatusCode)
+		assert.NotEmpty(t, deliveries[0].LastError)
+	})
+}
+
+func TestDispatcher_backoff(t *testing.T) {
+	d := NewDispatcher(nil, nil, Config{InitialBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}, logging.NewNop())
+
+	assert.Equal(t, 30*time.Second, d.backoff(1))
+	assert.Equal(t, time.Minute, d.backoff(2))
+	assert.Equal(t, 4*time.Minute, d.backoff(4))
+	assert.Equal(t, 5*time.Minute, d.backoff(5))
+	assert.Equal(t, 5*time.Minute, d.backoff(60))
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the signature of the payload, see Sign.
+	SignatureHeader = "X-Webhook-Signature"
+	// DeliveryHeader carries the ID of the delivery, which is the same on every attempt.
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	// DefaultTolerance is how old a signature Verify accepts by default.
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrSignatureNotValid = errors.New("webhook signature does not match the payload")
+	ErrSignatureExpired  = errors.New("webhook signature is too old")
+)
+
+// Sign returns the signature header of body sent at timestamp, in the form
+// "t=<unix seconds>,v1=<hex HMAC-SHA256>". The HMAC covers "<unix seconds>.<body>" so
+// that a captured payload cannot be replayed with a fresh timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac(secret, t, body))
+}
+
+// Verify checks the signature header of body, as receivers do: the HMAC must match
+// and the timestamp must be at most tolerance away from now.
+func Verify(secret string, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var t string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			t = kv[1]
+		case "v1":
+			if signature, err := hex.DecodeString(kv[1]); err == nil {
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrSignatureNotValid
+	}
+
+	expected := mac(secret, t, body)
+	valid := false
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrSignatureNotValid
+	}
+
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func mac(secret string, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	sentAt := time.Unix(1664051400, 0)
+	body := []byte(`{"id":1}`)
+	header := Sign("whsec_test", sentAt, body)
+
+	testCases := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		err    error
+	}{
+		{name: "Valid", secret: "whsec_test", header: header, body: body, now: sentAt.Add(time.Minute)},
+		{name: "Other secret", secret: "whsec_other", header: header, body: body, now: sentAt, err: ErrSignatureNotValid},
+		{name: "Tampered body", secret: "whsec_test", header: header, body: []byte(`{"id":2}`), now: sentAt, err: ErrSignatureNotValid},
+		{name: "Malformed header", secret: "whsec_test", header: "v1=abc", body: body, now: sentAt, err: ErrSignatureNotValid},
+		{name: "Replayed", secret: "whsec_test", header: header, body: body, now: sentAt.Add(time.Hour), err: ErrSignatureExpired},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify(test.secret, test.header, test.body, test.now, DefaultTolerance)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}